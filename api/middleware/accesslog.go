@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+)
+
+// RequestIDHeader carries the request id in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds a client-supplied id, which ends up in every log line.
+const maxRequestIDLength = 128
+
+// AccessLogMiddleware assigns each request an id, honoring a well-formed X-Request-ID, echoes
+// it in the response and stores a logger carrying it in the context. Once the handler returns it
+// writes one access log entry. Only sampleRate of the successful requests are logged; client
+// and server errors always are.
+func AccessLogMiddleware(logger *slog.Logger, routes RouteMatcher, sampleRate float64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			id := req.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			reqLogger := logger.With("request_id", id)
+			ctx := logging.WithRequestID(logging.NewContext(req.Context(), reqLogger), id)
+			sw := newStatusWriter(w)
+			// deferred so aborted streams are still logged
+			defer func() {
+				level := slog.LevelInfo
+				switch {
+				case sw.code >= http.StatusInternalServerError:
+					level = slog.LevelError
+				case sw.code >= http.StatusBadRequest:
+					level = slog.LevelWarn
+				case sampleRate < 1 && rand.Float64() >= sampleRate:
+					return
+				}
+				reqLogger.LogAttrs(ctx, level, "access",
+					slog.String("route", routePattern(routes, req)),
+					slog.String("method", req.Method),
+					slog.String("path", req.URL.Path),
+					slog.Int("status", sw.code),
+					slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+					slog.Int64("bytes", sw.bytes),
+					slog.String("remote_ip", remoteIP(req)),
+					slog.String("user_agent", req.UserAgent()),
+				)
+			}()
+
+			next.ServeHTTP(sw, req.WithContext(ctx))
+		})
+	}
+}
+
+// validRequestID accepts ids made of visible ASCII, so they cannot forge log fields.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = cryptorand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
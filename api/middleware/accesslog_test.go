@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccessLogMiddlewareSuite struct {
+	suite.Suite
+	buf bytes.Buffer
+	mux *http.ServeMux
+}
+
+func TestAccessLogMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(AccessLogMiddlewareSuite))
+}
+
+func (suite *AccessLogMiddlewareSuite) SetupTest() {
+	suite.buf.Reset()
+	suite.mux = http.NewServeMux()
+	suite.mux.HandleFunc("GET /singers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).InfoContext(r.Context(), "in handler", "handler_request_id", logging.RequestID(r.Context()))
+		if r.PathValue("id") == "0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// no WriteHeader, so net/http sends 200
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+}
+
+func (suite *AccessLogMiddlewareSuite) serve(sampleRate float64, req *http.Request) *httptest.ResponseRecorder {
+	logger := slog.New(slog.NewJSONHandler(&suite.buf, nil))
+	rr := httptest.NewRecorder()
+	middleware.AccessLogMiddleware(logger, suite.mux, sampleRate)(suite.mux).ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *AccessLogMiddlewareSuite) entries() []map[string]any {
+	var entries []map[string]any
+	dec := json.NewDecoder(&suite.buf)
+	for dec.More() {
+		var entry map[string]any
+		suite.Require().NoError(dec.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (suite *AccessLogMiddlewareSuite) TestOneEntryPerRequest() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/1", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.RemoteAddr = "192.0.2.1:54321"
+	rr := suite.serve(1, req)
+
+	suite.Equal("req-123", rr.Header().Get("X-Request-ID"))
+	entries := suite.entries()
+	suite.Require().Len(entries, 2)
+
+	handler, access := entries[0], entries[1]
+	suite.Equal("req-123", handler["request_id"])
+	suite.Equal("req-123", handler["handler_request_id"])
+
+	suite.Equal("access", access["msg"])
+	suite.Equal("INFO", access["level"])
+	suite.Equal("req-123", access["request_id"])
+	suite.Equal("/singers/{id}", access["route"])
+	suite.Equal("GET", access["method"])
+	suite.Equal(float64(http.StatusOK), access["status"])
+	suite.Equal(float64(len(`{"id":1}`)), access["bytes"])
+	suite.Equal("192.0.2.1", access["remote_ip"])
+	suite.Equal("curl/8.0", access["user_agent"])
+	suite.Contains(access, "duration_ms")
+}
+
+func (suite *AccessLogMiddlewareSuite) TestGeneratesRequestID() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/1", nil)
+	// ids that could forge log fields are replaced
+	req.Header.Set("X-Request-ID", "evil\nlevel=ERROR")
+	rr := suite.serve(1, req)
+
+	id := rr.Header().Get("X-Request-ID")
+	suite.Len(id, 32)
+	entries := suite.entries()
+	suite.Require().Len(entries, 2)
+	suite.Equal(id, entries[1]["request_id"])
+}
+
+func (suite *AccessLogMiddlewareSuite) TestSamplingKeepsErrors() {
+	suite.serve(0, httptest.NewRequest(http.MethodGet, "/singers/1", nil))
+	suite.serve(0, httptest.NewRequest(http.MethodGet, "/singers/0", nil))
+	suite.serve(0, httptest.NewRequest(http.MethodGet, "/no/such/path", nil))
+
+	var access []map[string]any
+	for _, entry := range suite.entries() {
+		if entry["msg"] == "access" {
+			access = append(access, entry)
+		}
+	}
+	suite.Require().Len(access, 2)
+	suite.Equal("WARN", access[0]["level"])
+	suite.Equal(float64(http.StatusBadRequest), access[0]["status"])
+	suite.Equal("unmatched", access[1]["route"])
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// Public marks a route that needs no credentials.
+const Public model.Role = ""
+
+// AuthPolicy maps route patterns, e.g. "DELETE /singers/{id}", to the role they require. A route
+// missing from the policy is admin-only, so a forgotten entry fails closed.
+type AuthPolicy map[string]model.Role
+
+// AuthMiddleware authenticates requests by their X-API-Key header or Authorization bearer token
+// and stores the principal in the context. Missing or invalid credentials get 401, a role below
+// the one the route requires gets 403. Unknown paths pass on so they still get 404 or 405.
+func AuthMiddleware(authService service.AuthService, routes RouteMatcher, policy AuthPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			pattern := matchedRoute(routes, req)
+			required, ok := policy[pattern]
+			if !ok {
+				required = model.RoleAdmin
+			}
+			if pattern == "" || required == Public {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			principal, err := authenticate(authService, req)
+			if err != nil {
+				if model.KindOf(err) == model.KindUnauthenticated {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				}
+				controller.WriteError(w, req, err)
+				return
+			}
+			if !principal.Role.Allows(required) {
+				controller.WriteError(w, req, model.NewError(model.KindForbidden, "this route requires the "+string(required)+" role", nil))
+				return
+			}
+
+			ctx := auth.NewContext(req.Context(), principal)
+			ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("principal", principal.Subject))
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+var errNoCredentials = model.NewError(model.KindUnauthenticated, "an X-API-Key header or a bearer token is required", nil)
+
+func authenticate(authService service.AuthService, req *http.Request) (*model.Principal, error) {
+	if key := req.Header.Get(apiKeyHeader); key != "" {
+		return authService.AuthenticateAPIKeyService(req.Context(), key)
+	}
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") && token != "" {
+		return authService.AuthenticateTokenService(req.Context(), strings.TrimSpace(token))
+	}
+	return nil, errNoCredentials
+}
@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/suite"
+)
+
+type stubAuthService struct {
+	apiKeys map[string]*model.Principal
+	tokens  map[string]*model.Principal
+}
+
+func (s *stubAuthService) AuthenticateAPIKeyService(ctx context.Context, key string) (*model.Principal, error) {
+	if p, ok := s.apiKeys[key]; ok {
+		return p, nil
+	}
+	return nil, service.ErrorInvalidAPIKey
+}
+
+func (s *stubAuthService) AuthenticateTokenService(ctx context.Context, token string) (*model.Principal, error) {
+	if p, ok := s.tokens[token]; ok {
+		return p, nil
+	}
+	return nil, service.ErrorInvalidToken
+}
+
+type AuthMiddlewareSuite struct {
+	suite.Suite
+	handler http.Handler
+	// principal is the one seen by the last handler call
+	principal *model.Principal
+}
+
+func TestAuthMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(AuthMiddlewareSuite))
+}
+
+func (suite *AuthMiddlewareSuite) SetupTest() {
+	suite.principal = nil
+	mux := http.NewServeMux()
+	record := func(w http.ResponseWriter, r *http.Request) {
+		suite.principal, _ = auth.FromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}
+	mux.HandleFunc("GET /singers", record)
+	mux.HandleFunc("DELETE /singers/{id}", record)
+	mux.HandleFunc("GET /trash", record)
+	mux.HandleFunc("GET /healthz", record)
+	mux.HandleFunc("GET /forgotten", record)
+
+	policy := middleware.AuthPolicy{
+		"GET /singers":         model.RoleViewer,
+		"DELETE /singers/{id}": model.RoleEditor,
+		"GET /trash":           model.RoleAdmin,
+		"GET /healthz":         middleware.Public,
+	}
+	authService := &stubAuthService{
+		apiKeys: map[string]*model.Principal{
+			"viewer-key": {Subject: "api_key:viewer", Role: model.RoleViewer, Method: model.AuthMethodAPIKey},
+		},
+		tokens: map[string]*model.Principal{
+			"editor-token": {Subject: "alice", Role: model.RoleEditor, Method: model.AuthMethodJWT},
+			"admin-token":  {Subject: "root", Role: model.RoleAdmin, Method: model.AuthMethodJWT},
+		},
+	}
+	suite.handler = middleware.AuthMiddleware(authService, mux, policy)(mux)
+}
+
+func (suite *AuthMiddlewareSuite) serve(method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	suite.handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *AuthMiddlewareSuite) problem(rr *httptest.ResponseRecorder) controller.Problem {
+	var problem controller.Problem
+	suite.NoError(json.NewDecoder(rr.Body).Decode(&problem))
+	return problem
+}
+
+func (suite *AuthMiddlewareSuite) TestMissingCredentials() {
+	rr := suite.serve(http.MethodDelete, "/singers/1", nil)
+
+	suite.Equal(http.StatusUnauthorized, rr.Code)
+	suite.Equal(`Bearer realm="api"`, rr.Header().Get("WWW-Authenticate"))
+	suite.Equal("/problems/unauthorized", suite.problem(rr).Type)
+	suite.Nil(suite.principal)
+}
+
+func (suite *AuthMiddlewareSuite) TestInvalidCredentials() {
+	rr := suite.serve(http.MethodGet, "/singers", map[string]string{"X-API-Key": "guess"})
+	suite.Equal(http.StatusUnauthorized, rr.Code)
+
+	rr = suite.serve(http.MethodGet, "/singers", map[string]string{"Authorization": "Bearer forged"})
+	suite.Equal(http.StatusUnauthorized, rr.Code)
+	suite.Equal("invalid bearer token", suite.problem(rr).Detail)
+}
+
+func (suite *AuthMiddlewareSuite) TestRoles() {
+	rr := suite.serve(http.MethodGet, "/singers", map[string]string{"X-API-Key": "viewer-key"})
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.Equal("api_key:viewer", suite.principal.Subject)
+
+	rr = suite.serve(http.MethodDelete, "/singers/1", map[string]string{"X-API-Key": "viewer-key"})
+	suite.Equal(http.StatusForbidden, rr.Code)
+	problem := suite.problem(rr)
+	suite.Equal("/problems/forbidden", problem.Type)
+	suite.Equal("this route requires the editor role", problem.Detail)
+
+	rr = suite.serve(http.MethodDelete, "/singers/1", map[string]string{"Authorization": "Bearer editor-token"})
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.Equal("alice", suite.principal.Subject)
+
+	rr = suite.serve(http.MethodGet, "/trash", map[string]string{"Authorization": "Bearer editor-token"})
+	suite.Equal(http.StatusForbidden, rr.Code)
+	rr = suite.serve(http.MethodGet, "/trash", map[string]string{"Authorization": "bearer admin-token"})
+	suite.Equal(http.StatusNoContent, rr.Code)
+}
+
+func (suite *AuthMiddlewareSuite) TestRoutesWithoutPolicyAreAdminOnly() {
+	rr := suite.serve(http.MethodGet, "/forgotten", map[string]string{"Authorization": "Bearer editor-token"})
+	suite.Equal(http.StatusForbidden, rr.Code)
+	rr = suite.serve(http.MethodGet, "/forgotten", map[string]string{"Authorization": "Bearer admin-token"})
+	suite.Equal(http.StatusNoContent, rr.Code)
+}
+
+func (suite *AuthMiddlewareSuite) TestPublicAndUnknownRoutes() {
+	rr := suite.serve(http.MethodGet, "/healthz", nil)
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.Nil(suite.principal)
+
+	rr = suite.serve(http.MethodGet, "/no/such/path", nil)
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
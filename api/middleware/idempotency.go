@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with an idempotent response.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+type recordingWriter struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	rw.code = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// IdempotencyMiddleware makes POST requests carrying an Idempotency-Key safe to retry. The
+// first response for a key is stored for ttl and replayed to retries of the same request;
+// reusing the key for a different request is rejected with 422. Keys are scoped to the
+// authenticated principal. Responses with a 5xx status are not stored, so the request can be
+// retried for real.
+func IdempotencyMiddleware(repo repository.IdempotencyRepository, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			key := req.Header.Get(idempotencyKeyHeader)
+			if req.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, req)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				controller.WriteProblem(w, req, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					controller.WriteProblem(w, req, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+					return
+				}
+				controller.WriteProblem(w, req, http.StatusBadRequest, "request body could not be read")
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			record := &model.IdempotencyRecord{
+				Key:         key,
+				Fingerprint: fingerprint(req, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+			if principal, ok := auth.FromContext(req.Context()); ok {
+				record.Subject = principal.Subject
+			}
+			existing, err := repo.Reserve(req.Context(), record)
+			if err != nil {
+				controller.WriteError(w, req, err)
+				return
+			}
+			if existing != nil {
+				switch {
+				case existing.Fingerprint != record.Fingerprint:
+					controller.WriteProblem(w, req, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				case !existing.Completed():
+					controller.WriteError(w, req, repository.ErrorIdempotencyKeyInUse)
+				default:
+					replay(w, req, existing)
+				}
+				return
+			}
+
+			// The outcome is saved even if the client has gone away in the meantime.
+			ctx := context.WithoutCancel(req.Context())
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := repo.Release(ctx, record.Subject, key); err != nil {
+					logging.FromContext(ctx).ErrorContext(ctx, "failed to release idempotency key", "error", err)
+				}
+			}()
+
+			rw := &recordingWriter{ResponseWriter: w, code: http.StatusOK}
+			next.ServeHTTP(rw, req)
+			if rw.code >= http.StatusInternalServerError {
+				return
+			}
+
+			record.StatusCode = rw.code
+			record.Header = make(map[string]string)
+			for _, name := range replayedHeaders {
+				if v := w.Header().Get(name); v != "" {
+					record.Header[name] = v
+				}
+			}
+			record.Body = rw.body.Bytes()
+			if err := repo.Complete(ctx, record); err != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "failed to store idempotent response", "error", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// fingerprint identifies a request by its method, target and body.
+func fingerprint(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, req *http.Request, record *model.IdempotencyRecord) {
+	for name, value := range record.Header {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(record.StatusCode)
+	if _, err := w.Write(record.Body); err != nil {
+		logging.FromContext(req.Context()).ErrorContext(req.Context(), "failed to write replayed response", "error", err)
+	}
+}
+
+// RunIdempotencyPurger deletes expired idempotency records every interval until ctx is done.
+func RunIdempotencyPurger(ctx context.Context, repo repository.IdempotencyRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := repo.Purge(ctx, time.Now()); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to purge idempotency keys", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyMiddlewareSuite struct {
+	suite.Suite
+	repo    repository.IdempotencyRepository
+	calls   int
+	status  int
+	handler http.Handler
+}
+
+func TestIdempotencyMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyMiddlewareSuite))
+}
+
+func (suite *IdempotencyMiddlewareSuite) SetupTest() {
+	suite.repo = repository.NewInMemoryIdempotencyRepository()
+	suite.calls = 0
+	suite.status = http.StatusCreated
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/albums/7")
+		w.WriteHeader(suite.status)
+		_, _ = w.Write([]byte(`{"id":7}`))
+	})
+	suite.handler = middleware.IdempotencyMiddleware(suite.repo, time.Hour)(next)
+}
+
+func (suite *IdempotencyMiddlewareSuite) post(key, body string) *httptest.ResponseRecorder {
+	return suite.postAs(nil, key, body)
+}
+
+func (suite *IdempotencyMiddlewareSuite) postAs(principal *model.Principal, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	if principal != nil {
+		req = req.WithContext(auth.NewContext(req.Context(), principal))
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rr := httptest.NewRecorder()
+	suite.handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestReplay() {
+	first := suite.post("key-1", `{"title":"A","singer_id":1}`)
+	suite.Equal(http.StatusCreated, first.Code)
+	suite.Empty(first.Header().Get("Idempotent-Replayed"))
+
+	second := suite.post("key-1", `{"title":"A","singer_id":1}`)
+	suite.Equal(http.StatusCreated, second.Code)
+	suite.Equal("true", second.Header().Get("Idempotent-Replayed"))
+	suite.Equal("/albums/7", second.Header().Get("Location"))
+	suite.Equal("application/json", second.Header().Get("Content-Type"))
+	suite.JSONEq(`{"id":7}`, second.Body.String())
+	suite.Equal(1, suite.calls)
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestKeyScopedToPrincipal() {
+	alice := &model.Principal{Subject: "api_key:alice", Role: model.RoleEditor, Method: model.AuthMethodAPIKey}
+	bob := &model.Principal{Subject: "api_key:bob", Role: model.RoleEditor, Method: model.AuthMethodAPIKey}
+
+	first := suite.postAs(alice, "key-1", `{"title":"A","singer_id":1}`)
+	suite.Equal(http.StatusCreated, first.Code)
+
+	// Bob's request with the same key and body is his own, not a replay of Alice's.
+	second := suite.postAs(bob, "key-1", `{"title":"A","singer_id":1}`)
+	suite.Equal(http.StatusCreated, second.Code)
+	suite.Empty(second.Header().Get("Idempotent-Replayed"))
+	suite.Equal(2, suite.calls)
+
+	retry := suite.postAs(alice, "key-1", `{"title":"A","singer_id":1}`)
+	suite.Equal("true", retry.Header().Get("Idempotent-Replayed"))
+	suite.Equal(2, suite.calls)
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestDifferentBody() {
+	suite.post("key-1", `{"title":"A","singer_id":1}`)
+
+	rr := suite.post("key-1", `{"title":"B","singer_id":1}`)
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+	suite.Equal(1, suite.calls)
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestInProgress() {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{}`))
+		req.Header.Set("Idempotency-Key", "key-1")
+		return req
+	}
+
+	// The retry arrives while the first request is still being handled.
+	var retry *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = middleware.IdempotencyMiddleware(suite.repo, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if retry == nil {
+			retry = httptest.NewRecorder()
+			handler.ServeHTTP(retry, newRequest())
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest())
+
+	suite.Equal(http.StatusCreated, rr.Code)
+	suite.Equal(http.StatusConflict, retry.Code)
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestServerErrorIsNotStored() {
+	suite.status = http.StatusServiceUnavailable
+	suite.post("key-1", `{}`)
+
+	suite.status = http.StatusCreated
+	rr := suite.post("key-1", `{}`)
+	suite.Equal(http.StatusCreated, rr.Code)
+	suite.Equal(2, suite.calls)
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestPassThrough() {
+	suite.post("", `{}`)
+	suite.post("", `{}`)
+
+	req := httptest.NewRequest(http.MethodGet, "/albums", nil)
+	req.Header.Set("Idempotency-Key", "key-1")
+	suite.handler.ServeHTTP(httptest.NewRecorder(), req)
+	suite.handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	suite.Equal(4, suite.calls)
+}
+
+func (suite *IdempotencyMiddlewareSuite) TestKeyTooLong() {
+	rr := suite.post(strings.Repeat("k", 256), `{}`)
+	suite.Equal(http.StatusBadRequest, rr.Code)
+	suite.Equal(0, suite.calls)
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+)
+
+// matchedRoute returns the full pattern that serves r, e.g. "POST /imports", or "" if none.
+func matchedRoute(routes RouteMatcher, r *http.Request) string {
+	_, pattern := routes.Handler(r)
+	return pattern
+}
+
+// TimeoutMiddleware gives every request a deadline, so database calls made with its context
+// are cancelled once it passes. overrides replaces the timeout of single routes, keyed by their
+// full pattern; 0 leaves a route without deadline.
+func TimeoutMiddleware(routes RouteMatcher, timeout time.Duration, overrides map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			d, ok := overrides[matchedRoute(routes, req)]
+			if !ok {
+				d = timeout
+			}
+			if d > 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), d)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// BodyLimitMiddleware caps request bodies at limit bytes, or the override of the route, so a
+// handler reading past it fails with an *http.MaxBytesError and answers 413. A declared length
+// over the limit is rejected before the handler runs.
+func BodyLimitMiddleware(routes RouteMatcher, limit int64, overrides map[string]int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			n, ok := overrides[matchedRoute(routes, req)]
+			if !ok {
+				n = limit
+			}
+			if req.ContentLength > n {
+				controller.WriteProblem(w, req, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", n))
+				return
+			}
+			req.Body = http.MaxBytesReader(w, req.Body, n)
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// ConcurrencyLimitMiddleware lets at most limit requests to the given routes run at once; the
+// routes share the slots. Requests over the limit are answered with 503 right away rather than
+// queued, since the requests it guards run for long.
+func ConcurrencyLimitMiddleware(routes RouteMatcher, limit int, patterns ...string) func(http.Handler) http.Handler {
+	slots := make(chan struct{}, limit)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if !slices.Contains(patterns, matchedRoute(routes, req)) {
+				next.ServeHTTP(w, req)
+				return
+			}
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				next.ServeHTTP(w, req)
+			default:
+				w.Header().Set("Retry-After", concurrencyRetryAfter)
+				controller.WriteProblem(w, req, http.StatusServiceUnavailable, fmt.Sprintf("at most %d of these requests can run at once", limit))
+			}
+		})
+	}
+}
+
+// concurrencyRetryAfter is the number of seconds a client turned away by
+// ConcurrencyLimitMiddleware is asked to wait.
+const concurrencyRetryAfter = "10"
+
+// JSONContentTypeMiddleware answers 415 to POST, PUT and PATCH requests whose body is not JSON.
+// Requests without a body pass, as do the exempt routes, which negotiate their own formats.
+func JSONContentTypeMiddleware(routes RouteMatcher, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch:
+			default:
+				next.ServeHTTP(w, req)
+				return
+			}
+			if req.ContentLength == 0 || isJSON(req.Header.Get("Content-Type")) || slices.Contains(exempt, matchedRoute(routes, req)) {
+				next.ServeHTTP(w, req)
+				return
+			}
+			controller.WriteProblem(w, req, http.StatusUnsupportedMediaType, "request body must be application/json")
+		})
+	}
+}
+
+// isJSON accepts application/json and structured suffixes such as application/merge-patch+json.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,156 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimitsMiddlewareSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+	// deadline is the context deadline seen by the last handler call
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func TestLimitsMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(LimitsMiddlewareSuite))
+}
+
+func (suite *LimitsMiddlewareSuite) SetupTest() {
+	suite.mux = http.NewServeMux()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		suite.deadline, suite.hasDeadline = r.Context().Deadline()
+		if _, err := io.ReadAll(r.Body); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	suite.mux.HandleFunc("POST /singers", handler)
+	suite.mux.HandleFunc("PATCH /singers/{id}", handler)
+	suite.mux.HandleFunc("POST /singers/{id}", handler)
+	suite.mux.HandleFunc("POST /imports", handler)
+	suite.mux.HandleFunc("GET /exports/albums", handler)
+}
+
+func (suite *LimitsMiddlewareSuite) serve(h func(http.Handler) http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	h(suite.mux).ServeHTTP(rr, req)
+	return rr
+}
+
+func (suite *LimitsMiddlewareSuite) TestTimeout() {
+	timeout := middleware.TimeoutMiddleware(suite.mux, time.Minute, map[string]time.Duration{"GET /exports/albums": 0})
+
+	start := time.Now()
+	suite.serve(timeout, httptest.NewRequest(http.MethodPost, "/singers", nil))
+	suite.True(suite.hasDeadline)
+	suite.WithinDuration(start.Add(time.Minute), suite.deadline, time.Second)
+
+	suite.serve(timeout, httptest.NewRequest(http.MethodGet, "/exports/albums", nil))
+	suite.False(suite.hasDeadline)
+}
+
+func (suite *LimitsMiddlewareSuite) TestTimeoutCancelsContext() {
+	var ctxErr error
+	handler := middleware.TimeoutMiddleware(suite.mux, time.Millisecond, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		ctxErr = r.Context().Err()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/singers", nil))
+	suite.ErrorIs(ctxErr, context.DeadlineExceeded)
+}
+
+func (suite *LimitsMiddlewareSuite) TestBodyLimit() {
+	limit := middleware.BodyLimitMiddleware(suite.mux, 8, map[string]int64{"POST /imports": 64})
+
+	rr := suite.serve(limit, httptest.NewRequest(http.MethodPost, "/singers", strings.NewReader(`{"name":"x"}`)))
+	suite.Equal(http.StatusRequestEntityTooLarge, rr.Code)
+	suite.Equal("application/problem+json", rr.Header().Get("Content-Type"))
+	suite.Contains(rr.Body.String(), "request body exceeds 8 bytes")
+
+	// without a declared length the handler hits the limit while reading
+	req := httptest.NewRequest(http.MethodPost, "/singers", io.MultiReader(strings.NewReader(`{"name":"x"}`)))
+	req.ContentLength = -1
+	rr = suite.serve(limit, req)
+	suite.Equal(http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = suite.serve(limit, httptest.NewRequest(http.MethodPost, "/imports", strings.NewReader("name\nSinger 1\n")))
+	suite.Equal(http.StatusNoContent, rr.Code)
+}
+
+func (suite *LimitsMiddlewareSuite) TestConcurrencyLimit() {
+	started, release := make(chan struct{}), make(chan struct{})
+	blocking := func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /exports/singers", blocking)
+	mux.HandleFunc("GET /exports/albums", blocking)
+	mux.HandleFunc("GET /singers", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	handler := middleware.ConcurrencyLimitMiddleware(mux, 1, "GET /exports/singers", "GET /exports/albums")(mux)
+	serve := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		return rr
+	}
+
+	done := make(chan int)
+	go func() { done <- serve("/exports/singers").Code }()
+	<-started
+
+	// the routes share the slot, and other routes are not limited
+	rr := serve("/exports/albums")
+	suite.Equal(http.StatusServiceUnavailable, rr.Code)
+	suite.Equal("10", rr.Header().Get("Retry-After"))
+	suite.Contains(rr.Body.String(), "at most 1 of these requests can run at once")
+	suite.Equal(http.StatusNoContent, serve("/singers").Code)
+
+	close(release)
+	suite.Equal(http.StatusNoContent, <-done)
+
+	// the slot is free again once the first export is done
+	go func() { <-started }()
+	suite.Equal(http.StatusNoContent, serve("/exports/albums").Code)
+}
+
+func (suite *LimitsMiddlewareSuite) TestJSONContentType() {
+	jsonOnly := middleware.JSONContentTypeMiddleware(suite.mux, "POST /imports")
+	post := func(target, contentType, body string) int {
+		method := http.MethodPost
+		if strings.HasPrefix(contentType, "application/merge-patch") {
+			method = http.MethodPatch
+		}
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		return suite.serve(jsonOnly, req).Code
+	}
+
+	suite.Equal(http.StatusNoContent, post("/singers", "application/json", `{}`))
+	suite.Equal(http.StatusNoContent, post("/singers", "application/json; charset=utf-8", `{}`))
+	suite.Equal(http.StatusNoContent, post("/singers/1", "application/merge-patch+json", `{}`))
+	suite.Equal(http.StatusUnsupportedMediaType, post("/singers", "text/plain", `{}`))
+	suite.Equal(http.StatusUnsupportedMediaType, post("/singers", "", `{}`))
+	// a restore has no body to check
+	suite.Equal(http.StatusNoContent, post("/singers/1", "", ""))
+	suite.Equal(http.StatusNoContent, post("/imports", "text/csv", "name\n"))
+}
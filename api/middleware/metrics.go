@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/metrics"
+)
+
+// unmatchedRoute labels requests no route matched, so unknown paths cannot grow the label set.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a method outside the standard set, for the same reason.
+const otherMethod = "OTHER"
+
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+func methodLabel(r *http.Request) string {
+	if standardMethods[r.Method] {
+		return r.Method
+	}
+	return otherMethod
+}
+
+// RouteMatcher finds the pattern that serves a request; *http.ServeMux implements it.
+type RouteMatcher interface {
+	Handler(r *http.Request) (h http.Handler, pattern string)
+}
+
+// routePattern returns the path part of the matched pattern, e.g. /singers/{id}.
+func routePattern(routes RouteMatcher, r *http.Request) string {
+	_, pattern := routes.Handler(r)
+	if pattern == "" {
+		return unmatchedRoute
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+type HTTPMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inFlight *metrics.GaugeVec
+}
+
+func NewHTTPMetrics(r *metrics.Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: r.NewCounterVec("http_requests_total", "HTTP requests served.",
+			"route", "method", "status"),
+		duration: r.NewHistogramVec("http_request_duration_seconds", "Time to serve an HTTP request.",
+			metrics.DefBuckets, "route", "method", "status"),
+		inFlight: r.NewGaugeVec("http_requests_in_flight", "HTTP requests currently being served.",
+			"route", "method"),
+	}
+}
+
+// MetricsMiddleware records every request under the route pattern routes matches rather than
+// its raw URI.
+func MetricsMiddleware(m *HTTPMetrics, routes RouteMatcher) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			route, method := routePattern(routes, req), methodLabel(req)
+			start := time.Now()
+			m.inFlight.Add(1, route, method)
+			sw := newStatusWriter(w)
+			// deferred so aborted streams and panics are still counted
+			defer func() {
+				m.inFlight.Add(-1, route, method)
+				status := strconv.Itoa(sw.code)
+				m.requests.Inc(route, method, status)
+				m.duration.Observe(time.Since(start).Seconds(), route, method, status)
+			}()
+
+			next.ServeHTTP(sw, req)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/metrics"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsMiddlewareSuite struct {
+	suite.Suite
+	handler http.Handler
+}
+
+func TestMetricsMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(MetricsMiddlewareSuite))
+}
+
+func (suite *MetricsMiddlewareSuite) SetupTest() {
+	registry := metrics.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /singers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "404" {
+			http.NotFound(w, r)
+			return
+		}
+		// no WriteHeader, so net/http sends 200
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.Handle("GET /metrics", registry)
+	suite.handler = middleware.MetricsMiddleware(middleware.NewHTTPMetrics(registry), mux)(mux)
+}
+
+func (suite *MetricsMiddlewareSuite) get(target string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	suite.handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr
+}
+
+func (suite *MetricsMiddlewareSuite) TestScrape() {
+	suite.get("/singers/1")
+	suite.get("/singers/2")
+	suite.get("/singers/404")
+	suite.get("/no/such/path")
+
+	rr := suite.get("/metrics")
+	suite.Equal(http.StatusOK, rr.Code)
+	body := rr.Body.String()
+
+	suite.Contains(body, "# TYPE http_requests_total counter\n")
+	suite.Contains(body, `http_requests_total{route="/singers/{id}",method="GET",status="200"} 2`+"\n")
+	suite.Contains(body, `http_requests_total{route="/singers/{id}",method="GET",status="404"} 1`+"\n")
+	suite.Contains(body, `http_requests_total{route="unmatched",method="GET",status="404"} 1`+"\n")
+	suite.NotContains(body, "/singers/1")
+	suite.Contains(body, `http_request_duration_seconds_count{route="/singers/{id}",method="GET",status="200"} 2`+"\n")
+	// the scrape itself is still in flight while it renders
+	suite.Contains(body, `http_requests_in_flight{route="/metrics",method="GET"} 1`+"\n")
+	suite.Contains(body, `http_requests_in_flight{route="/singers/{id}",method="GET"} 0`+"\n")
+}
+
+func (suite *MetricsMiddlewareSuite) TestNonStandardMethod() {
+	for _, method := range []string{"PROPFIND", "X-RANDOM-1", "get"} {
+		suite.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/singers/1", nil))
+	}
+
+	body := suite.get("/metrics").Body.String()
+	suite.Contains(body, `http_requests_total{route="unmatched",method="OTHER",status="405"} 3`+"\n")
+	suite.NotContains(body, "PROPFIND")
+	suite.NotContains(body, `method="get"`)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+)
+
+// RecoverMiddleware turns a panicking handler into a logged 500 problem response instead of a
+// dropped connection. http.ErrAbortHandler is re-raised, since it is how a handler deliberately
+// aborts a response, e.g. a failed export stream.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sw := newStatusWriter(w)
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(p)
+			}
+			logging.FromContext(req.Context()).ErrorContext(req.Context(), "handler panicked",
+				"panic", p, "stack", string(debug.Stack()))
+			if sw.wroteHeader {
+				// the status is already on the wire, so only closing the connection tells the client
+				panic(http.ErrAbortHandler)
+			}
+			controller.WriteProblem(sw, req, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}()
+
+		next.ServeHTTP(sw, req)
+	})
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecoverMiddlewareSuite struct {
+	suite.Suite
+	buf      bytes.Buffer
+	original *slog.Logger
+}
+
+func TestRecoverMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(RecoverMiddlewareSuite))
+}
+
+func (suite *RecoverMiddlewareSuite) SetupTest() {
+	suite.buf.Reset()
+	suite.original = slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&suite.buf, nil)))
+}
+
+func (suite *RecoverMiddlewareSuite) TearDownTest() {
+	slog.SetDefault(suite.original)
+}
+
+func (suite *RecoverMiddlewareSuite) TestPanicBecomesProblem() {
+	handler := middleware.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var singer *struct{ Name string }
+		_, _ = w.Write([]byte(singer.Name))
+	}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/albums/1", nil))
+
+	suite.Equal(http.StatusInternalServerError, rr.Code)
+	suite.Equal("application/problem+json", rr.Header().Get("Content-Type"))
+	var problem controller.Problem
+	suite.NoError(json.NewDecoder(rr.Body).Decode(&problem))
+	suite.Equal("Internal Server Error", problem.Detail)
+	suite.NotContains(problem.Detail, "nil pointer")
+
+	suite.Contains(suite.buf.String(), `"msg":"handler panicked"`)
+	suite.Contains(suite.buf.String(), "nil pointer dereference")
+	suite.Contains(suite.buf.String(), "recover_test.go")
+}
+
+func (suite *RecoverMiddlewareSuite) TestAbortHandlerIsRepanicked() {
+	handler := middleware.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	suite.PanicsWithValue(http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/exports/albums", nil))
+	})
+	suite.NotContains(suite.buf.String(), "handler panicked")
+}
+
+func (suite *RecoverMiddlewareSuite) TestPanicAfterHeadersAborts() {
+	handler := middleware.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":`))
+		panic("broken")
+	}))
+
+	suite.PanicsWithValue(http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/albums/1", nil))
+	})
+	suite.Contains(suite.buf.String(), `"panic":"broken"`)
+}
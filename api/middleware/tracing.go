@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
+)
+
+// TracingMiddleware continues the trace of an incoming traceparent header, or starts one, with
+// a server span per request named after the handler's route, e.g. "GET /albums/{id}".
+func TracingMiddleware(tp trace.TracerProvider, routes RouteMatcher) func(http.Handler) http.Handler {
+	tracer := tp.Tracer(tracing.InstrumentationName)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			route := routePattern(routes, req)
+			ctx := tracing.Propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", route),
+					attribute.String("url.path", req.URL.Path),
+					attribute.String("user_agent.original", req.UserAgent()),
+				),
+			)
+			sw := newStatusWriter(w)
+			defer func() {
+				span.SetAttributes(attribute.Int("http.response.status_code", sw.code))
+				if sw.code >= http.StatusInternalServerError {
+					span.SetStatus(codes.Error, http.StatusText(sw.code))
+				}
+				span.End()
+			}()
+
+			next.ServeHTTP(sw, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracingMiddlewareSuite struct {
+	suite.Suite
+	exporter *tracetest.InMemoryExporter
+	handler  http.Handler
+	// handlerSpan is the span context seen by the last handler call
+	handlerSpan trace.SpanContext
+}
+
+func TestTracingMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(TracingMiddlewareSuite))
+}
+
+func (suite *TracingMiddlewareSuite) SetupTest() {
+	suite.exporter = tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider(sdktrace.NewSimpleSpanProcessor(suite.exporter), "test", 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /albums/{id}", func(w http.ResponseWriter, r *http.Request) {
+		suite.handlerSpan = trace.SpanContextFromContext(r.Context())
+		if r.PathValue("id") == "500" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	suite.handler = middleware.TracingMiddleware(tp, mux)(mux)
+}
+
+func (suite *TracingMiddlewareSuite) TestContinuesIncomingTrace() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/3", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	suite.handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := suite.exporter.GetSpans()
+	suite.Require().Len(spans, 1)
+	span := spans[0]
+	suite.Equal("GET /albums/{id}", span.Name)
+	suite.Equal(trace.SpanKindServer, span.SpanKind)
+	suite.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	suite.Equal("00f067aa0ba902b7", span.Parent.SpanID().String())
+	suite.Equal(span.SpanContext, suite.handlerSpan)
+	suite.Contains(span.Attributes, attribute.String("http.route", "/albums/{id}"))
+	suite.Contains(span.Attributes, attribute.Int("http.response.status_code", http.StatusNotFound))
+	// a client error is not a failure of the server
+	suite.Equal(codes.Unset, span.Status.Code)
+}
+
+func (suite *TracingMiddlewareSuite) TestStartsTraceAndMarksServerErrors() {
+	suite.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/albums/500", nil))
+
+	spans := suite.exporter.GetSpans()
+	suite.Require().Len(spans, 1)
+	suite.False(spans[0].Parent.IsValid())
+	suite.Equal(codes.Error, spans[0].Status.Code)
+}
@@ -1,48 +1,204 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/pulse227/server-recruit-challenge-sample/api/middleware"
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/config"
 	"github.com/pulse227/server-recruit-challenge-sample/controller"
 	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/metrics"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
 	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
 )
 
-func NewRouter(
-	dbUser, dbPass, dbHost, dbName string,
-) (http.Handler, error) {
+// exportRoutes stream whole tables, so they get a timeout and a concurrency limit of their own.
+var exportRoutes = []string{"GET /exports/singers", "GET /exports/albums"}
 
-	dbClient, err := mysqldb.Initialize(dbUser, dbPass, dbHost, dbName)
+var routeBodyLimits = map[string]int64{
+	"POST /imports": controller.MaxImportSize,
+}
+
+const (
+	// trashPurgeInterval is how often trashed rows past their retention are hard-deleted.
+	trashPurgeInterval          = time.Hour
+	idempotencyKeyPurgeInterval = time.Hour
+)
+
+// Router serves the API and owns the resources behind it.
+type Router struct {
+	http.Handler
+	db              *sql.DB
+	healthService   service.HealthService
+	importService   service.ImportService
+	shutdownTracing func(context.Context) error
+}
+
+// Drain makes /readyz fail so load balancers stop routing here before shutdown.
+func (r *Router) Drain() {
+	r.healthService.StartDraining()
+}
+
+// Close lets running imports finish, flushes pending spans and releases the database pool once
+// the server has shut down.
+func (r *Router) Close(ctx context.Context) error {
+	importErr := r.importService.Close(ctx)
+	return errors.Join(importErr, r.shutdownTracing(ctx), r.db.Close())
+}
+
+// NewRouter wires the handlers. Background jobs such as the trash purger run until ctx is done.
+func NewRouter(ctx context.Context, cfg *config.Config) (*Router, error) {
+	tp, shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
 	if err != nil {
 		return nil, err
 	}
-	if err = dbClient.Ping(); err != nil {
+
+	verifier, err := auth.NewVerifier(cfg.Auth.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	dbClient, err := mysqldb.Connect(ctx, cfg.DB, tp)
+	if err != nil {
 		return nil, err
 	}
 
-	singerRepo := repository.NewSingerRepository(dbClient)
-	singerService := service.NewSingerService(singerRepo)
-	singerController := controller.NewSingerController(singerService)
+	registry := metrics.NewRegistry()
+	metrics.RegisterDBStats(registry, dbClient.Stats)
+	queryObserver := repository.Observers(tracing.NewObserver(tp, "repository"), metrics.NewQueryObserver(registry))
+	serviceObserver := tracing.NewObserver(tp, "service")
+
+	controllerOptions := controller.Options{
+		ImportMode:     cfg.Features.ImportMode,
+		RequireIfMatch: cfg.Features.RequireIfMatch,
+		MaxBatchSize:   cfg.Features.MaxBatchSize,
+	}
+
+	auditRepo := repository.ObserveAuditRepository(repository.NewAuditRepository(dbClient), queryObserver)
+	auditWriter := service.NewAuditWriter(auditRepo)
+	auditService := service.ObserveAuditService(service.NewAuditService(auditRepo), serviceObserver)
+	auditController := controller.NewAuditController(auditService)
+
+	singerRepo := repository.ObserveSingerRepository(repository.NewSingerRepository(dbClient), queryObserver)
+	albumRepo := repository.ObserveAlbumRepository(repository.NewAlbumRepository(dbClient), queryObserver)
+	transactor := repository.NewTransactor(dbClient)
+	singerService := service.ObserveSingerService(service.NewSingerService(singerRepo, albumRepo, transactor, auditWriter), serviceObserver)
+	singerController := controller.NewSingerController(singerService, controllerOptions)
 
-	albumRepo := repository.NewAlbumRepository(dbClient)
-	albumService := service.NewAlbumService(albumRepo)
-	albumController := controller.NewAlbumController(albumService)
+	trackRepo := repository.ObserveTrackRepository(repository.NewTrackRepository(dbClient), queryObserver)
+	albumService := service.ObserveAlbumService(service.NewAlbumService(albumRepo, trackRepo, transactor, auditWriter), serviceObserver)
+	albumController := controller.NewAlbumController(albumService, controllerOptions)
+
+	trackService := service.ObserveTrackService(service.NewTrackService(trackRepo, albumRepo, transactor), serviceObserver)
+	trackController := controller.NewTrackController(trackService)
+
+	creditRepo := repository.ObserveCreditRepository(repository.NewCreditRepository(dbClient), queryObserver)
+	creditService := service.ObserveCreditService(service.NewCreditService(creditRepo, albumRepo, transactor, auditWriter), serviceObserver)
+	creditController := controller.NewCreditController(creditService, albumService, controllerOptions)
+
+	trashService := service.ObserveTrashService(service.NewTrashService(singerRepo, albumRepo), serviceObserver)
+	trashController := controller.NewTrashController(trashService)
+	go service.RunTrashPurger(ctx, trashService, cfg.Features.TrashRetention, trashPurgeInterval)
+
+	searchRepo := repository.ObserveSearchRepository(repository.NewSearchRepository(dbClient), queryObserver)
+	searchService := service.ObserveSearchService(service.NewSearchService(searchRepo), serviceObserver)
+	searchController := controller.NewSearchController(searchService)
+
+	importJobRepo := repository.NewInMemoryImportJobRepository(cfg.Features.ImportJobRetention)
+	importService := service.ObserveImportService(service.NewImportService(importJobRepo, singerRepo, albumRepo, transactor, auditWriter), serviceObserver)
+	importController := controller.NewImportController(importService)
+
+	statsController := controller.NewStatsController(dbClient)
+
+	healthRepo := repository.ObserveHealthRepository(repository.NewHealthRepository(dbClient), queryObserver)
+	healthService := service.NewHealthService(healthRepo, cfg.Server.ReadinessTimeout)
+	healthController := controller.NewHealthController(healthService)
+
+	apiKeyRepo := repository.ObserveAPIKeyRepository(repository.NewAPIKeyRepository(dbClient), queryObserver)
+	authService := service.ObserveAuthService(service.NewAuthService(apiKeyRepo, verifier), serviceObserver)
 
 	mux := http.NewServeMux()
+	// every route declares the role it requires
+	policy := middleware.AuthPolicy{}
+	handle := func(pattern string, role model.Role, handler http.HandlerFunc) {
+		mux.HandleFunc(pattern, handler)
+		policy[pattern] = role
+	}
+
+	handle("GET /singers", model.RoleViewer, singerController.GetSingerListHandler)
+	handle("GET /singers/{id}", model.RoleViewer, singerController.GetSingerDetailHandler)
+	handle("GET /singers/{id}/albums", model.RoleViewer, singerController.GetSingerAlbumsHandler)
+	handle("POST /singers", model.RoleEditor, singerController.PostSingerHandler)
+	handle("POST /singers:batch", model.RoleEditor, singerController.BatchSingersHandler)
+	handle("POST /singers/{id}", model.RoleEditor, singerController.RestoreSingerHandler)
+	handle("PUT /singers/{id}", model.RoleEditor, singerController.PutSingerHandler)
+	handle("PATCH /singers/{id}", model.RoleEditor, singerController.PatchSingerHandler)
+	handle("DELETE /singers/{id}", model.RoleEditor, singerController.DeleteSingerHandler)
 
-	mux.HandleFunc("GET /singers", singerController.GetSingerListHandler)
-	mux.HandleFunc("GET /singers/{id}", singerController.GetSingerDetailHandler)
-	mux.HandleFunc("POST /singers", singerController.PostSingerHandler)
-	mux.HandleFunc("DELETE /singers/{id}", singerController.DeleteSingerHandler)
+	handle("GET /albums", model.RoleViewer, albumController.GetAlbums)
+	handle("GET /albums/{id}", model.RoleViewer, albumController.GetAlbum)
+	handle("POST /albums", model.RoleEditor, albumController.CreateAlbum)
+	handle("POST /albums:batch", model.RoleEditor, albumController.BatchAlbums)
+	handle("POST /albums/{id}", model.RoleEditor, albumController.RestoreAlbum)
+	handle("PUT /albums/{id}", model.RoleEditor, albumController.UpdateAlbum)
+	handle("PATCH /albums/{id}", model.RoleEditor, albumController.PatchAlbum)
+	handle("DELETE /albums/{id}", model.RoleEditor, albumController.DeleteAlbum)
 
-	mux.HandleFunc("GET /albums", albumController.GetAlbums)
-	mux.HandleFunc("GET /albums/{id}", albumController.GetAlbum)
-	mux.HandleFunc("POST /albums", albumController.CreateAlbum)
-	mux.HandleFunc("DELETE /albums/{id}", albumController.DeleteAlbum)
+	handle("GET /albums/{id}/credits", model.RoleViewer, creditController.GetAlbumCredits)
+	handle("PUT /albums/{id}/credits", model.RoleEditor, creditController.PutAlbumCredits)
 
-	wrappedMux := middleware.LoggingMiddleware(mux)
+	handle("GET /albums/{id}/tracks", model.RoleViewer, trackController.GetAlbumTracks)
+	handle("POST /albums/{id}/tracks", model.RoleEditor, trackController.CreateTrack)
+	handle("POST /albums/{id}/tracks:reorder", model.RoleEditor, trackController.ReorderTracks)
+	handle("GET /tracks/{id}", model.RoleViewer, trackController.GetTrack)
+	handle("PATCH /tracks/{id}", model.RoleEditor, trackController.PatchTrack)
+	handle("DELETE /tracks/{id}", model.RoleEditor, trackController.DeleteTrack)
+
+	handle("GET /search", model.RoleViewer, searchController.SearchHandler)
+
+	handle("GET /exports/singers", model.RoleViewer, singerController.ExportSingersHandler)
+	handle("GET /exports/albums", model.RoleViewer, albumController.ExportAlbums)
+
+	handle("GET /trash", model.RoleAdmin, trashController.GetTrashHandler)
+	handle("GET /audit", model.RoleAdmin, auditController.GetAuditEventsHandler)
+
+	handle("GET /healthz", middleware.Public, healthController.HealthzHandler)
+	handle("GET /readyz", middleware.Public, healthController.ReadyzHandler)
+	handle("GET /metrics", middleware.Public, registry.ServeHTTP)
+	handle("GET /internal/db/stats", model.RoleAdmin, statsController.GetDBStatsHandler)
+
+	handle("POST /imports", model.RoleEditor, importController.PostImportHandler)
+	handle("GET /imports/{id}", model.RoleViewer, importController.GetImportHandler)
+
+	idempotencyRepo := repository.ObserveIdempotencyRepository(repository.NewIdempotencyRepository(dbClient), queryObserver)
+	go middleware.RunIdempotencyPurger(ctx, idempotencyRepo, idempotencyKeyPurgeInterval)
+
+	httpMetrics := middleware.NewHTTPMetrics(registry)
+	// Wrapped from the inside out, so the last middleware applied sees a request first.
+	var handler http.Handler = middleware.IdempotencyMiddleware(idempotencyRepo, cfg.Features.IdempotencyKeyTTL)(mux)
+	handler = middleware.JSONContentTypeMiddleware(mux, "POST /imports")(handler)
+	handler = middleware.BodyLimitMiddleware(mux, int64(cfg.Server.MaxBodyBytes), routeBodyLimits)(handler)
+	handler = middleware.ConcurrencyLimitMiddleware(mux, cfg.Server.MaxConcurrentExports, exportRoutes...)(handler)
+	if cfg.Auth.Enabled {
+		handler = middleware.AuthMiddleware(authService, mux, policy)(handler)
+	}
+	routeTimeouts := make(map[string]time.Duration, len(exportRoutes))
+	for _, route := range exportRoutes {
+		routeTimeouts[route] = cfg.Server.ExportTimeout
+	}
+	handler = middleware.TimeoutMiddleware(mux, cfg.Server.RequestTimeout, routeTimeouts)(handler)
+	handler = middleware.RecoverMiddleware(handler)
+	handler = middleware.AccessLogMiddleware(slog.Default(), mux, cfg.Log.AccessSampleRate)(handler)
+	handler = middleware.MetricsMiddleware(httpMetrics, mux)(handler)
+	handler = middleware.TracingMiddleware(tp, mux)(handler)
 
-	return wrappedMux, nil
+	return &Router{Handler: handler, db: dbClient, healthService: healthService, importService: importService, shutdownTracing: shutdownTracing}, nil
 }
@@ -0,0 +1,31 @@
+// Package auth verifies API keys and bearer tokens and carries the authenticated principal
+// through a context.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, p *model.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the request ctx belongs to. ok is false when the request
+// was not authenticated, e.g. with authentication disabled.
+func FromContext(ctx context.Context) (p *model.Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(*model.Principal)
+	return p, ok
+}
+
+// HashAPIKey returns the SHA-256 hex digest stored for key. API keys are random, so a plain
+// digest is enough and keeps them searchable.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAPIKey(t *testing.T) {
+	// matches SHA2('local-viewer-key', 256) in initdb-dev/3_api_keys.sql
+	assert.Equal(t, "e30273f7d6af4b85e28b85b57e70b499a03025fe1dbe5b3261ad57147a33600f", auth.HashAPIKey("local-viewer-key"))
+	assert.NotEqual(t, auth.HashAPIKey("key"), auth.HashAPIKey("key2"))
+}
+
+func TestContext(t *testing.T) {
+	_, ok := auth.FromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := &model.Principal{Subject: "alice", Role: model.RoleEditor, Method: model.AuthMethodJWT}
+	got, ok := auth.FromContext(auth.NewContext(context.Background(), principal))
+	assert.True(t, ok)
+	assert.Same(t, principal, got)
+}
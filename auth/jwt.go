@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// leeway tolerates clock skew between the token issuer and this server.
+const leeway = 30 * time.Second
+
+var ErrNoTokenKeys = errors.New("no key is configured for bearer tokens")
+
+// Verifier checks HS256 and RS256 bearer tokens against locally configured keys.
+type Verifier struct {
+	hsSecret  []byte
+	rsaKey    *rsa.PublicKey
+	jwks      map[string]*rsa.PublicKey
+	roleClaim string
+	parser    *jwt.Parser
+}
+
+// NewVerifier loads the keys of c. A verifier without keys rejects every token.
+func NewVerifier(c config.JWT) (*Verifier, error) {
+	v := &Verifier{roleClaim: c.RoleClaim}
+	var methods []string
+	if c.HS256Secret != "" {
+		v.hsSecret = []byte(c.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if c.RS256PublicKeyFile != "" {
+		key, err := loadRSAPublicKey(c.RS256PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKey = key
+	}
+	if c.JWKSFile != "" {
+		keys, err := loadJWKS(c.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.jwks = keys
+	}
+	if v.rsaKey != nil || len(v.jwks) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	opts := []jwt.ParserOption{
+		// pinning the methods keeps an RS256 public key from being accepted as an HS256 secret
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if c.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(c.Issuer))
+	}
+	if c.Audience != "" {
+		opts = append(opts, jwt.WithAudience(c.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Verify returns the principal named by the sub and role claims of a valid token.
+func (v *Verifier) Verify(token string) (*model.Principal, error) {
+	if v.hsSecret == nil && v.rsaKey == nil && len(v.jwks) == 0 {
+		return nil, ErrNoTokenKeys
+	}
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, err
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("token has no sub claim")
+	}
+	role, _ := claims[v.roleClaim].(string)
+	if !model.Role(role).Valid() {
+		return nil, fmt.Errorf("token claim %s is not a known role", v.roleClaim)
+	}
+	return &model.Principal{Subject: subject, Role: model.Role(role), Method: model.AuthMethodJWT}, nil
+}
+
+func (v *Verifier) key(t *jwt.Token) (any, error) {
+	switch t.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.hsSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" && v.rsaKey != nil {
+			return v.rsaKey, nil
+		}
+		if key, ok := v.jwks[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+}
+
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an RSA public key", path)
+	}
+	return rsaKey, nil
+}
+
+// jwk holds the members of an RSA JSON Web Key (RFC 7517) needed to verify signatures.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// loadJWKS returns the RSA signing keys of a key set by kid; other keys are skipped.
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Use == "enc" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: invalid n: %w", path, k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: invalid e: %w", path, k.Kid, err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("%s: key %q: exponent too large", path, k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA signing keys found in %s", path)
+	}
+	return keys, nil
+}
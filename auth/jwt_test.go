@@ -0,0 +1,176 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+type VerifierSuite struct {
+	suite.Suite
+	rsaKey *rsa.PrivateKey
+	dir    string
+}
+
+func TestVerifierSuite(t *testing.T) {
+	suite.Run(t, new(VerifierSuite))
+}
+
+func (suite *VerifierSuite) SetupSuite() {
+	var err error
+	suite.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+}
+
+func (suite *VerifierSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *VerifierSuite) claims(role string) jwt.MapClaims {
+	return jwt.MapClaims{"sub": "alice", "role": role, "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func (suite *VerifierSuite) signHS256(claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *VerifierSuite) signRS256(claims jwt.MapClaims, kid string) string {
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		t.Header["kid"] = kid
+	}
+	token, err := t.SignedString(suite.rsaKey)
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *VerifierSuite) writeFile(name string, data []byte) string {
+	path := filepath.Join(suite.dir, name)
+	suite.Require().NoError(os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func (suite *VerifierSuite) verifier(c config.JWT) *auth.Verifier {
+	if c.RoleClaim == "" {
+		c.RoleClaim = "role"
+	}
+	v, err := auth.NewVerifier(c)
+	suite.Require().NoError(err)
+	return v
+}
+
+func (suite *VerifierSuite) TestHS256() {
+	v := suite.verifier(config.JWT{HS256Secret: testSecret, Issuer: "https://issuer.example", Audience: "catalog"})
+
+	claims := suite.claims("editor")
+	claims["iss"] = "https://issuer.example"
+	claims["aud"] = "catalog"
+	principal, err := v.Verify(suite.signHS256(claims))
+	suite.Require().NoError(err)
+	suite.Equal(&model.Principal{Subject: "alice", Role: model.RoleEditor, Method: model.AuthMethodJWT}, principal)
+
+	claims["aud"] = "other"
+	_, err = v.Verify(suite.signHS256(claims))
+	suite.Error(err)
+}
+
+func (suite *VerifierSuite) TestRejectsInvalidTokens() {
+	v := suite.verifier(config.JWT{HS256Secret: testSecret})
+
+	expired := suite.claims("viewer")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	noExpiry := suite.claims("viewer")
+	delete(noExpiry, "exp")
+	noSubject := suite.claims("viewer")
+	delete(noSubject, "sub")
+	wrongSecret, err := jwt.NewWithClaims(jwt.SigningMethodHS256, suite.claims("viewer")).SignedString([]byte("another secret of at least 32 bytes"))
+	suite.Require().NoError(err)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, suite.claims("admin")).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	suite.Require().NoError(err)
+
+	for name, token := range map[string]string{
+		"expired":      suite.signHS256(expired),
+		"no expiry":    suite.signHS256(noExpiry),
+		"no subject":   suite.signHS256(noSubject),
+		"unknown role": suite.signHS256(suite.claims("root")),
+		"wrong secret": wrongSecret,
+		"unsigned":     unsigned,
+		// RS256 is not configured
+		"other method": suite.signRS256(suite.claims("admin"), ""),
+		"malformed":    "not.a.token",
+	} {
+		_, err := v.Verify(token)
+		suite.Error(err, name)
+	}
+}
+
+func (suite *VerifierSuite) TestRS256PublicKeyFile() {
+	der, err := x509.MarshalPKIXPublicKey(&suite.rsaKey.PublicKey)
+	suite.Require().NoError(err)
+	path := suite.writeFile("public.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	v := suite.verifier(config.JWT{RS256PublicKeyFile: path})
+
+	principal, err := v.Verify(suite.signRS256(suite.claims("admin"), ""))
+	suite.Require().NoError(err)
+	suite.Equal(model.RoleAdmin, principal.Role)
+
+	// an HS256 token signed with the public key must not pass as its secret
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, suite.claims("admin")).SignedString(der)
+	suite.Require().NoError(err)
+	_, err = v.Verify(forged)
+	suite.Error(err)
+}
+
+func (suite *VerifierSuite) TestJWKSFile() {
+	pub := suite.rsaKey.PublicKey
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256"},
+		{
+			"kty": "RSA", "kid": "rsa-1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		},
+	}}
+	data, err := json.Marshal(set)
+	suite.Require().NoError(err)
+	v := suite.verifier(config.JWT{JWKSFile: suite.writeFile("jwks.json", data)})
+
+	principal, err := v.Verify(suite.signRS256(suite.claims("viewer"), "rsa-1"))
+	suite.Require().NoError(err)
+	suite.Equal(model.RoleViewer, principal.Role)
+
+	_, err = v.Verify(suite.signRS256(suite.claims("viewer"), "rsa-2"))
+	suite.ErrorContains(err, `unknown key id "rsa-2"`)
+}
+
+func (suite *VerifierSuite) TestInvalidKeyFiles() {
+	_, err := auth.NewVerifier(config.JWT{RoleClaim: "role", JWKSFile: suite.writeFile("empty.json", []byte(`{"keys":[]}`))})
+	suite.ErrorContains(err, "no RSA signing keys")
+
+	_, err = auth.NewVerifier(config.JWT{RoleClaim: "role", RS256PublicKeyFile: suite.writeFile("bad.pem", []byte("not pem"))})
+	suite.ErrorContains(err, "no PEM block")
+}
+
+func (suite *VerifierSuite) TestWithoutKeys() {
+	v := suite.verifier(config.JWT{})
+	_, err := v.Verify(suite.signHS256(suite.claims("viewer")))
+	suite.ErrorIs(err, auth.ErrNoTokenKeys)
+}
@@ -0,0 +1,339 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets when the configuration is printed.
+const redacted = "[REDACTED]"
+
+// collationPattern keeps the collation safe to splice into the SET statement run on connect.
+var collationPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// MaxBatchSizeLimit keeps one multi-row INSERT of a batch within the 65535 placeholders of a
+// MySQL prepared statement; the widest batch insert, albums with ids, binds 3 per row.
+const MaxBatchSizeLimit = 65535 / 3
+
+// Config is the effective configuration of the server.
+type Config struct {
+	Server   Server   `yaml:"server"`
+	DB       DB       `yaml:"db"`
+	Log      Log      `yaml:"log"`
+	Tracing  Tracing  `yaml:"tracing"`
+	Auth     Auth     `yaml:"auth"`
+	Features Features `yaml:"features"`
+}
+
+type Server struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	// WriteTimeout is off by default so long exports are not cut off mid-stream; they are
+	// bounded by ExportTimeout instead.
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay is how long /readyz fails before shutdown starts, so load balancers stop
+	// routing new requests first.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// ReadinessTimeout bounds the dependency checks of one /readyz probe.
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"`
+	// RequestTimeout is the deadline of a request's database work; exports have their own.
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// ExportTimeout bounds a whole export, both its database transaction and writing the
+	// response, so a client that stops reading cannot hold a connection forever.
+	ExportTimeout time.Duration `yaml:"export_timeout"`
+	// MaxConcurrentExports caps the exports running at once, each holding a database
+	// connection; more are answered with 503.
+	MaxConcurrentExports int `yaml:"max_concurrent_exports"`
+	// MaxBodyBytes caps JSON request bodies; imports have a larger limit of their own.
+	MaxBodyBytes int `yaml:"max_body_bytes"`
+}
+
+type DB struct {
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Addr            string        `yaml:"addr"`
+	Name            string        `yaml:"name"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	DialTimeout     time.Duration `yaml:"dial_timeout"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	// Collation applies to the connection's literals. Columns compare by the collation the
+	// schema declares for them, which this should match.
+	Collation string `yaml:"collation"`
+	TLS       TLS    `yaml:"tls"`
+	// StartupTimeout bounds how long startup retries while MySQL is still booting.
+	StartupTimeout      time.Duration `yaml:"startup_timeout"`
+	RetryInitialBackoff time.Duration `yaml:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `yaml:"retry_max_backoff"`
+}
+
+type TLSMode string
+
+const (
+	TLSDisabled TLSMode = "disabled"
+	// TLSPreferred uses TLS when the server offers it, without verifying the certificate.
+	TLSPreferred  TLSMode = "preferred"
+	TLSSkipVerify TLSMode = "skip-verify"
+	// TLSRequired verifies the server certificate against CAFile, or the system roots.
+	TLSRequired TLSMode = "required"
+)
+
+func (m TLSMode) Valid() bool {
+	switch m {
+	case TLSDisabled, TLSPreferred, TLSSkipVerify, TLSRequired:
+		return true
+	}
+	return false
+}
+
+type TLS struct {
+	Mode TLSMode `yaml:"mode"`
+	// ServerName defaults to the host of DB.Addr.
+	ServerName string `yaml:"server_name"`
+	CAFile     string `yaml:"ca_file"`
+	// CertFile and KeyFile present a client certificate when both are set.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+type Log struct {
+	// Level is one of debug, info, warn and error.
+	Level string `yaml:"level"`
+	// Format is json or text.
+	Format string `yaml:"format"`
+	// AccessSampleRate is the share of successful requests written to the access log; errors
+	// are always logged.
+	AccessSampleRate float64 `yaml:"access_sample_rate"`
+}
+
+type TracingExporter string
+
+const (
+	TracingNone TracingExporter = "none"
+	// TracingOTLP sends spans over OTLP/HTTP, e.g. to a local collector.
+	TracingOTLP TracingExporter = "otlp"
+)
+
+type Tracing struct {
+	Exporter TracingExporter `yaml:"exporter"`
+	// Endpoint is the host:port of the OTLP/HTTP receiver.
+	Endpoint string `yaml:"endpoint"`
+	// Insecure sends spans over plain HTTP.
+	Insecure    bool   `yaml:"insecure"`
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio is the share of new traces recorded; incoming traceparent flags are honored.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+type Auth struct {
+	// Enabled requires credentials on every route except the health and metrics endpoints.
+	Enabled bool `yaml:"enabled"`
+	JWT     JWT  `yaml:"jwt"`
+}
+
+// JWT configures which bearer tokens are accepted. Without any key, only API keys work.
+type JWT struct {
+	HS256Secret string `yaml:"hs256_secret"`
+	// RS256PublicKeyFile is a PEM public key for RS256 tokens without a kid header.
+	RS256PublicKeyFile string `yaml:"rs256_public_key_file"`
+	// JWKSFile is a JSON Web Key Set whose RSA keys verify RS256 tokens by kid.
+	JWKSFile string `yaml:"jwks_file"`
+	// Issuer and Audience are checked against iss and aud when set.
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// RoleClaim names the claim holding viewer, editor or admin.
+	RoleClaim string `yaml:"role_claim"`
+}
+
+type Features struct {
+	// ImportMode accepts client-supplied ids on create.
+	ImportMode bool `yaml:"import_mode"`
+	// RequireIfMatch rejects unconditional updates and deletes with 428.
+	RequireIfMatch bool `yaml:"require_if_match"`
+	MaxBatchSize   int  `yaml:"max_batch_size"`
+	// TrashRetention is how long soft-deleted rows stay restorable.
+	TrashRetention time.Duration `yaml:"trash_retention"`
+	// IdempotencyKeyTTL is how long a POST can be retried with the same Idempotency-Key.
+	IdempotencyKeyTTL time.Duration `yaml:"idempotency_key_ttl"`
+	// ImportJobRetention is how long a finished import can still be polled.
+	ImportJobRetention time.Duration `yaml:"import_job_retention"`
+}
+
+// Default returns the configuration used for the local compose setup.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:                 ":8888",
+			ReadHeaderTimeout:    5 * time.Second,
+			ReadTimeout:          30 * time.Second,
+			IdleTimeout:          2 * time.Minute,
+			ShutdownTimeout:      5 * time.Second,
+			DrainDelay:           5 * time.Second,
+			ReadinessTimeout:     2 * time.Second,
+			RequestTimeout:       30 * time.Second,
+			ExportTimeout:        10 * time.Minute,
+			MaxConcurrentExports: 4,
+			MaxBodyBytes:         1 << 20,
+		},
+		DB: DB{
+			User:            "root",
+			Password:        "root",
+			Addr:            "localhost:13306",
+			Name:            "myapp",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			DialTimeout:     5 * time.Second,
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			Collation:       "utf8mb4_ja_0900_as_cs",
+			TLS:             TLS{Mode: TLSDisabled},
+			StartupTimeout:  time.Minute,
+			// 0.5s, 1s, 2s, 4s, 5s, 5s, ... while the compose database initializes
+			RetryInitialBackoff: 500 * time.Millisecond,
+			RetryMaxBackoff:     5 * time.Second,
+		},
+		Log: Log{
+			Level:            "debug",
+			Format:           "json",
+			AccessSampleRate: 1,
+		},
+		Tracing: Tracing{
+			Exporter:    TracingNone,
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "server-recruit-challenge-sample",
+			SampleRatio: 1,
+		},
+		Auth: Auth{
+			Enabled: true,
+			JWT:     JWT{RoleClaim: "role"},
+		},
+		Features: Features{
+			// Conditional updates are mandatory unless explicitly turned off for older clients.
+			RequireIfMatch:     true,
+			MaxBatchSize:       1000,
+			TrashRetention:     30 * 24 * time.Hour,
+			IdempotencyKeyTTL:  24 * time.Hour,
+			ImportJobRetention: 24 * time.Hour,
+		},
+	}
+}
+
+// Validate reports every invalid value at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Addr != "", "server.addr must not be empty")
+	check(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout must not be negative")
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay must not be negative")
+	check(c.Server.ReadinessTimeout > 0, "server.readiness_timeout must be positive")
+	check(c.Server.RequestTimeout >= 0, "server.request_timeout must not be negative")
+	check(c.Server.ExportTimeout > 0, "server.export_timeout must be positive")
+	check(c.Server.MaxConcurrentExports >= 1, "server.max_concurrent_exports must be at least 1")
+	check(c.Server.MaxBodyBytes >= 1, "server.max_body_bytes must be at least 1")
+
+	check(c.DB.User != "", "db.user must not be empty")
+	check(c.DB.Addr != "", "db.addr must not be empty")
+	check(c.DB.Name != "", "db.name must not be empty")
+	check(c.DB.MaxOpenConns >= 0, "db.max_open_conns must not be negative")
+	check(c.DB.MaxIdleConns >= 0, "db.max_idle_conns must not be negative")
+	check(c.DB.MaxOpenConns == 0 || c.DB.MaxIdleConns <= c.DB.MaxOpenConns,
+		"db.max_idle_conns (%d) must not exceed db.max_open_conns (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns)
+	check(c.DB.ConnMaxLifetime >= 0, "db.conn_max_lifetime must not be negative")
+	check(c.DB.ConnMaxIdleTime >= 0, "db.conn_max_idle_time must not be negative")
+	check(c.DB.DialTimeout >= 0, "db.dial_timeout must not be negative")
+	check(c.DB.ReadTimeout >= 0, "db.read_timeout must not be negative")
+	check(c.DB.WriteTimeout >= 0, "db.write_timeout must not be negative")
+	check(c.DB.Collation == "" || collationPattern.MatchString(c.DB.Collation), "db.collation %q is not a collation name", c.DB.Collation)
+	check(c.DB.TLS.Mode.Valid(), "db.tls.mode %q must be one of disabled, preferred, skip-verify and required", c.DB.TLS.Mode)
+	check((c.DB.TLS.CertFile == "") == (c.DB.TLS.KeyFile == ""), "db.tls.cert_file and db.tls.key_file must be set together")
+	check(c.DB.StartupTimeout > 0, "db.startup_timeout must be positive")
+	check(c.DB.RetryInitialBackoff > 0, "db.retry_initial_backoff must be positive")
+	check(c.DB.RetryMaxBackoff >= c.DB.RetryInitialBackoff, "db.retry_max_backoff must not be shorter than db.retry_initial_backoff")
+
+	_, err := c.Log.level()
+	check(err == nil, "log.level %q must be one of debug, info, warn and error", c.Log.Level)
+	check(c.Log.Format == "json" || c.Log.Format == "text", "log.format %q must be json or text", c.Log.Format)
+	check(c.Log.AccessSampleRate >= 0 && c.Log.AccessSampleRate <= 1, "log.access_sample_rate must be between 0 and 1")
+
+	check(c.Tracing.Exporter == TracingNone || c.Tracing.Exporter == TracingOTLP,
+		"tracing.exporter %q must be none or otlp", c.Tracing.Exporter)
+	check(c.Tracing.Exporter != TracingOTLP || c.Tracing.Endpoint != "", "tracing.endpoint must not be empty")
+	check(c.Tracing.ServiceName != "", "tracing.service_name must not be empty")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	check(c.Auth.JWT.HS256Secret == "" || len(c.Auth.JWT.HS256Secret) >= 32, "auth.jwt.hs256_secret must be at least 32 bytes")
+	check(c.Auth.JWT.RoleClaim != "", "auth.jwt.role_claim must not be empty")
+
+	check(c.Features.MaxBatchSize >= 1, "features.max_batch_size must be at least 1")
+	check(c.Features.MaxBatchSize <= MaxBatchSizeLimit, "features.max_batch_size must not exceed %d", MaxBatchSizeLimit)
+	check(c.Features.TrashRetention > 0, "features.trash_retention must be positive")
+	check(c.Features.IdempotencyKeyTTL > 0, "features.idempotency_key_ttl must be positive")
+	check(c.Features.ImportJobRetention > 0, "features.import_job_retention must be positive")
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy that is safe to print.
+func (c *Config) Redacted() *Config {
+	r := *c
+	if r.DB.Password != "" {
+		r.DB.Password = redacted
+	}
+	if r.Auth.JWT.HS256Secret != "" {
+		r.Auth.JWT.HS256Secret = redacted
+	}
+	return &r
+}
+
+// WriteRedacted prints the configuration as YAML with secrets redacted.
+func (c *Config) WriteRedacted(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func (l Log) level() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(l.Level))
+	return level, err
+}
+
+// NewHandler returns the slog handler selected by Level and Format.
+func (l Log) NewHandler(w io.Writer) slog.Handler {
+	level, err := l.level()
+	if err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	if l.Format == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
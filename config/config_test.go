@@ -0,0 +1,70 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault_IsValid(t *testing.T) {
+	assert.NoError(t, config.Default().Validate())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := config.Default()
+	cfg.DB.User = ""
+	cfg.DB.MaxOpenConns = 5
+	cfg.DB.MaxIdleConns = 10
+	cfg.Log.Level = "verbose"
+	cfg.Log.AccessSampleRate = 1.5
+	cfg.Features.MaxBatchSize = 0
+	cfg.Server.ExportTimeout = 0
+	cfg.Auth.JWT.HS256Secret = "short"
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.ErrorContains(t, err, "db.user must not be empty")
+	assert.ErrorContains(t, err, "db.max_idle_conns (10) must not exceed db.max_open_conns (5)")
+	assert.ErrorContains(t, err, `log.level "verbose"`)
+	assert.ErrorContains(t, err, "log.access_sample_rate must be between 0 and 1")
+	assert.ErrorContains(t, err, "features.max_batch_size must be at least 1")
+	assert.ErrorContains(t, err, "server.export_timeout must be positive")
+	assert.ErrorContains(t, err, "auth.jwt.hs256_secret must be at least 32 bytes")
+}
+
+func TestConfig_Validate_MaxBatchSizeLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features.MaxBatchSize = config.MaxBatchSizeLimit
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Features.MaxBatchSize = config.MaxBatchSizeLimit + 1
+	assert.ErrorContains(t, cfg.Validate(), "features.max_batch_size must not exceed 21845")
+}
+
+func TestConfig_WriteRedacted(t *testing.T) {
+	cfg := config.Default()
+	cfg.DB.Password = "s3cret"
+	cfg.Auth.JWT.HS256Secret = "0123456789abcdef0123456789abcdef"
+
+	var buf bytes.Buffer
+	err := cfg.WriteRedacted(&buf)
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.NotContains(t, buf.String(), "0123456789abcdef")
+	assert.Contains(t, buf.String(), "hs256_secret: '[REDACTED]'")
+	assert.Contains(t, buf.String(), "password: '[REDACTED]'")
+	assert.Contains(t, buf.String(), "trash_retention: 720h0m0s")
+	assert.Equal(t, "s3cret", cfg.DB.Password)
+}
+
+func TestLog_NewHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := config.Log{Level: "warn", Format: "text"}.NewHandler(&buf)
+
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelWarn))
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setting binds one configuration value to its command-line flag and environment variable.
+type setting struct {
+	flag   string
+	env    string
+	usage  string
+	isBool bool
+	set    func(c *Config, v string) error
+}
+
+func stringSetting(flag, env, usage string, field func(c *Config) *string) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}}
+}
+
+func intSetting(flag, env, usage string, field func(c *Config) *int) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", v)
+		}
+		*field(c) = n
+		return nil
+	}}
+}
+
+func boolSetting(flag, env, usage string, field func(c *Config) *bool) setting {
+	return setting{flag: flag, env: env, usage: usage, isBool: true, set: func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		*field(c) = b
+		return nil
+	}}
+}
+
+func floatSetting(flag, env, usage string, field func(c *Config) *float64) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		*field(c) = f
+		return nil
+	}}
+}
+
+func durationSetting(flag, env, usage string, field func(c *Config) *time.Duration) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", v)
+		}
+		*field(c) = d
+		return nil
+	}}
+}
+
+var settings = []setting{
+	stringSetting("listen-addr", "LISTEN_ADDR", "address the HTTP server listens on",
+		func(c *Config) *string { return &c.Server.Addr }),
+	durationSetting("read-header-timeout", "READ_HEADER_TIMEOUT", "time allowed to read request headers",
+		func(c *Config) *time.Duration { return &c.Server.ReadHeaderTimeout }),
+	durationSetting("read-timeout", "READ_TIMEOUT", "time allowed to read a whole request",
+		func(c *Config) *time.Duration { return &c.Server.ReadTimeout }),
+	durationSetting("write-timeout", "WRITE_TIMEOUT", "time allowed to write a response, 0 for none",
+		func(c *Config) *time.Duration { return &c.Server.WriteTimeout }),
+	durationSetting("idle-timeout", "IDLE_TIMEOUT", "how long idle keep-alive connections stay open",
+		func(c *Config) *time.Duration { return &c.Server.IdleTimeout }),
+	durationSetting("shutdown-timeout", "SHUTDOWN_TIMEOUT", "how long graceful shutdown waits for requests",
+		func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout }),
+	durationSetting("drain-delay", "DRAIN_DELAY", "how long readiness fails before shutdown starts",
+		func(c *Config) *time.Duration { return &c.Server.DrainDelay }),
+	durationSetting("readiness-timeout", "READINESS_TIMEOUT", "time allowed for the checks of one readiness probe",
+		func(c *Config) *time.Duration { return &c.Server.ReadinessTimeout }),
+	durationSetting("request-timeout", "REQUEST_TIMEOUT", "deadline of a request's database work, 0 for none",
+		func(c *Config) *time.Duration { return &c.Server.RequestTimeout }),
+	durationSetting("export-timeout", "EXPORT_TIMEOUT", "deadline of a whole export",
+		func(c *Config) *time.Duration { return &c.Server.ExportTimeout }),
+	intSetting("max-concurrent-exports", "MAX_CONCURRENT_EXPORTS", "exports allowed to run at once",
+		func(c *Config) *int { return &c.Server.MaxConcurrentExports }),
+	intSetting("max-body-bytes", "MAX_BODY_BYTES", "largest accepted JSON request body",
+		func(c *Config) *int { return &c.Server.MaxBodyBytes }),
+
+	stringSetting("db-user", "DB_USER", "MySQL user",
+		func(c *Config) *string { return &c.DB.User }),
+	stringSetting("db-password", "DB_PASSWORD", "MySQL password",
+		func(c *Config) *string { return &c.DB.Password }),
+	stringSetting("db-addr", "DB_ADDR", "MySQL host:port",
+		func(c *Config) *string { return &c.DB.Addr }),
+	stringSetting("db-name", "DB_NAME", "MySQL database",
+		func(c *Config) *string { return &c.DB.Name }),
+	intSetting("db-max-open-conns", "DB_MAX_OPEN_CONNS", "maximum open connections, 0 for unlimited",
+		func(c *Config) *int { return &c.DB.MaxOpenConns }),
+	intSetting("db-max-idle-conns", "DB_MAX_IDLE_CONNS", "maximum idle connections",
+		func(c *Config) *int { return &c.DB.MaxIdleConns }),
+	durationSetting("db-conn-max-lifetime", "DB_CONN_MAX_LIFETIME", "maximum lifetime of a connection, 0 for none",
+		func(c *Config) *time.Duration { return &c.DB.ConnMaxLifetime }),
+	durationSetting("db-conn-max-idle-time", "DB_CONN_MAX_IDLE_TIME", "maximum idle time of a connection, 0 for none",
+		func(c *Config) *time.Duration { return &c.DB.ConnMaxIdleTime }),
+	durationSetting("db-dial-timeout", "DB_DIAL_TIMEOUT", "time allowed to connect to MySQL",
+		func(c *Config) *time.Duration { return &c.DB.DialTimeout }),
+	durationSetting("db-read-timeout", "DB_READ_TIMEOUT", "I/O read timeout of a MySQL connection",
+		func(c *Config) *time.Duration { return &c.DB.ReadTimeout }),
+	durationSetting("db-write-timeout", "DB_WRITE_TIMEOUT", "I/O write timeout of a MySQL connection",
+		func(c *Config) *time.Duration { return &c.DB.WriteTimeout }),
+	stringSetting("db-collation", "DB_COLLATION", "collation of MySQL connections",
+		func(c *Config) *string { return &c.DB.Collation }),
+	stringSetting("db-tls-mode", "DB_TLS_MODE", "disabled, preferred, skip-verify or required",
+		func(c *Config) *string { return (*string)(&c.DB.TLS.Mode) }),
+	stringSetting("db-tls-server-name", "DB_TLS_SERVER_NAME", "expected MySQL certificate name, defaults to the host",
+		func(c *Config) *string { return &c.DB.TLS.ServerName }),
+	stringSetting("db-tls-ca-file", "DB_TLS_CA_FILE", "PEM file of CAs trusted for MySQL",
+		func(c *Config) *string { return &c.DB.TLS.CAFile }),
+	stringSetting("db-tls-cert-file", "DB_TLS_CERT_FILE", "PEM client certificate for MySQL",
+		func(c *Config) *string { return &c.DB.TLS.CertFile }),
+	stringSetting("db-tls-key-file", "DB_TLS_KEY_FILE", "PEM client key for MySQL",
+		func(c *Config) *string { return &c.DB.TLS.KeyFile }),
+	durationSetting("db-startup-timeout", "DB_STARTUP_TIMEOUT", "how long startup waits for MySQL",
+		func(c *Config) *time.Duration { return &c.DB.StartupTimeout }),
+	durationSetting("db-retry-initial-backoff", "DB_RETRY_INITIAL_BACKOFF", "first pause between startup attempts",
+		func(c *Config) *time.Duration { return &c.DB.RetryInitialBackoff }),
+	durationSetting("db-retry-max-backoff", "DB_RETRY_MAX_BACKOFF", "longest pause between startup attempts",
+		func(c *Config) *time.Duration { return &c.DB.RetryMaxBackoff }),
+
+	stringSetting("log-level", "LOG_LEVEL", "debug, info, warn or error",
+		func(c *Config) *string { return &c.Log.Level }),
+	stringSetting("log-format", "LOG_FORMAT", "json or text",
+		func(c *Config) *string { return &c.Log.Format }),
+	floatSetting("log-access-sample-rate", "LOG_ACCESS_SAMPLE_RATE", "share of successful requests in the access log, 0 to 1",
+		func(c *Config) *float64 { return &c.Log.AccessSampleRate }),
+
+	stringSetting("tracing-exporter", "TRACING_EXPORTER", "none or otlp",
+		func(c *Config) *string { return (*string)(&c.Tracing.Exporter) }),
+	stringSetting("tracing-endpoint", "TRACING_ENDPOINT", "host:port of the OTLP/HTTP receiver",
+		func(c *Config) *string { return &c.Tracing.Endpoint }),
+	boolSetting("tracing-insecure", "TRACING_INSECURE", "send spans over plain HTTP",
+		func(c *Config) *bool { return &c.Tracing.Insecure }),
+	stringSetting("tracing-service-name", "TRACING_SERVICE_NAME", "service.name of the recorded spans",
+		func(c *Config) *string { return &c.Tracing.ServiceName }),
+	floatSetting("tracing-sample-ratio", "TRACING_SAMPLE_RATIO", "share of new traces recorded, 0 to 1",
+		func(c *Config) *float64 { return &c.Tracing.SampleRatio }),
+
+	boolSetting("auth-enabled", "AUTH_ENABLED", "require an API key or bearer token on every route but health and metrics",
+		func(c *Config) *bool { return &c.Auth.Enabled }),
+	stringSetting("jwt-hs256-secret", "JWT_HS256_SECRET", "shared secret of HS256 bearer tokens",
+		func(c *Config) *string { return &c.Auth.JWT.HS256Secret }),
+	stringSetting("jwt-rs256-public-key-file", "JWT_RS256_PUBLIC_KEY_FILE", "PEM public key of RS256 bearer tokens",
+		func(c *Config) *string { return &c.Auth.JWT.RS256PublicKeyFile }),
+	stringSetting("jwt-jwks-file", "JWT_JWKS_FILE", "JSON Web Key Set of RS256 bearer tokens",
+		func(c *Config) *string { return &c.Auth.JWT.JWKSFile }),
+	stringSetting("jwt-issuer", "JWT_ISSUER", "required iss of bearer tokens",
+		func(c *Config) *string { return &c.Auth.JWT.Issuer }),
+	stringSetting("jwt-audience", "JWT_AUDIENCE", "required aud of bearer tokens",
+		func(c *Config) *string { return &c.Auth.JWT.Audience }),
+	stringSetting("jwt-role-claim", "JWT_ROLE_CLAIM", "claim holding the role of a bearer token",
+		func(c *Config) *string { return &c.Auth.JWT.RoleClaim }),
+
+	boolSetting("import-mode", "IMPORT_MODE", "accept client-supplied ids on create",
+		func(c *Config) *bool { return &c.Features.ImportMode }),
+	boolSetting("require-if-match", "REQUIRE_IF_MATCH", "reject updates and deletes without If-Match",
+		func(c *Config) *bool { return &c.Features.RequireIfMatch }),
+	intSetting("batch-max-size", "BATCH_MAX_SIZE", "maximum operations in one batch request",
+		func(c *Config) *int { return &c.Features.MaxBatchSize }),
+	durationSetting("trash-retention", "TRASH_RETENTION", "how long soft-deleted rows stay restorable",
+		func(c *Config) *time.Duration { return &c.Features.TrashRetention }),
+	durationSetting("idempotency-key-ttl", "IDEMPOTENCY_KEY_TTL", "how long an Idempotency-Key is remembered",
+		func(c *Config) *time.Duration { return &c.Features.IdempotencyKeyTTL }),
+	durationSetting("import-job-retention", "IMPORT_JOB_RETENTION", "how long a finished import can be polled",
+		func(c *Config) *time.Duration { return &c.Features.ImportJobRetention }),
+}
+
+// Load builds the configuration from the defaults, an optional YAML or JSON file, environment
+// variables and command-line flags, each overriding the previous one, and validates the result.
+// printConfig reports whether --print-config was given.
+func Load(args []string, getenv func(string) string) (cfg *Config, printConfig bool, err error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	path := fs.String("config", getenv("CONFIG_FILE"), "path to a YAML or JSON config file (env CONFIG_FILE)")
+	fs.BoolVar(&printConfig, "print-config", false, "print the effective configuration with secrets redacted and exit")
+
+	// Flags are applied after the file and the environment, so only record them while parsing.
+	var flagged []func(c *Config) error
+	for _, s := range settings {
+		record := func(v string) error {
+			if err := s.set(Default(), v); err != nil {
+				return err
+			}
+			flagged = append(flagged, func(c *Config) error { return s.set(c, v) })
+			return nil
+		}
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env)
+		if s.isBool {
+			fs.BoolFunc(s.flag, usage, record)
+		} else {
+			fs.Func(s.flag, usage, record)
+		}
+	}
+	if err = fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+
+	cfg = Default()
+	if *path != "" {
+		if err = loadFile(cfg, *path); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			if err = s.set(cfg, v); err != nil {
+				return nil, false, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+	for _, apply := range flagged {
+		if err = apply(cfg); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, printConfig, nil
+}
+
+// loadFile overlays the file onto cfg. JSON is a subset of YAML, so one decoder reads both.
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err = dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func env(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, printConfig, err := config.Load(nil, env(nil))
+	assert.NoError(t, err)
+	assert.False(t, printConfig)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+db:
+  user: file-user
+  addr: db:3306
+features:
+  trash_retention: 48h
+`)
+
+	cfg, _, err := config.Load(
+		[]string{"-config", path, "-db-user", "flag-user", "-import-mode"},
+		env(map[string]string{"DB_USER": "env-user", "DB_NAME": "env-db", "TRASH_RETENTION": "72h"}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Addr)
+	assert.Equal(t, "db:3306", cfg.DB.Addr)
+	assert.Equal(t, "flag-user", cfg.DB.User)
+	assert.Equal(t, "env-db", cfg.DB.Name)
+	assert.Equal(t, 72*time.Hour, cfg.Features.TrashRetention)
+	assert.True(t, cfg.Features.ImportMode)
+	// untouched values keep their defaults
+	assert.Equal(t, 1000, cfg.Features.MaxBatchSize)
+}
+
+func TestLoad_JSONFileFromEnv(t *testing.T) {
+	path := writeFile(t, "config.json", `{"log": {"level": "info", "format": "text"}, "features": {"require_if_match": false}}`)
+
+	cfg, _, err := config.Load(nil, env(map[string]string{"CONFIG_FILE": path}))
+	assert.NoError(t, err)
+	assert.Equal(t, config.Log{Level: "info", Format: "text", AccessSampleRate: 1}, cfg.Log)
+	assert.False(t, cfg.Features.RequireIfMatch)
+}
+
+func TestLoad_PrintConfig(t *testing.T) {
+	_, printConfig, err := config.Load([]string{"--print-config"}, env(nil))
+	assert.NoError(t, err)
+	assert.True(t, printConfig)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		message string
+	}{
+		{name: "invalid env", env: map[string]string{"BATCH_MAX_SIZE": "many"}, message: `invalid BATCH_MAX_SIZE: "many" is not an integer`},
+		{name: "invalid flag", args: []string{"-trash-retention", "forever"}, message: `"forever" is not a duration`},
+		{name: "unknown file field", file: "db:\n  pasword: x\n", message: "field pasword not found"},
+		{name: "failed validation", env: map[string]string{"LOG_FORMAT": "xml"}, message: `invalid config: log.format "xml" must be json or text`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := c.args
+			if c.file != "" {
+				args = append(args, "-config", writeFile(t, "config.yaml", c.file))
+			}
+			_, _, err := config.Load(args, env(c.env))
+			assert.ErrorContains(t, err, c.message)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// actionID parses a "{id}:{action}" path segment such as "12:restore". ServeMux wildcards
+// must span a whole segment, so custom methods are routed on {id} and split here. On
+// failure the problem response has already been written.
+func actionID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	raw, ok := strings.CutSuffix(r.PathValue("id"), ":"+action)
+	if !ok {
+		writeProblem(w, r, http.StatusNotFound, "unknown action", nil)
+		return 0, false
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		badRequestHandler(w, r, fmt.Errorf("invalid path param: %w", err))
+		return 0, false
+	}
+	return id, true
+}
@@ -2,12 +2,12 @@ package controller
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	"github.com/go-sql-driver/mysql"
 	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
 	"github.com/pulse227/server-recruit-challenge-sample/service"
-	"log/slog"
+	"io"
 	"net/http"
 	"strconv"
 )
@@ -16,32 +16,44 @@ type AlbumController interface {
 	GetAlbums(w http.ResponseWriter, r *http.Request)
 	GetAlbum(w http.ResponseWriter, r *http.Request)
 	CreateAlbum(w http.ResponseWriter, r *http.Request)
+	UpdateAlbum(w http.ResponseWriter, r *http.Request)
+	PatchAlbum(w http.ResponseWriter, r *http.Request)
 	DeleteAlbum(w http.ResponseWriter, r *http.Request)
+	RestoreAlbum(w http.ResponseWriter, r *http.Request)
+	BatchAlbums(w http.ResponseWriter, r *http.Request)
+	ExportAlbums(w http.ResponseWriter, r *http.Request)
 }
 type albumController struct {
 	service service.AlbumService
+	options Options
 }
 
 var _ AlbumController = (*albumController)(nil)
 
-func NewAlbumController(s service.AlbumService) AlbumController {
-	return &albumController{service: s}
+func NewAlbumController(s service.AlbumService, options Options) AlbumController {
+	return &albumController{service: s, options: options}
 }
 
 // GetAlbums GET /albums
 func (a albumController) GetAlbums(w http.ResponseWriter, r *http.Request) {
-	albums, err := a.service.GetAlbumListService(r.Context())
+	query, err := dto.NewAlbumQuery(r.URL.Query())
 	if err != nil {
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+		badRequestHandler(w, r, err)
+		return
+	}
+	page, err := a.service.GetAlbumListService(r.Context(), query)
+	if err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setNextLink(w, r, page.NextCursor)
 	w.WriteHeader(http.StatusOK)
 
-	res := dto.NewAlbumsResponse(albums)
+	res := dto.NewAlbumListResponse(page)
 	if err = json.NewEncoder(w).Encode(res); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
 		return
 	}
 }
@@ -51,22 +63,40 @@ func (a albumController) GetAlbum(w http.ResponseWriter, r *http.Request) {
 	ID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		err = fmt.Errorf("invalid path param: %w", err)
-		errorHandler(w, r, http.StatusBadRequest, err.Error())
+		badRequestHandler(w, r, err)
 		return
 	}
 	req := dto.GetAlbumRequest{ID: ID}
 	albumID := req.ToModel()
-	album, err := a.service.GetAlbumService(r.Context(), *albumID)
-
+	include, err := dto.ParseInclude(r.URL.Query(), "tracks")
 	if err != nil {
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+		badRequestHandler(w, r, err)
 		return
 	}
+	var album *model.Album
+	if include["tracks"] {
+		album, err = a.service.GetAlbumWithTracksService(r.Context(), *albumID)
+	} else {
+		album, err = a.service.GetAlbumService(r.Context(), *albumID)
+	}
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	// The tag covers the album row and the singer's name, not tracks, so a representation
+	// with tracks gets no ETag.
+	if len(include) == 0 {
+		w.Header().Set("ETag", albumETag(album))
+		if notModified(r, albumETag(album)) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	res := dto.NewAlbumResponse(album)
 	if err = json.NewEncoder(w).Encode(res); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
 		return
 	}
 }
@@ -74,28 +104,112 @@ func (a albumController) GetAlbum(w http.ResponseWriter, r *http.Request) {
 // CreateAlbum POST /albums
 func (a albumController) CreateAlbum(w http.ResponseWriter, r *http.Request) {
 	req := dto.CreateAlbumRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		errorHandler(w, r, http.StatusBadRequest, err.Error())
+	if err := decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	if err := a.options.rejectClientID(req.ID); err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 
 	album := req.ToModel()
 	if err := a.service.PostAlbumService(r.Context(), album); err != nil {
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
-			err = fmt.Errorf("album ID already exists: %w", err)
-			errorHandler(w, r, http.StatusConflict, err.Error())
-			return
-		}
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+		errorHandler(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/albums/%d", album.ID))
+	w.Header().Set("ETag", albumETag(album))
 	w.WriteHeader(http.StatusCreated)
 	res := dto.NewCreateAlbumResponse(album)
 	if err := json.NewEncoder(w).Encode(res); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// UpdateAlbum PUT /albums/{id}
+func (a albumController) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	version, ok := a.options.ifMatch(w, r, currentAlbum(r.Context(), a.service.GetAlbumService, model.AlbumID(ID)))
+	if !ok {
+		return
+	}
+	req := dto.UpdateAlbumRequest{}
+	if err = decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	a.updateAlbum(w, r, req.ToModel(ID), version)
+}
+
+// PatchAlbum PATCH /albums/{id}
+func (a albumController) PatchAlbum(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	current, err := a.service.GetAlbumService(r.Context(), model.AlbumID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	// the patch applies to current, so that is what If-Match is checked against
+	version, ok := a.options.ifMatch(w, r, func() (string, int64, error) {
+		return albumETag(current), current.Version, nil
+	})
+	if !ok {
+		return
+	}
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		err = fmt.Errorf("invalid body param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	target, err := json.Marshal(dto.NewUpdateAlbumRequest(current))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	merged, err := dto.ApplyMergePatch(target, patch)
+	if err != nil {
+		err = fmt.Errorf("invalid body param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	req := dto.UpdateAlbumRequest{}
+	if err = decodeJSON(merged, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	a.updateAlbum(w, r, req.ToModel(ID), version)
+}
+
+func (a albumController) updateAlbum(w http.ResponseWriter, r *http.Request, album *model.Album, version int64) {
+	album.Version = version
+	if err := a.service.UpdateAlbumService(r.Context(), album); err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", albumETag(album))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewUpdateAlbumResponse(album)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
 		return
 	}
 }
@@ -105,15 +219,85 @@ func (a albumController) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
 	ID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		err = fmt.Errorf("invalid path param: %w", err)
-		errorHandler(w, r, http.StatusBadRequest, err.Error())
+		badRequestHandler(w, r, err)
+		return
+	}
+	version, ok := a.options.ifMatch(w, r, currentAlbum(r.Context(), a.service.GetAlbumService, model.AlbumID(ID)))
+	if !ok {
 		return
 	}
 	req := dto.DeleteAlbumRequest{ID: ID}
 	albumID := req.ToModel()
-	if err = a.service.DeleteAlbumService(r.Context(), *albumID); err != nil {
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+	if err = a.service.DeleteAlbumService(r.Context(), *albumID, version); err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RestoreAlbum POST /albums/{id}:restore
+func (a albumController) RestoreAlbum(w http.ResponseWriter, r *http.Request) {
+	ID, ok := actionID(w, r, "restore")
+	if !ok {
+		return
+	}
+	album, err := a.service.RestoreAlbumService(r.Context(), model.AlbumID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewAlbumResponse(album)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// BatchAlbums POST /albums:batch
+func (a albumController) BatchAlbums(w http.ResponseWriter, r *http.Request) {
+	req := dto.BatchRequest[dto.AlbumOperationRequest]{}
+	if err := decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	mode, ops, err := batchOperations[*model.Album](a.options, &req)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	results, err := a.service.BatchAlbumService(r.Context(), mode, ops)
+	if err != nil {
+		batchErrorHandler(w, r, err)
+		return
+	}
+	writeBatchResults(w, r, results)
+}
+
+// ExportAlbums GET /exports/albums
+func (a albumController) ExportAlbums(w http.ResponseWriter, r *http.Request) {
+	q, err := dto.NewAlbumExportQuery(r.URL.Query())
+	if err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	format, ok := negotiateExport(w, r)
+	if !ok {
+		return
+	}
+
+	export := newExportWriter(w, r, format, "albums", dto.AlbumExportHeader)
+	defer export.close(r)
+	err = a.service.ExportAlbumsService(r.Context(), q, func(album *model.Album) error {
+		return export.write(dto.NewAlbumExportRow(album))
+	})
+	if err != nil {
+		export.fail(r, err)
+		return
+	}
+	export.finish(r)
+}
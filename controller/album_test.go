@@ -3,9 +3,12 @@ package controller_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/pulse227/server-recruit-challenge-sample/controller"
 	"github.com/pulse227/server-recruit-challenge-sample/dto"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
@@ -22,12 +25,12 @@ func NewMockAlbumService() *MockAlbumService {
 	return &MockAlbumService{}
 }
 
-func (m *MockAlbumService) GetAlbumListService(ctx context.Context) ([]*model.Album, error) {
-	args := m.Called(ctx)
+func (m *MockAlbumService) GetAlbumListService(ctx context.Context, q *model.AlbumQuery) (*model.Page[*model.Album], error) {
+	args := m.Called(ctx, q)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Album), args.Error(1)
+	return args.Get(0).(*model.Page[*model.Album]), args.Error(1)
 }
 
 func (m *MockAlbumService) GetAlbumService(ctx context.Context, albumID model.AlbumID) (*model.Album, error) {
@@ -38,6 +41,14 @@ func (m *MockAlbumService) GetAlbumService(ctx context.Context, albumID model.Al
 	return args.Get(0).(*model.Album), args.Error(1)
 }
 
+func (m *MockAlbumService) GetAlbumWithTracksService(ctx context.Context, albumID model.AlbumID) (*model.Album, error) {
+	args := m.Called(ctx, albumID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Album), args.Error(1)
+}
+
 func (m *MockAlbumService) PostAlbumService(ctx context.Context, album *model.Album) error {
 	args := m.Called(ctx, album)
 	if err, ok := args.Get(0).(error); ok {
@@ -46,14 +57,50 @@ func (m *MockAlbumService) PostAlbumService(ctx context.Context, album *model.Al
 	return nil
 }
 
-func (m *MockAlbumService) DeleteAlbumService(ctx context.Context, albumID model.AlbumID) error {
-	args := m.Called(ctx, albumID)
+func (m *MockAlbumService) UpdateAlbumService(ctx context.Context, album *model.Album) error {
+	args := m.Called(ctx, album)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockAlbumService) DeleteAlbumService(ctx context.Context, albumID model.AlbumID, version int64) error {
+	args := m.Called(ctx, albumID, version)
 	if err, ok := args.Get(0).(error); ok {
 		return err
 	}
 	return nil
 }
 
+func (m *MockAlbumService) RestoreAlbumService(ctx context.Context, albumID model.AlbumID) (*model.Album, error) {
+	args := m.Called(ctx, albumID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Album), args.Error(1)
+}
+
+func (m *MockAlbumService) BatchAlbumService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Album]) ([]*model.BatchResult, error) {
+	args := m.Called(ctx, mode, ops)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BatchResult), args.Error(1)
+}
+
+func (m *MockAlbumService) ExportAlbumsService(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) error {
+	args := m.Called(ctx, q)
+	if albums, ok := args.Get(0).([]*model.Album); ok {
+		for _, album := range albums {
+			if err := fn(album); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 type AlbumControllerSuite struct {
 	suite.Suite
 	albumController  controller.AlbumController
@@ -65,7 +112,7 @@ func TestAlbumControllerTestSuite(t *testing.T) {
 }
 func (suite *AlbumControllerSuite) SetupTest() {
 	suite.mockAlbumService = NewMockAlbumService()
-	suite.albumController = controller.NewAlbumController(suite.mockAlbumService)
+	suite.albumController = controller.NewAlbumController(suite.mockAlbumService, controller.Options{})
 }
 
 func (suite *AlbumControllerSuite) TestGetAlbums_Success() {
@@ -91,14 +138,19 @@ func (suite *AlbumControllerSuite) TestGetAlbums_Success() {
 		},
 	}
 
-	suite.mockAlbumService.On("GetAlbumListService", req.Context()).Return(albums, nil)
+	q := &model.AlbumQuery{Limit: model.DefaultPageLimit, Sort: model.AlbumSortID}
+	page := &model.Page[*model.Album]{Items: albums}
+	suite.mockAlbumService.On("GetAlbumListService", req.Context(), q).Return(page, nil)
 	suite.albumController.GetAlbums(rr, req)
 
 	suite.Equal(http.StatusOK, rr.Code)
+	suite.Empty(rr.Header().Get("Link"))
 
-	var res []*dto.AlbumResponse
-	err := json.NewDecoder(rr.Body).Decode(&res)
+	var list dto.AlbumListResponse
+	err := json.NewDecoder(rr.Body).Decode(&list)
 	suite.NoError(err)
+	suite.Empty(list.NextCursor)
+	res := list.Items
 
 	suite.Len(res, 2)
 	suite.Equal(1, res[0].ID)
@@ -147,8 +199,17 @@ func (suite *AlbumControllerSuite) TestGetAlbum_Success() {
 	//suite.mockAlbumService.AssertExpectations(suite.T())
 }
 
+// stored mimics the service leaving the album as stored, with the singer its ETag covers.
+func stored(version, singerVersion int64) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		album := args.Get(1).(*model.Album)
+		album.Version = version
+		album.Singer = &model.Singer{ID: album.SingerID, Version: singerVersion}
+	}
+}
+
 func (suite *AlbumControllerSuite) TestCreateAlbum_Success() {
-	body := `{"id":1,"title":"New Album","singer_id":1}`
+	body := `{"title":"New Album","singer_id":1}`
 	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
 	rr := httptest.NewRecorder()
 
@@ -156,22 +217,107 @@ func (suite *AlbumControllerSuite) TestCreateAlbum_Success() {
 	err := json.NewDecoder(strings.NewReader(body)).Decode(&album)
 	suite.NoError(err)
 
-	suite.mockAlbumService.On("PostAlbumService", req.Context(), album.ToModel()).Return(nil)
+	suite.mockAlbumService.On("PostAlbumService", req.Context(), album.ToModel()).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Album).ID = model.AlbumID(10)
+			stored(1, 3)(args)
+		}).
+		Return(nil)
 	suite.albumController.CreateAlbum(rr, req)
 
 	suite.Equal(http.StatusCreated, rr.Code)
+	suite.Equal("/albums/10", rr.Header().Get("Location"))
+	// the same tag GET /albums/10 would send
+	suite.Equal(`"1-3"`, rr.Header().Get("ETag"))
 
 	var res dto.CreateAlbumResponse
 	err = json.NewDecoder(rr.Body).Decode(&res)
 	suite.NoError(err)
 
-	suite.Equal(1, res.ID)
+	suite.Equal(10, res.ID)
 	suite.Equal("New Album", res.Title)
 	suite.Equal(1, res.SingerID)
 
 	suite.mockAlbumService.AssertExpectations(suite.T())
 }
 
+func (suite *AlbumControllerSuite) TestCreateAlbum_ClientIDRejected() {
+	body := `{"id":1,"title":"New Album","singer_id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	suite.albumController.CreateAlbum(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal([]controller.ProblemField{
+		{Field: "id", Code: model.CodeReadOnly, Message: "id is assigned by the server"},
+	}, res.Errors)
+
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "PostAlbumService", mock.Anything, mock.Anything)
+}
+
+func (suite *AlbumControllerSuite) TestCreateAlbum_ImportMode() {
+	albumController := controller.NewAlbumController(suite.mockAlbumService, controller.Options{ImportMode: true})
+
+	body := `{"id":1,"title":"New Album","singer_id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{ID: model.AlbumID(1), Title: "New Album", SingerID: model.SingerID(1)}
+	suite.mockAlbumService.On("PostAlbumService", req.Context(), album).Run(stored(1, 1)).Return(nil)
+	albumController.CreateAlbum(rr, req)
+
+	suite.Equal(http.StatusCreated, rr.Code)
+	suite.Equal("/albums/1", rr.Header().Get("Location"))
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbum_IncludeTracks() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/1?include=tracks", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{
+		ID:     model.AlbumID(1),
+		Title:  "Alice's 1st Album",
+		Singer: &model.Singer{ID: model.SingerID(1), Name: "Alice"},
+		Tracks: []*model.Track{
+			{ID: model.TrackID(1), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "Opening", DurationSeconds: 95},
+			{ID: model.TrackID(2), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 2, Title: "Closing", DurationSeconds: 240},
+		},
+	}
+	suite.mockAlbumService.On("GetAlbumWithTracksService", req.Context(), model.AlbumID(1)).Return(album, nil)
+	suite.albumController.GetAlbum(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.AlbumResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Len(res.Tracks, 2)
+	suite.Equal("Opening", res.Tracks[0].Title)
+	suite.Require().NotNil(res.TotalDurationSeconds)
+	suite.Equal(335, *res.TotalDurationSeconds)
+
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "GetAlbumService", mock.Anything, mock.Anything)
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbum_UnknownInclude() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/1?include=lyrics", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	suite.albumController.GetAlbum(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "GetAlbumService", mock.Anything, mock.Anything)
+}
+
 func (suite *AlbumControllerSuite) TestDeleteAlbum_Success() {
 	//req := httptest.NewRequest(http.MethodDelete, "/albums/1", nil)
 	//rr := httptest.NewRecorder()
@@ -186,3 +332,562 @@ func (suite *AlbumControllerSuite) TestDeleteAlbum_Success() {
 	//
 	//suite.mockAlbumService.AssertExpectations(suite.T())
 }
+
+func (suite *AlbumControllerSuite) TestUpdateAlbum_Success() {
+	body := `{"title":"Updated Album","singer_id":2}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{ID: model.AlbumID(1), Title: "Updated Album", SingerID: model.SingerID(2)}
+	suite.mockAlbumService.On("UpdateAlbumService", req.Context(), album).Run(stored(2, 1)).Return(nil)
+	suite.albumController.UpdateAlbum(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.UpdateAlbumResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+
+	suite.Equal(1, res.ID)
+	suite.Equal("Updated Album", res.Title)
+	suite.Equal(2, res.SingerID)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbum_ETag() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/1", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "Album 1",
+		SingerID: model.SingerID(1),
+		Singer:   &model.Singer{ID: model.SingerID(1), Name: "Singer 1", Version: 2},
+		Version:  3,
+	}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(album, nil)
+	suite.albumController.GetAlbum(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal(`"3-2"`, rr.Header().Get("ETag"))
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbum_NotModified() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/1", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-None-Match", `"2-2", W/"3-2"`)
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "Album 1",
+		SingerID: model.SingerID(1),
+		Singer:   &model.Singer{ID: model.SingerID(1), Name: "Singer 1", Version: 2},
+		Version:  3,
+	}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(album, nil)
+	suite.albumController.GetAlbum(rr, req)
+
+	suite.Equal(http.StatusNotModified, rr.Code)
+	suite.Equal(`"3-2"`, rr.Header().Get("ETag"))
+	suite.Empty(rr.Body.String())
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbum_SingerRenamed() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/1", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-None-Match", `"3-2"`)
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "Album 1",
+		SingerID: model.SingerID(1),
+		Singer:   &model.Singer{ID: model.SingerID(1), Name: "Renamed Singer", Version: 3},
+		Version:  3,
+	}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(album, nil)
+	suite.albumController.GetAlbum(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal(`"3-3"`, rr.Header().Get("ETag"))
+	suite.Contains(rr.Body.String(), "Renamed Singer")
+}
+
+func (suite *AlbumControllerSuite) TestUpdateAlbum_IfMatch() {
+	body := `{"title":"Updated Album","singer_id":2}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `"3-1", "4-2"`)
+	rr := httptest.NewRecorder()
+
+	current := &model.Album{ID: model.AlbumID(1), SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1), Version: 2}, Version: 4}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(current, nil)
+	album := &model.Album{ID: model.AlbumID(1), Title: "Updated Album", SingerID: model.SingerID(2), Version: 4}
+	suite.mockAlbumService.On("UpdateAlbumService", req.Context(), album).Run(stored(5, 6)).Return(nil)
+	suite.albumController.UpdateAlbum(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal(`"5-6"`, rr.Header().Get("ETag"))
+}
+
+func (suite *AlbumControllerSuite) TestUpdateAlbum_VersionMismatch() {
+	body := `{"title":"Updated Album","singer_id":2}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `"1-1"`)
+	rr := httptest.NewRecorder()
+
+	// the album changed between the check and the write
+	current := &model.Album{ID: model.AlbumID(1), SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1), Version: 1}, Version: 1}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(current, nil)
+	album := &model.Album{ID: model.AlbumID(1), Title: "Updated Album", SingerID: model.SingerID(2), Version: 1}
+	suite.mockAlbumService.On("UpdateAlbumService", req.Context(), album).Return(repository.ErrorAlbumVersionMismatch)
+	suite.albumController.UpdateAlbum(rr, req)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+	suite.Contains(rr.Body.String(), "/problems/precondition-failed")
+}
+
+func (suite *AlbumControllerSuite) TestUpdateAlbum_StaleSingerVersion() {
+	body := `{"title":"Updated Album","singer_id":2}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	// the tag a client got before the singer was renamed
+	req.Header.Set("If-Match", `"4-1"`)
+	rr := httptest.NewRecorder()
+
+	current := &model.Album{ID: model.AlbumID(1), SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1), Version: 2}, Version: 4}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(current, nil)
+	suite.albumController.UpdateAlbum(rr, req)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "UpdateAlbumService", mock.Anything, mock.Anything)
+}
+
+func (suite *AlbumControllerSuite) TestDeleteAlbum_IfMatchRequired() {
+	albumController := controller.NewAlbumController(suite.mockAlbumService, controller.Options{RequireIfMatch: true})
+	req := httptest.NewRequest(http.MethodDelete, "/albums/1", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	albumController.DeleteAlbum(rr, req)
+
+	suite.Equal(http.StatusPreconditionRequired, rr.Code)
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "DeleteAlbumService", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AlbumControllerSuite) TestDeleteAlbum_IfMatch() {
+	cases := []struct {
+		ifMatch string
+		version int64
+		status  int
+	}{
+		{ifMatch: `"2-7"`, version: 2, status: http.StatusNoContent},
+		{ifMatch: `"1-7", "2-7"`, version: 2, status: http.StatusNoContent},
+		{ifMatch: `*`, version: 0, status: http.StatusNoContent},
+		{ifMatch: `"2"`, status: http.StatusPreconditionFailed},
+		{ifMatch: `"2-6"`, status: http.StatusPreconditionFailed},
+		{ifMatch: `W/"2-7"`, status: http.StatusPreconditionFailed},
+		{ifMatch: `"abc"`, status: http.StatusPreconditionFailed},
+	}
+	current := &model.Album{ID: model.AlbumID(1), SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1), Version: 7}, Version: 2}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodDelete, "/albums/1", nil)
+		req.SetPathValue("id", "1")
+		req.Header.Set("If-Match", c.ifMatch)
+		rr := httptest.NewRecorder()
+
+		suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(current, nil).Once()
+		suite.mockAlbumService.On("DeleteAlbumService", req.Context(), model.AlbumID(1), c.version).Return(nil).Once()
+		albumController := controller.NewAlbumController(suite.mockAlbumService, controller.Options{RequireIfMatch: true})
+		albumController.DeleteAlbum(rr, req)
+
+		suite.Equal(c.status, rr.Code, c.ifMatch)
+	}
+}
+
+func (suite *AlbumControllerSuite) TestUpdateAlbum_SingerNotFound() {
+	body := `{"title":"Updated Album","singer_id":99}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{ID: model.AlbumID(1), Title: "Updated Album", SingerID: model.SingerID(99)}
+	suite.mockAlbumService.On("UpdateAlbumService", req.Context(), album).
+		Return(repository.ErrorReferencedSingerNotFound)
+	suite.albumController.UpdateAlbum(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestPatchAlbum_Success() {
+	body := `{"title":"Patched Album"}`
+	req := httptest.NewRequest(http.MethodPatch, "/albums/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	current := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "Old Album",
+		SingerID: model.SingerID(2),
+		Singer:   &model.Singer{ID: model.SingerID(2), Name: "Singer 2"},
+	}
+	patched := &model.Album{ID: model.AlbumID(1), Title: "Patched Album", SingerID: model.SingerID(2)}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(1)).Return(current, nil)
+	suite.mockAlbumService.On("UpdateAlbumService", req.Context(), patched).Run(stored(2, 1)).Return(nil)
+	suite.albumController.PatchAlbum(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.UpdateAlbumResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+
+	suite.Equal(1, res.ID)
+	suite.Equal("Patched Album", res.Title)
+	suite.Equal(2, res.SingerID)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestPatchAlbum_NotFound() {
+	req := httptest.NewRequest(http.MethodPatch, "/albums/99", strings.NewReader(`{"title":"Patched Album"}`))
+	req.SetPathValue("id", "99")
+	rr := httptest.NewRecorder()
+
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(99)).
+		Return(nil, repository.ErrorAlbumNotFound)
+	suite.albumController.PatchAlbum(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestCreateAlbum_Conflict() {
+	albumController := controller.NewAlbumController(suite.mockAlbumService, controller.Options{ImportMode: true})
+
+	body := `{"id":1,"title":"New Album","singer_id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{ID: model.AlbumID(1), Title: "New Album", SingerID: model.SingerID(1)}
+	suite.mockAlbumService.On("PostAlbumService", req.Context(), album).
+		Return(fmt.Errorf("%w: %w", repository.ErrorAlbumAlreadyExists, errors.New("Error 1062: Duplicate entry '1' for key 'albums.PRIMARY'")))
+	albumController.CreateAlbum(rr, req)
+
+	suite.Equal(http.StatusConflict, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("album ID already exists", res.Detail)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbums_InternalErrorIsNotLeaked() {
+	req := httptest.NewRequest(http.MethodGet, "/albums", nil)
+	rr := httptest.NewRecorder()
+
+	suite.mockAlbumService.On("GetAlbumListService", req.Context(), mock.Anything).
+		Return(nil, model.NewError(model.KindInternal, "database error", errors.New("Error 1146: Table 'myapp.albums' doesn't exist")))
+	suite.albumController.GetAlbums(rr, req)
+
+	suite.Equal(http.StatusInternalServerError, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal(http.StatusText(http.StatusInternalServerError), res.Detail)
+	suite.NotContains(rr.Body.String(), "myapp.albums")
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestDeleteAlbum_NotFound() {
+	req := httptest.NewRequest(http.MethodDelete, "/albums/99", nil)
+	req.SetPathValue("id", "99")
+	rr := httptest.NewRecorder()
+
+	suite.mockAlbumService.On("DeleteAlbumService", req.Context(), model.AlbumID(99), int64(0)).
+		Return(repository.ErrorAlbumNotFound)
+	suite.albumController.DeleteAlbum(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestCreateAlbum_ValidationProblem() {
+	body := `{"title":"","singer_id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	req.Header.Set("X-Request-ID", "req-123")
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{Title: "", SingerID: model.SingerID(1)}
+	suite.mockAlbumService.On("PostAlbumService", req.Context(), album).Return(album.Validate())
+	suite.albumController.CreateAlbum(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+	suite.Equal("application/problem+json", rr.Header().Get("Content-Type"))
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal(http.StatusUnprocessableEntity, res.Status)
+	suite.Equal("/albums", res.Instance)
+	suite.Equal("req-123", res.RequestID)
+	suite.Equal([]controller.ProblemField{
+		{Field: "title", Code: model.CodeRequired, Message: "title is required"},
+	}, res.Errors)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestCreateAlbum_UnknownField() {
+	body := `{"title":"New Album","singer_id":1,"genre":"pop"}`
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	suite.albumController.CreateAlbum(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal([]controller.ProblemField{
+		{Field: "genre", Code: model.CodeUnknownField, Message: `unknown field "genre"`},
+	}, res.Errors)
+
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "PostAlbumService", mock.Anything, mock.Anything)
+}
+
+func (suite *AlbumControllerSuite) TestCreateAlbum_InvalidType() {
+	body := `{"title":"New Album","singer_id":"one"}`
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	suite.albumController.CreateAlbum(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Len(res.Errors, 1)
+	suite.Equal("singer_id", res.Errors[0].Field)
+	suite.Equal(model.CodeInvalidType, res.Errors[0].Code)
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbums_NextPage() {
+	cursor := &model.Cursor{Sort: "title", Value: "Album 1", ID: 1}
+	req := httptest.NewRequest(http.MethodGet, "/albums?limit=1&sort=title&singer_id=1&title_prefix=Al", nil)
+	rr := httptest.NewRecorder()
+
+	singerID := model.SingerID(1)
+	q := &model.AlbumQuery{Limit: 1, Sort: model.AlbumSortTitle, SingerID: &singerID, TitlePrefix: "Al"}
+	page := &model.Page[*model.Album]{
+		Items: []*model.Album{
+			{ID: model.AlbumID(1), Title: "Album 1", SingerID: singerID, Singer: &model.Singer{ID: singerID, Name: "Singer 1"}},
+		},
+		NextCursor: cursor,
+	}
+	suite.mockAlbumService.On("GetAlbumListService", req.Context(), q).Return(page, nil)
+	suite.albumController.GetAlbums(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var list dto.AlbumListResponse
+	err := json.NewDecoder(rr.Body).Decode(&list)
+	suite.NoError(err)
+	suite.Len(list.Items, 1)
+	suite.Equal(cursor.Encode(), list.NextCursor)
+	suite.Equal(
+		`</albums?cursor=`+cursor.Encode()+`&limit=1&singer_id=1&sort=title&title_prefix=Al>; rel="next"`,
+		rr.Header().Get("Link"),
+	)
+
+	suite.mockAlbumService.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumControllerSuite) TestGetAlbums_InvalidQuery() {
+	req := httptest.NewRequest(http.MethodGet, "/albums?limit=ten&order=sideways&cursor=%21%21", nil)
+	rr := httptest.NewRecorder()
+
+	suite.albumController.GetAlbums(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	fields := make([]string, 0, len(res.Errors))
+	for _, f := range res.Errors {
+		fields = append(fields, f.Field)
+	}
+	suite.ElementsMatch([]string{"limit", "order", "cursor"}, fields)
+}
+
+func (suite *AlbumControllerSuite) TestRestoreAlbum_SingerTrashed() {
+	req := httptest.NewRequest(http.MethodPost, "/albums/3:restore", nil)
+	req.SetPathValue("id", "3:restore")
+	rr := httptest.NewRecorder()
+
+	suite.mockAlbumService.On("RestoreAlbumService", req.Context(), model.AlbumID(3)).
+		Return(nil, repository.ErrorReferencedSingerNotFound)
+	suite.albumController.RestoreAlbum(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
+
+func (suite *AlbumControllerSuite) TestRestoreAlbum_InvalidID() {
+	req := httptest.NewRequest(http.MethodPost, "/albums/abc:restore", nil)
+	req.SetPathValue("id", "abc:restore")
+	rr := httptest.NewRecorder()
+
+	suite.albumController.RestoreAlbum(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (suite *AlbumControllerSuite) TestBatchAlbums_BestEffort() {
+	body := `{"mode":"best_effort","operations":[
+		{"op":"create","title":"New Album","singer_id":1},
+		{"op":"delete","id":3,"version":2}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/albums:batch", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	ops := []model.BatchOperation[*model.Album]{
+		{Action: model.BatchCreate, Item: &model.Album{Title: "New Album", SingerID: model.SingerID(1)}},
+		{Action: model.BatchDelete, Item: &model.Album{ID: model.AlbumID(3), Version: 2}},
+	}
+	results := []*model.BatchResult{
+		{Index: 0, Action: model.BatchCreate, ID: 10},
+		{Index: 1, Action: model.BatchDelete, ID: 3, Err: repository.ErrorAlbumNotFound},
+	}
+	suite.mockAlbumService.On("BatchAlbumService", req.Context(), model.BatchBestEffort, ops).Return(results, nil)
+	suite.albumController.BatchAlbums(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.BatchResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Require().Len(res.Results, 2)
+	suite.Equal(http.StatusCreated, res.Results[0].Status)
+	suite.Equal(10, res.Results[0].ID)
+	suite.Equal(http.StatusNotFound, res.Results[1].Status)
+	suite.Equal("album not found", res.Results[1].Detail)
+}
+
+func (suite *AlbumControllerSuite) TestBatchAlbums_AtomicFailure() {
+	body := `{"operations":[{"op":"create","title":"New Album","singer_id":1},{"op":"create","singer_id":1}]}`
+	req := httptest.NewRequest(http.MethodPost, "/albums:batch", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	invalid := &model.ValidationError{}
+	invalid.Add("title", model.CodeRequired, "title is required")
+	suite.mockAlbumService.On("BatchAlbumService", req.Context(), model.BatchAtomic, mock.Anything).
+		Return(nil, &model.BatchError{Index: 1, Err: invalid})
+	suite.albumController.BatchAlbums(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("operations[1]: request contains invalid fields", res.Detail)
+	suite.Require().Len(res.Errors, 1)
+	suite.Equal("operations[1].title", res.Errors[0].Field)
+}
+
+func (suite *AlbumControllerSuite) TestBatchAlbums_TooManyOperations() {
+	suite.albumController = controller.NewAlbumController(suite.mockAlbumService, controller.Options{MaxBatchSize: 1})
+	body := `{"operations":[{"op":"create","title":"A","singer_id":1},{"op":"create","title":"B","singer_id":1}]}`
+	req := httptest.NewRequest(http.MethodPost, "/albums:batch", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	suite.albumController.BatchAlbums(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Require().Len(res.Errors, 1)
+	suite.Equal("operations", res.Errors[0].Field)
+	suite.Equal(model.CodeOutOfRange, res.Errors[0].Code)
+	suite.mockAlbumService.AssertNotCalled(suite.T(), "BatchAlbumService", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AlbumControllerSuite) TestExportAlbums_JSON() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/albums?singer_id=1&sort=title", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+
+	singerID := model.SingerID(1)
+	q := &model.AlbumQuery{Sort: model.AlbumSortTitle, SingerID: &singerID}
+	singer := &model.Singer{ID: singerID, Name: "Alice"}
+	albums := []*model.Album{
+		{ID: model.AlbumID(2), Title: "Alpha", SingerID: singerID, Singer: singer},
+		{ID: model.AlbumID(1), Title: "Beta", SingerID: singerID, Singer: singer},
+	}
+	suite.mockAlbumService.On("ExportAlbumsService", req.Context(), q).Return(albums, nil)
+	suite.albumController.ExportAlbums(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("application/json", rr.Header().Get("Content-Type"))
+	suite.Equal(`attachment; filename="albums.json"`, rr.Header().Get("Content-Disposition"))
+
+	var rows []dto.AlbumExportRow
+	err := json.NewDecoder(rr.Body).Decode(&rows)
+	suite.NoError(err)
+	suite.Require().Len(rows, 2)
+	suite.Equal("Alpha", rows[0].Title)
+	suite.Equal("Alice", rows[1].SingerName)
+}
+
+func (suite *AlbumControllerSuite) TestExportAlbums_Empty() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/albums", nil)
+	rr := httptest.NewRecorder()
+
+	suite.mockAlbumService.On("ExportAlbumsService", req.Context(), &model.AlbumQuery{Sort: model.AlbumSortID}).Return(nil, nil)
+	suite.albumController.ExportAlbums(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("[]\n", rr.Body.String())
+}
+
+func (suite *AlbumControllerSuite) TestExportAlbums_UnavailableBeforeFirstRow() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/albums?format=ndjson", nil)
+	rr := httptest.NewRecorder()
+
+	unavailable := model.NewError(model.KindUnavailable, "database unavailable", nil)
+	suite.mockAlbumService.On("ExportAlbumsService", req.Context(), mock.Anything).Return(nil, unavailable)
+	suite.albumController.ExportAlbums(rr, req)
+
+	suite.Equal(http.StatusServiceUnavailable, rr.Code)
+	suite.Equal("application/problem+json", rr.Header().Get("Content-Type"))
+}
+
+func (suite *AlbumControllerSuite) TestExportAlbums_AbortsMidStream() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/albums?format=ndjson", nil)
+	rr := httptest.NewRecorder()
+
+	albums := []*model.Album{{ID: model.AlbumID(1), Title: "Alpha", Singer: &model.Singer{ID: 1, Name: "Alice"}}}
+	suite.mockAlbumService.On("ExportAlbumsService", req.Context(), mock.Anything).Return(albums, errors.New("connection reset"))
+
+	suite.PanicsWithValue(http.ErrAbortHandler, func() {
+		suite.albumController.ExportAlbums(rr, req)
+	})
+	suite.Equal(http.StatusOK, rr.Code)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+)
+
+type AuditController interface {
+	GetAuditEventsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type auditController struct {
+	service service.AuditService
+}
+
+var _ AuditController = (*auditController)(nil)
+
+func NewAuditController(s service.AuditService) AuditController {
+	return &auditController{service: s}
+}
+
+// GetAuditEventsHandler GET /audit
+func (c *auditController) GetAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := dto.NewAuditQuery(r.URL.Query())
+	if err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	page, err := c.service.GetAuditEventsService(r.Context(), query)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setNextLink(w, r, page.NextCursor)
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewAuditEventListResponse(page)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
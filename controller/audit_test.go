@@ -0,0 +1,88 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) GetAuditEventsService(ctx context.Context, q *model.AuditQuery) (*model.Page[*model.AuditEvent], error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Page[*model.AuditEvent]), args.Error(1)
+}
+
+type AuditControllerSuite struct {
+	suite.Suite
+	auditController  controller.AuditController
+	mockAuditService *MockAuditService
+}
+
+func TestAuditControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditControllerSuite))
+}
+
+func (suite *AuditControllerSuite) SetupTest() {
+	suite.mockAuditService = &MockAuditService{}
+	suite.auditController = controller.NewAuditController(suite.mockAuditService)
+}
+
+func (suite *AuditControllerSuite) TestGetAuditEventsHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/audit?entity=album&id=3&limit=1", nil)
+	rr := httptest.NewRecorder()
+
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	q := &model.AuditQuery{Limit: 1, EntityType: model.AuditEntityAlbum, EntityID: 3}
+	page := &model.Page[*model.AuditEvent]{
+		Items: []*model.AuditEvent{{
+			ID:         9,
+			Actor:      "api_key:local editor",
+			Action:     model.AuditDelete,
+			EntityType: model.AuditEntityAlbum,
+			EntityID:   3,
+			RequestID:  "req-9",
+			Before:     json.RawMessage(`{"id":3,"title":"Bella's 1st Album"}`),
+			CreatedAt:  createdAt,
+		}},
+		NextCursor: &model.Cursor{Sort: "id", Desc: true, ID: 9},
+	}
+	suite.mockAuditService.On("GetAuditEventsService", req.Context(), q).Return(page, nil)
+	suite.auditController.GetAuditEventsHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Contains(rr.Header().Get("Link"), `rel="next"`)
+
+	var res dto.AuditEventListResponse
+	suite.Require().NoError(json.NewDecoder(rr.Body).Decode(&res))
+	suite.Require().Len(res.Items, 1)
+	suite.Equal("api_key:local editor", res.Items[0].Actor)
+	suite.Equal("delete", res.Items[0].Action)
+	suite.JSONEq(`{"id":3,"title":"Bella's 1st Album"}`, string(res.Items[0].Before))
+	suite.JSONEq(`null`, string(res.Items[0].After))
+	suite.Equal(page.NextCursor.Encode(), res.NextCursor)
+}
+
+func (suite *AuditControllerSuite) TestGetAuditEventsHandler_InvalidID() {
+	req := httptest.NewRequest(http.MethodGet, "/audit?entity=album&id=three", nil)
+	rr := httptest.NewRecorder()
+
+	suite.auditController.GetAuditEventsHandler(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+	suite.mockAuditService.AssertNotCalled(suite.T(), "GetAuditEventsService", mock.Anything, mock.Anything)
+}
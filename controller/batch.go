@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type batchOperationRequest[M any] interface {
+	Header() dto.BatchOperationHeader
+	ToModel() model.BatchOperation[M]
+}
+
+// batchOperations checks the shape of a batch request. The items themselves are validated
+// by the service, so that best-effort batches can report them one by one.
+func batchOperations[M any, R batchOperationRequest[M]](o Options, req *dto.BatchRequest[R]) (model.BatchMode, []model.BatchOperation[M], error) {
+	v := &model.ValidationError{}
+	mode := model.BatchMode(req.Mode)
+	if mode == "" {
+		mode = model.BatchAtomic
+	}
+	if !mode.Valid() {
+		v.Add("mode", model.CodeInvalid, "mode must be atomic or best_effort")
+	}
+	if len(req.Operations) == 0 {
+		v.Add("operations", model.CodeRequired, "at least one operation is required")
+	}
+	if limit := o.maxBatchSize(); len(req.Operations) > limit {
+		v.Add("operations", model.CodeOutOfRange, fmt.Sprintf("at most %d operations are allowed", limit))
+		return "", nil, v
+	}
+
+	ops := make([]model.BatchOperation[M], 0, len(req.Operations))
+	for i, op := range req.Operations {
+		h := op.Header()
+		field := func(name string) string { return fmt.Sprintf("operations[%d].%s", i, name) }
+		switch model.BatchAction(h.Op) {
+		case model.BatchCreate:
+			if h.ID != nil && !o.ImportMode {
+				v.Add(field("id"), model.CodeReadOnly, "id is assigned by the server")
+			}
+		case model.BatchUpdate, model.BatchDelete:
+			if h.ID == nil {
+				v.Add(field("id"), model.CodeRequired, "id is required")
+			}
+			if h.Version < 0 {
+				v.Add(field("version"), model.CodeOutOfRange, "version must be positive")
+			} else if h.Version == 0 && o.RequireIfMatch {
+				v.Add(field("version"), model.CodeRequired, "version is required")
+			}
+		default:
+			v.Add(field("op"), model.CodeInvalid, "op must be one of create, update, delete")
+		}
+		ops = append(ops, op.ToModel())
+	}
+	if err := v.Err(); err != nil {
+		return "", nil, err
+	}
+	return mode, ops, nil
+}
+
+var batchSuccessStatus = map[model.BatchAction]int{
+	model.BatchCreate: http.StatusCreated,
+	model.BatchUpdate: http.StatusOK,
+	model.BatchDelete: http.StatusNoContent,
+}
+
+// writeBatchResults answers 200 with one entry per operation, each carrying the status
+// the operation would have had as a request of its own.
+func writeBatchResults(w http.ResponseWriter, r *http.Request, results []*model.BatchResult) {
+	res := &dto.BatchResponse{Results: make([]*dto.BatchResultResponse, len(results))}
+	for i, result := range results {
+		if result.Err == nil {
+			res.Results[i] = dto.NewBatchResultResponse(result, batchSuccessStatus[result.Action], "")
+			continue
+		}
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "batch operation failed", "index", result.Index, "error", result.Err)
+		kind := model.KindOf(result.Err)
+		res.Results[i] = dto.NewBatchResultResponse(result, statusFromKind(kind), publicMessage(result.Err, kind))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// batchErrorHandler reports an aborted atomic batch with the status of the failing
+// operation and points the detail and field errors at it.
+func batchErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var batchErr *model.BatchError
+	if !errors.As(err, &batchErr) {
+		errorHandler(w, r, err)
+		return
+	}
+
+	kind := model.KindOf(batchErr.Err)
+	detail := fmt.Sprintf("operations[%d]: %s", batchErr.Index, publicMessage(batchErr.Err, kind))
+	var validationErr *model.ValidationError
+	if errors.As(batchErr.Err, &validationErr) {
+		prefixed := &model.ValidationError{}
+		for _, f := range validationErr.Fields {
+			prefixed.Add(fmt.Sprintf("operations[%d].%s", batchErr.Index, f.Field), f.Code, f.Message)
+		}
+		err = prefixed
+	}
+	writeProblem(w, r, statusFromKind(kind), detail, err)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"net/http"
+	"strconv"
+)
+
+type CreditController interface {
+	GetAlbumCredits(w http.ResponseWriter, r *http.Request)
+	PutAlbumCredits(w http.ResponseWriter, r *http.Request)
+}
+type creditController struct {
+	service      service.CreditService
+	albumService service.AlbumService
+	options      Options
+}
+
+var _ CreditController = (*creditController)(nil)
+
+func NewCreditController(s service.CreditService, albumService service.AlbumService, options Options) CreditController {
+	return &creditController{service: s, albumService: albumService, options: options}
+}
+
+// GetAlbumCredits GET /albums/{id}/credits
+func (c creditController) GetAlbumCredits(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	credits, err := c.service.GetAlbumCreditsService(r.Context(), model.AlbumID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewCreditListResponse(credits)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// PutAlbumCredits PUT /albums/{id}/credits
+func (c creditController) PutAlbumCredits(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	// The primary credit rewrites the album, so the write is conditional on the album's ETag.
+	version, ok := c.options.ifMatch(w, r, currentAlbum(r.Context(), c.albumService.GetAlbumService, model.AlbumID(ID)))
+	if !ok {
+		return
+	}
+	req := dto.PutCreditsRequest{}
+	if err = decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	credits, err := c.service.PutAlbumCreditsService(r.Context(), model.AlbumID(ID), version, req.ToModel())
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewCreditListResponse(credits)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
@@ -0,0 +1,153 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockCreditService struct {
+	mock.Mock
+}
+
+func NewMockCreditService() *MockCreditService {
+	return &MockCreditService{}
+}
+
+func (m *MockCreditService) GetAlbumCreditsService(ctx context.Context, albumID model.AlbumID) ([]*model.Credit, error) {
+	args := m.Called(ctx, albumID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Credit), args.Error(1)
+}
+
+func (m *MockCreditService) PutAlbumCreditsService(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) ([]*model.Credit, error) {
+	args := m.Called(ctx, albumID, version, credits)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Credit), args.Error(1)
+}
+
+type CreditControllerSuite struct {
+	suite.Suite
+	creditController  controller.CreditController
+	mockCreditService *MockCreditService
+	mockAlbumService  *MockAlbumService
+}
+
+func TestCreditControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(CreditControllerSuite))
+}
+
+func (suite *CreditControllerSuite) SetupTest() {
+	suite.mockCreditService = NewMockCreditService()
+	suite.mockAlbumService = NewMockAlbumService()
+	suite.creditController = controller.NewCreditController(suite.mockCreditService, suite.mockAlbumService, controller.Options{})
+}
+
+func (suite *CreditControllerSuite) TestGetAlbumCredits_Success() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/2/credits", nil)
+	req.SetPathValue("id", "2")
+	rr := httptest.NewRecorder()
+
+	credits := []*model.Credit{
+		{AlbumID: 2, SingerID: 1, Singer: &model.Singer{ID: 1, Name: "Alice"}, Role: model.CreditRolePrimary, Position: 1},
+		{AlbumID: 2, SingerID: 2, Singer: &model.Singer{ID: 2, Name: "Bella"}, Role: model.CreditRoleFeatured, Position: 2},
+	}
+	suite.mockCreditService.On("GetAlbumCreditsService", req.Context(), model.AlbumID(2)).Return(credits, nil)
+	suite.creditController.GetAlbumCredits(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.CreditListResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Len(res.Items, 2)
+	suite.Equal("primary", res.Items[0].Role)
+	suite.Equal("Bella", res.Items[1].Singer.Name)
+	suite.Equal(2, res.Items[1].Position)
+}
+
+func (suite *CreditControllerSuite) TestPutAlbumCredits_Success() {
+	body := `{"credits":[{"singer_id":1,"role":"primary"},{"singer_id":3,"role":"producer"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/2/credits", strings.NewReader(body))
+	req.SetPathValue("id", "2")
+	rr := httptest.NewRecorder()
+
+	credits := []*model.Credit{
+		{SingerID: 1, Role: model.CreditRolePrimary},
+		{SingerID: 3, Role: model.CreditRoleProducer},
+	}
+	suite.mockCreditService.On("PutAlbumCreditsService", req.Context(), model.AlbumID(2), int64(0), credits).Return(credits, nil)
+	suite.creditController.PutAlbumCredits(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.mockCreditService.AssertExpectations(suite.T())
+}
+
+func (suite *CreditControllerSuite) TestPutAlbumCredits_UnknownSinger() {
+	body := `{"credits":[{"singer_id":1,"role":"primary"},{"singer_id":99,"role":"featured"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/2/credits", strings.NewReader(body))
+	req.SetPathValue("id", "2")
+	rr := httptest.NewRecorder()
+
+	suite.mockCreditService.On("PutAlbumCreditsService", req.Context(), model.AlbumID(2), int64(0), mock.Anything).
+		Return(nil, repository.ErrorReferencedSingerNotFound)
+	suite.creditController.PutAlbumCredits(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
+
+func (suite *CreditControllerSuite) TestPutAlbumCredits_TrashedAlbum() {
+	body := `{"credits":[{"singer_id":1,"role":"primary"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/3/credits", strings.NewReader(body))
+	req.SetPathValue("id", "3")
+	rr := httptest.NewRecorder()
+
+	suite.mockCreditService.On("PutAlbumCreditsService", req.Context(), model.AlbumID(3), int64(0), mock.Anything).
+		Return(nil, repository.ErrorAlbumNotFound)
+	suite.creditController.PutAlbumCredits(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *CreditControllerSuite) TestPutAlbumCredits_IfMatch() {
+	body := `{"credits":[{"singer_id":1,"role":"primary"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/2/credits", strings.NewReader(body))
+	req.SetPathValue("id", "2")
+	req.Header.Set("If-Match", `"4-2"`)
+	rr := httptest.NewRecorder()
+
+	album := &model.Album{ID: model.AlbumID(2), Singer: &model.Singer{ID: model.SingerID(1), Version: 2}, Version: 4}
+	suite.mockAlbumService.On("GetAlbumService", req.Context(), model.AlbumID(2)).Return(album, nil)
+	suite.mockCreditService.On("PutAlbumCreditsService", req.Context(), model.AlbumID(2), int64(4), mock.Anything).
+		Return(nil, repository.ErrorAlbumVersionMismatch)
+	suite.creditController.PutAlbumCredits(rr, req)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+	suite.mockCreditService.AssertExpectations(suite.T())
+}
+
+func (suite *CreditControllerSuite) TestPutAlbumCredits_IfMatchRequired() {
+	creditController := controller.NewCreditController(suite.mockCreditService, suite.mockAlbumService, controller.Options{RequireIfMatch: true})
+	body := `{"credits":[{"singer_id":1,"role":"primary"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/albums/2/credits", strings.NewReader(body))
+	req.SetPathValue("id", "2")
+	rr := httptest.NewRecorder()
+
+	creditController.PutAlbumCredits(rr, req)
+
+	suite.Equal(http.StatusPreconditionRequired, rr.Code)
+	suite.mockCreditService.AssertNotCalled(suite.T(), "PutAlbumCreditsService", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// decodeBody strictly decodes a JSON request body into v.
+func decodeBody(body io.Reader, v any) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// decodeJSON is decodeBody for documents already read into memory, such as merged patches.
+func decodeJSON(data []byte, v any) error {
+	return decodeBody(bytes.NewReader(data), v)
+}
+
+// decodeError turns json errors that point at a specific field into a ValidationError.
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		v := &model.ValidationError{}
+		v.Add(typeErr.Field, model.CodeInvalidType, fmt.Sprintf("%s must be %s", typeErr.Field, typeErr.Type))
+		return fmt.Errorf("invalid body param: %w", v)
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if unquoted, unquoteErr := strconv.Unquote(field); unquoteErr == nil {
+			field = unquoted
+		}
+		v := &model.ValidationError{}
+		v.Add(field, model.CodeUnknownField, "unknown field "+strconv.Quote(field))
+		return fmt.Errorf("invalid body param: %w", v)
+	}
+	return fmt.Errorf("invalid body param: %w", err)
+}
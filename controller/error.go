@@ -2,21 +2,161 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
 )
 
-type ErrorMessage struct {
-	Message string `json:"message"`
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+}
+
+type ProblemField struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+var problemTypes = map[int]string{
+	http.StatusBadRequest:            "/problems/bad-request",
+	http.StatusUnauthorized:          "/problems/unauthorized",
+	http.StatusForbidden:             "/problems/forbidden",
+	http.StatusNotFound:              "/problems/not-found",
+	http.StatusConflict:              "/problems/conflict",
+	http.StatusRequestEntityTooLarge: "/problems/payload-too-large",
+	http.StatusUnsupportedMediaType:  "/problems/unsupported-media-type",
+	http.StatusUnprocessableEntity:   "/problems/validation-error",
+	http.StatusServiceUnavailable:    "/problems/unavailable",
+	http.StatusPreconditionFailed:    "/problems/precondition-failed",
+	http.StatusPreconditionRequired:  "/problems/precondition-required",
+}
+
+// statusFromKind is the single place where domain error kinds become HTTP status codes.
+func statusFromKind(kind model.ErrorKind) int {
+	switch kind {
+	case model.KindNotFound:
+		return http.StatusNotFound
+	case model.KindConflict:
+		return http.StatusConflict
+	case model.KindValidation, model.KindForeignKeyViolation:
+		return http.StatusUnprocessableEntity
+	case model.KindUnavailable:
+		return http.StatusServiceUnavailable
+	case model.KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case model.KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	case model.KindUnauthenticated:
+		return http.StatusUnauthorized
+	case model.KindForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// publicMessage returns the text that may be shown to clients for err.
+// Internal and unavailable errors never expose their cause.
+func publicMessage(err error, kind model.ErrorKind) string {
+	switch kind {
+	case model.KindInternal, model.KindUnavailable:
+		return http.StatusText(statusFromKind(kind))
+	}
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		return "request contains invalid fields"
+	}
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return err.Error()
+}
+
+// errorHandler writes err as a problem response whose status is derived from its kind.
+func errorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	kind := model.KindOf(err)
+	writeProblem(w, r, statusFromKind(kind), publicMessage(err, kind), err)
 }
 
-func errorHandler(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
-	slog.ErrorContext(r.Context(), "error occurred", "message", message)
+// badRequestHandler writes a 400 problem response for malformed path or body parameters, or
+// 413 when the body was cut off at its size limit.
+func badRequestHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		writeProblem(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit), err)
+		return
+	}
+	message := err.Error()
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		message = "request could not be decoded"
+	}
+	writeProblem(w, r, http.StatusBadRequest, message, err)
+}
+
+// WriteError lets middleware report err exactly as the handlers would.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	errorHandler(w, r, err)
+}
+
+// WriteProblem lets middleware answer with a problem response of its own.
+func WriteProblem(w http.ResponseWriter, r *http.Request, statusCode int, detail string) {
+	writeProblem(w, r, statusCode, detail, errors.New(detail))
+}
+
+// writeProblem logs client errors at INFO, as they are expected in normal operation; only
+// server errors are logged at ERROR.
+func writeProblem(w http.ResponseWriter, r *http.Request, statusCode int, detail string, err error) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logging.FromContext(r.Context()).Log(r.Context(), level, "error occurred", "status", statusCode, "error", err)
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(statusCode),
+		Status:    statusCode,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestID(w, r),
+	}
+	if problemType, ok := problemTypes[statusCode]; ok {
+		problem.Type = problemType
+	}
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, f := range validationErr.Fields {
+			problem.Errors = append(problem.Errors, ProblemField{Field: f.Field, Code: f.Code, Message: f.Message})
+		}
+	}
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", problemContentType)
 	w.WriteHeader(statusCode)
 
-	if err := json.NewEncoder(w).Encode(ErrorMessage{Message: message}); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
+
+// requestID prefers the id already assigned to the response and falls back to the client's.
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	if id := w.Header().Get("X-Request-ID"); id != "" {
+		return id
 	}
+	return r.Header.Get("X-Request-ID")
 }
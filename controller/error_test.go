@@ -0,0 +1,43 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteProblem_LogLevel(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{status: http.StatusNotFound, level: "INFO"},
+		{status: http.StatusPreconditionFailed, level: "INFO"},
+		{status: http.StatusInternalServerError, level: "ERROR"},
+		{status: http.StatusServiceUnavailable, level: "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			req := httptest.NewRequest(http.MethodGet, "/singers/1", nil)
+			req = req.WithContext(logging.NewContext(req.Context(), logger))
+			rr := httptest.NewRecorder()
+
+			controller.WriteProblem(rr, req, tt.status, "detail")
+
+			assert.Equal(t, tt.status, rr.Code)
+			var entry map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, tt.level, entry["level"])
+			assert.EqualValues(t, tt.status, entry["status"])
+		})
+	}
+}
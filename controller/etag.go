@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+var (
+	errPreconditionRequired = model.NewError(model.KindPreconditionRequired, "If-Match header is required", nil)
+	errPreconditionFailed   = model.NewError(model.KindPreconditionFailed, "If-Match does not match the current version", nil)
+)
+
+// etag renders a resource version as a strong entity tag. A representation that also embeds
+// other rows passes their versions after its own, e.g. an album with its singer's name.
+func etag(version int64, embedded ...int64) string {
+	parts := []string{strconv.FormatInt(version, 10)}
+	for _, v := range embedded {
+		parts = append(parts, strconv.FormatInt(v, 10))
+	}
+	return `"` + strings.Join(parts, "-") + `"`
+}
+
+// albumETag covers the album row and the singer name it embeds.
+func albumETag(album *model.Album) string {
+	return etag(album.Version, album.Singer.Version)
+}
+
+func singerETag(singer *model.Singer) string {
+	return etag(singer.Version)
+}
+
+// currentAlbum loads the album a conditional write is checked against, for ifMatch.
+func currentAlbum(ctx context.Context, get func(context.Context, model.AlbumID) (*model.Album, error), id model.AlbumID) func() (string, int64, error) {
+	return func() (string, int64, error) {
+		album, err := get(ctx, id)
+		if err != nil {
+			return "", 0, err
+		}
+		return albumETag(album), album.Version, nil
+	}
+}
+
+// currentSinger is currentAlbum for singers.
+func currentSinger(ctx context.Context, get func(context.Context, model.SingerID) (*model.Singer, error), id model.SingerID) func() (string, int64, error) {
+	return func() (string, int64, error) {
+		singer, err := get(ctx, id)
+		if err != nil {
+			return "", 0, err
+		}
+		return singerETag(singer), singer.Version, nil
+	}
+}
+
+// notModified reports whether If-None-Match already names tag. It uses the weak comparison
+// RFC 9110 prescribes for If-None-Match.
+func notModified(r *http.Request, tag string) bool {
+	for _, t := range entityTags(r.Header.Values("If-None-Match")) {
+		if t == "*" || strings.TrimPrefix(t, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch returns the version a mutating request is conditional on, zero when the client did
+// not make it conditional. Otherwise one of the listed tags must equal the tag of the current
+// representation, which current loads along with its version, and the write is made
+// conditional on that version. When the request cannot go ahead, ifMatch writes the error
+// response and reports false.
+func (o Options) ifMatch(w http.ResponseWriter, r *http.Request, current func() (tag string, version int64, err error)) (int64, bool) {
+	tags := entityTags(r.Header.Values("If-Match"))
+	if len(tags) == 0 {
+		if o.RequireIfMatch {
+			errorHandler(w, r, errPreconditionRequired)
+			return 0, false
+		}
+		return 0, true
+	}
+	if slices.Contains(tags, "*") {
+		return 0, true
+	}
+
+	tag, version, err := current()
+	if err != nil {
+		errorHandler(w, r, err)
+		return 0, false
+	}
+	// A strong comparison, which weak tags never pass.
+	if !slices.Contains(tags, tag) {
+		errorHandler(w, r, errPreconditionFailed)
+		return 0, false
+	}
+	return version, true
+}
+
+func entityTags(values []string) []string {
+	var tags []string
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
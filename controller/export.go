@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type exportFormat string
+
+const (
+	exportCSV    exportFormat = "csv"
+	exportNDJSON exportFormat = "ndjson"
+	exportJSON   exportFormat = "json"
+)
+
+var exportContentTypes = map[exportFormat]string{
+	exportCSV:    "text/csv; charset=utf-8",
+	exportNDJSON: "application/x-ndjson",
+	exportJSON:   "application/json",
+}
+
+var exportMediaTypes = map[string]exportFormat{
+	"text/csv":             exportCSV,
+	"application/x-ndjson": exportNDJSON,
+	"application/ndjson":   exportNDJSON,
+	"application/json":     exportJSON,
+	"application/*":        exportJSON,
+	"*/*":                  exportJSON,
+}
+
+// exportFlushEvery is how many rows are buffered before they are pushed to the client.
+const exportFlushEvery = 100
+
+// negotiateExport picks the export format from the format parameter, or else from the first
+// Accept entry it can serve. On failure the problem response has already been written.
+func negotiateExport(w http.ResponseWriter, r *http.Request) (exportFormat, bool) {
+	if raw := r.URL.Query().Get("format"); raw != "" {
+		format := exportFormat(raw)
+		if _, ok := exportContentTypes[format]; !ok {
+			v := &model.ValidationError{}
+			v.Add("format", model.CodeInvalid, "format must be one of csv, ndjson, json")
+			badRequestHandler(w, r, v)
+			return "", false
+		}
+		return format, true
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return exportJSON, true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if format, ok := exportMediaTypes[mediaType]; ok {
+			return format, true
+		}
+	}
+	writeProblem(w, r, http.StatusNotAcceptable, "exports are available as text/csv, application/x-ndjson or application/json", nil)
+	return "", false
+}
+
+type exportRow interface {
+	CSVRecord() []string
+}
+
+// exportWriter streams rows to the response as they are read. Nothing is written before the
+// first row, so an export that fails early still gets a regular problem response.
+type exportWriter struct {
+	w        http.ResponseWriter
+	format   exportFormat
+	filename string
+	header   []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	started  bool
+	rows     int
+}
+
+// newExportWriter gives the writes the request's deadline, so a client that stops reading
+// fails the export once it passes instead of blocking it. Call close when the export is done.
+func newExportWriter(w http.ResponseWriter, r *http.Request, format exportFormat, name string, header []string) *exportWriter {
+	e := &exportWriter{
+		w:        w,
+		format:   format,
+		filename: name + "." + string(format),
+		header:   header,
+		csv:      csv.NewWriter(w),
+		json:     json.NewEncoder(w),
+	}
+	if deadline, ok := r.Context().Deadline(); ok {
+		e.setWriteDeadline(r, deadline)
+	}
+	return e
+}
+
+// close lifts the write deadline again. It is set on the connection, and unless the server has
+// a WriteTimeout of its own it would still apply to the next request on the connection.
+func (e *exportWriter) close(r *http.Request) {
+	if _, ok := r.Context().Deadline(); ok {
+		e.setWriteDeadline(r, time.Time{})
+	}
+}
+
+func (e *exportWriter) setWriteDeadline(r *http.Request, deadline time.Time) {
+	err := http.NewResponseController(e.w).SetWriteDeadline(deadline)
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logging.FromContext(r.Context()).WarnContext(r.Context(), "failed to set export write deadline", "error", err)
+	}
+}
+
+func (e *exportWriter) start() error {
+	e.started = true
+	e.w.Header().Set("Content-Type", exportContentTypes[e.format])
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.w.WriteHeader(http.StatusOK)
+
+	switch e.format {
+	case exportCSV:
+		return e.csv.Write(e.header)
+	case exportJSON:
+		_, err := io.WriteString(e.w, "[")
+		return err
+	}
+	return nil
+}
+
+func (e *exportWriter) write(row exportRow) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch e.format {
+	case exportCSV:
+		err = e.csv.Write(row.CSVRecord())
+	case exportJSON:
+		if e.rows > 0 {
+			if _, err = io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		err = e.json.Encode(row)
+	default:
+		err = e.json.Encode(row)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *exportWriter) flush() error {
+	if e.format == exportCSV {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := http.NewResponseController(e.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// finish completes the document. An export without rows still gets its header or brackets.
+func (e *exportWriter) finish(r *http.Request) {
+	err := func() error {
+		if !e.started {
+			if err := e.start(); err != nil {
+				return err
+			}
+		}
+		if e.format == exportJSON {
+			if _, err := io.WriteString(e.w, "]\n"); err != nil {
+				return err
+			}
+		}
+		return e.flush()
+	}()
+	if err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to finish export", "rows", e.rows, "error", err)
+	}
+}
+
+// fail reports err. Once rows have been sent the status cannot change, so the connection is
+// aborted instead, which keeps a truncated export from looking complete.
+func (e *exportWriter) fail(r *http.Request, err error) {
+	if !e.started {
+		errorHandler(e.w, r, err)
+		return
+	}
+	logging.FromContext(r.Context()).ErrorContext(r.Context(), "export aborted", "rows", e.rows, "error", err)
+	panic(http.ErrAbortHandler)
+}
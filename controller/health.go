@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+)
+
+type HealthController interface {
+	HealthzHandler(w http.ResponseWriter, r *http.Request)
+	ReadyzHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type healthController struct {
+	service service.HealthService
+}
+
+var _ HealthController = (*healthController)(nil)
+
+func NewHealthController(s service.HealthService) HealthController {
+	return &healthController{service: s}
+}
+
+// HealthzHandler GET /healthz
+func (c *healthController) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, r, c.service.LivenessService(r.Context()))
+}
+
+// ReadyzHandler GET /readyz
+func (c *healthController) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	report := c.service.ReadinessService(r.Context())
+	for _, check := range report.Checks {
+		if check.Status != model.HealthOK {
+			logging.FromContext(r.Context()).WarnContext(r.Context(), "readiness check failed", "check", check.Name, "error", check.Error)
+		}
+	}
+	writeHealthReport(w, r, report)
+}
+
+func writeHealthReport(w http.ResponseWriter, r *http.Request, report *model.HealthReport) {
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(dto.NewHealthResponse(report)); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
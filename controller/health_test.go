@@ -0,0 +1,93 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) LivenessService(ctx context.Context) *model.HealthReport {
+	args := m.Called(ctx)
+	return args.Get(0).(*model.HealthReport)
+}
+
+func (m *MockHealthService) ReadinessService(ctx context.Context) *model.HealthReport {
+	args := m.Called(ctx)
+	return args.Get(0).(*model.HealthReport)
+}
+
+func (m *MockHealthService) StartDraining() {
+	m.Called()
+}
+
+type HealthControllerSuite struct {
+	suite.Suite
+	healthController  controller.HealthController
+	mockHealthService *MockHealthService
+}
+
+func TestHealthControllerSuite(t *testing.T) {
+	suite.Run(t, new(HealthControllerSuite))
+}
+
+func (suite *HealthControllerSuite) SetupTest() {
+	suite.mockHealthService = new(MockHealthService)
+	suite.healthController = controller.NewHealthController(suite.mockHealthService)
+}
+
+func (suite *HealthControllerSuite) TestHealthzHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+
+	suite.mockHealthService.On("LivenessService", req.Context()).Return(model.NewHealthReport())
+	suite.healthController.HealthzHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("no-store", rr.Header().Get("Cache-Control"))
+	suite.JSONEq(`{"status": "ok", "checks": []}`, rr.Body.String())
+}
+
+func (suite *HealthControllerSuite) TestReadyzHandler_Ready() {
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rr := httptest.NewRecorder()
+
+	report := model.NewHealthReport(&model.HealthCheck{Name: "database", Status: model.HealthOK, Latency: 1500 * time.Microsecond})
+	suite.mockHealthService.On("ReadinessService", req.Context()).Return(report)
+	suite.healthController.ReadyzHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.JSONEq(`{"status": "ok", "checks": [{"name": "database", "status": "ok", "latency_ms": 1.5}]}`, rr.Body.String())
+}
+
+func (suite *HealthControllerSuite) TestReadyzHandler_NotReady() {
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rr := httptest.NewRecorder()
+
+	report := model.NewHealthReport(
+		&model.HealthCheck{Name: "draining", Status: model.HealthFail, Error: "server is shutting down"},
+		&model.HealthCheck{Name: "database", Status: model.HealthOK},
+	)
+	suite.mockHealthService.On("ReadinessService", req.Context()).Return(report)
+	suite.healthController.ReadyzHandler(rr, req)
+
+	suite.Equal(http.StatusServiceUnavailable, rr.Code)
+
+	var res dto.HealthResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("fail", res.Status)
+	suite.Equal("server is shutting down", res.Checks[0].Error)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+)
+
+// MaxImportSize bounds an import file, which is read whole before the job starts.
+const MaxImportSize = 32 << 20
+
+var importDecoders = map[string]func(io.Reader) (*model.ImportFile, error){
+	"text/csv":             dto.DecodeImportCSV,
+	"application/x-ndjson": dto.DecodeImportNDJSON,
+	"application/ndjson":   dto.DecodeImportNDJSON,
+}
+
+type ImportController interface {
+	PostImportHandler(w http.ResponseWriter, r *http.Request)
+	GetImportHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type importController struct {
+	service service.ImportService
+}
+
+var _ ImportController = (*importController)(nil)
+
+func NewImportController(s service.ImportService) ImportController {
+	return &importController{service: s}
+}
+
+// PostImportHandler POST /imports
+func (c *importController) PostImportHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := dto.NewImportRequest(r.URL.Query())
+	if err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	decode, ok := importDecoders[mediaType]
+	if !ok {
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "imports must be text/csv or application/x-ndjson", nil)
+		return
+	}
+	file, err := decode(http.MaxBytesReader(w, r.Body, MaxImportSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeProblem(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("import file exceeds %d bytes", maxBytesErr.Limit), err)
+			return
+		}
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	job, err := c.service.StartImportService(r.Context(), file, req.DryRun)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/imports/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	res := dto.NewImportJobResponse(job)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// GetImportHandler GET /imports/{id}
+func (c *importController) GetImportHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := c.service.GetImportService(r.Context(), r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewImportJobResponse(job)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
@@ -0,0 +1,147 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) StartImportService(ctx context.Context, file *model.ImportFile, dryRun bool) (*model.ImportJob, error) {
+	args := m.Called(ctx, file, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportJob), args.Error(1)
+}
+
+func (m *MockImportService) GetImportService(ctx context.Context, id string) (*model.ImportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportJob), args.Error(1)
+}
+
+func (m *MockImportService) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type ImportControllerSuite struct {
+	suite.Suite
+	importController  controller.ImportController
+	mockImportService *MockImportService
+}
+
+func TestImportControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportControllerSuite))
+}
+
+func (suite *ImportControllerSuite) SetupTest() {
+	suite.mockImportService = &MockImportService{}
+	suite.importController = controller.NewImportController(suite.mockImportService)
+}
+
+func (suite *ImportControllerSuite) TestPostImportHandler_CSV() {
+	body := "type,name\nsinger,Alice\n"
+	req := httptest.NewRequest(http.MethodPost, "/imports?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	rr := httptest.NewRecorder()
+
+	file := &model.ImportFile{Rows: []*model.ImportRow{{Line: 2, Type: model.ImportSinger, Name: "Alice"}}}
+	job := &model.ImportJob{ID: "abc123", DryRun: true, Status: model.ImportRunning, TotalRows: 1, CreatedAt: time.Now()}
+	suite.mockImportService.On("StartImportService", req.Context(), file, true).Return(job, nil)
+	suite.importController.PostImportHandler(rr, req)
+
+	suite.Equal(http.StatusAccepted, rr.Code)
+	suite.Equal("/imports/abc123", rr.Header().Get("Location"))
+
+	var res dto.ImportJobResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("abc123", res.ID)
+	suite.Equal("running", res.Status)
+	suite.True(res.DryRun)
+}
+
+func (suite *ImportControllerSuite) TestPostImportHandler_UnsupportedMediaType() {
+	req := httptest.NewRequest(http.MethodPost, "/imports", strings.NewReader(`[{"type":"singer"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	suite.importController.PostImportHandler(rr, req)
+
+	suite.Equal(http.StatusUnsupportedMediaType, rr.Code)
+	suite.mockImportService.AssertNotCalled(suite.T(), "StartImportService", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ImportControllerSuite) TestPostImportHandler_InvalidHeader() {
+	req := httptest.NewRequest(http.MethodPost, "/imports", strings.NewReader("name\nAlice\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+
+	suite.importController.PostImportHandler(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Require().Len(res.Errors, 1)
+	suite.Equal("type", res.Errors[0].Field)
+}
+
+func (suite *ImportControllerSuite) TestGetImportHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/imports/abc123", nil)
+	req.SetPathValue("id", "abc123")
+	rr := httptest.NewRecorder()
+
+	finishedAt := time.Now()
+	job := &model.ImportJob{
+		ID:             "abc123",
+		Status:         model.ImportCompleted,
+		TotalRows:      2,
+		ProcessedRows:  2,
+		SingersCreated: 1,
+		Errors:         []model.ImportRowError{{Line: 3, FieldError: model.FieldError{Field: "title", Code: model.CodeRequired}}},
+		FinishedAt:     &finishedAt,
+	}
+	suite.mockImportService.On("GetImportService", req.Context(), "abc123").Return(job, nil)
+	suite.importController.GetImportHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.ImportJobResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("completed", res.Status)
+	suite.Equal(1, res.SingersCreated)
+	suite.Require().Len(res.Errors, 1)
+	suite.Equal(3, res.Errors[0].Line)
+	suite.NotNil(res.FinishedAt)
+}
+
+func (suite *ImportControllerSuite) TestGetImportHandler_NotFound() {
+	req := httptest.NewRequest(http.MethodGet, "/imports/missing", nil)
+	req.SetPathValue("id", "missing")
+	rr := httptest.NewRecorder()
+
+	suite.mockImportService.On("GetImportService", req.Context(), "missing").Return(nil, repository.ErrorImportJobNotFound)
+	suite.importController.GetImportHandler(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
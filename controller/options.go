@@ -0,0 +1,34 @@
+package controller
+
+import "github.com/pulse227/server-recruit-challenge-sample/model"
+
+// DefaultMaxBatchSize caps the operations of one batch request when Options leave it unset.
+const DefaultMaxBatchSize = 1000
+
+// Options configures behaviour shared by the controllers.
+type Options struct {
+	// ImportMode accepts client-supplied ids on create so existing catalogs can be loaded as-is.
+	ImportMode bool
+	// RequireIfMatch rejects updates and deletes of singers and albums that carry no If-Match
+	// header with 428 instead of applying them unconditionally.
+	RequireIfMatch bool
+	// MaxBatchSize caps the operations of one batch request; zero means DefaultMaxBatchSize.
+	MaxBatchSize int
+}
+
+// rejectClientID reports a client-supplied id unless import mode is enabled.
+func (o Options) rejectClientID(id *int) error {
+	if id == nil || o.ImportMode {
+		return nil
+	}
+	v := &model.ValidationError{}
+	v.Add("id", model.CodeReadOnly, "id is assigned by the server")
+	return v
+}
+
+func (o Options) maxBatchSize() int {
+	if o.MaxBatchSize <= 0 {
+		return DefaultMaxBatchSize
+	}
+	return o.MaxBatchSize
+}
@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// setNextLink advertises the next page with an RFC 8288 Link header.
+func setNextLink(w http.ResponseWriter, r *http.Request, cursor *model.Cursor) {
+	if cursor == nil {
+		return
+	}
+	values := r.URL.Query()
+	values.Set("cursor", cursor.Encode())
+	next := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+}
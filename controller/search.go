@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+)
+
+type SearchController interface {
+	SearchHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type searchController struct {
+	service service.SearchService
+}
+
+var _ SearchController = (*searchController)(nil)
+
+func NewSearchController(s service.SearchService) SearchController {
+	return &searchController{service: s}
+}
+
+// SearchHandler GET /search
+func (c *searchController) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := dto.NewSearchQuery(r.URL.Query())
+	if err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	page, err := c.service.SearchCatalogService(r.Context(), query)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setNextLink(w, r, page.NextCursor)
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewSearchResponse(page)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
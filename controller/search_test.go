@@ -0,0 +1,98 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type MockSearchService struct {
+	mock.Mock
+}
+
+func NewMockSearchService() *MockSearchService {
+	return &MockSearchService{}
+}
+
+func (m *MockSearchService) SearchCatalogService(ctx context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Page[*model.SearchResult]), args.Error(1)
+}
+
+type SearchControllerSuite struct {
+	suite.Suite
+	searchController  controller.SearchController
+	mockSearchService *MockSearchService
+}
+
+func TestSearchControllerSuite(t *testing.T) {
+	suite.Run(t, new(SearchControllerSuite))
+}
+
+func (suite *SearchControllerSuite) SetupTest() {
+	suite.mockSearchService = NewMockSearchService()
+	suite.searchController = controller.NewSearchController(suite.mockSearchService)
+}
+
+func (suite *SearchControllerSuite) TestSearchHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/search?q=ali&type=singer,album&limit=1", nil)
+	rr := httptest.NewRecorder()
+
+	q := &model.SearchQuery{
+		Query: "ali",
+		Types: []model.SearchResultType{model.SearchResultSinger, model.SearchResultAlbum},
+		Limit: 1,
+	}
+	next := &model.Cursor{Sort: "relevance", Offset: 1}
+	page := &model.Page[*model.SearchResult]{
+		Items: []*model.SearchResult{
+			{
+				Type:      model.SearchResultAlbum,
+				ID:        1,
+				Name:      "Alice's 1st Album",
+				Singer:    &model.Singer{ID: model.SingerID(1), Name: "Alice"},
+				Score:     1.2,
+				Highlight: "<em>Ali</em>ce's 1st Album",
+			},
+		},
+		NextCursor: next,
+	}
+	suite.mockSearchService.On("SearchCatalogService", req.Context(), q).Return(page, nil)
+	suite.searchController.SearchHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Contains(rr.Header().Get("Link"), "cursor="+next.Encode())
+
+	var res dto.SearchResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+
+	suite.Len(res.Items, 1)
+	suite.Equal("album", res.Items[0].Type)
+	suite.Equal("<em>Ali</em>ce's 1st Album", res.Items[0].Highlight)
+	suite.Equal("Alice", res.Items[0].Singer.Name)
+	suite.Equal(next.Encode(), res.NextCursor)
+
+	suite.mockSearchService.AssertExpectations(suite.T())
+}
+
+func (suite *SearchControllerSuite) TestSearchHandler_MissingQuery() {
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+	rr := httptest.NewRecorder()
+
+	suite.mockSearchService.On("SearchCatalogService", req.Context(), mock.Anything).
+		Return(nil, (&model.SearchQuery{Limit: 20}).Validate())
+	suite.searchController.SearchHandler(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
@@ -2,11 +2,11 @@ package controller
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	"github.com/go-sql-driver/mysql"
 	"github.com/pulse227/server-recruit-challenge-sample/dto"
-	"log/slog"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -16,33 +16,46 @@ import (
 type SingerController interface {
 	GetSingerListHandler(w http.ResponseWriter, r *http.Request)
 	GetSingerDetailHandler(w http.ResponseWriter, r *http.Request)
+	GetSingerAlbumsHandler(w http.ResponseWriter, r *http.Request)
 	PostSingerHandler(w http.ResponseWriter, r *http.Request)
+	PutSingerHandler(w http.ResponseWriter, r *http.Request)
+	PatchSingerHandler(w http.ResponseWriter, r *http.Request)
 	DeleteSingerHandler(w http.ResponseWriter, r *http.Request)
+	RestoreSingerHandler(w http.ResponseWriter, r *http.Request)
+	BatchSingersHandler(w http.ResponseWriter, r *http.Request)
+	ExportSingersHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type singerController struct {
 	service service.SingerService
+	options Options
 }
 
 var _ SingerController = (*singerController)(nil)
 
-func NewSingerController(s service.SingerService) SingerController {
-	return &singerController{service: s}
+func NewSingerController(s service.SingerService, options Options) SingerController {
+	return &singerController{service: s, options: options}
 }
 
 // GetSingerListHandler GET /singers
 func (c *singerController) GetSingerListHandler(w http.ResponseWriter, r *http.Request) {
-	singers, err := c.service.GetSingerListService(r.Context())
+	query, err := dto.NewSingerQuery(r.URL.Query())
 	if err != nil {
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+		badRequestHandler(w, r, err)
+		return
+	}
+	page, err := c.service.GetSingerListService(r.Context(), query)
+	if err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setNextLink(w, r, page.NextCursor)
 	w.WriteHeader(http.StatusOK)
-	res := dto.NewSingersResponse(singers)
+	res := dto.NewSingerListResponse(page)
 	if err = json.NewEncoder(w).Encode(res); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
 		return
 	}
 }
@@ -52,22 +65,64 @@ func (c *singerController) GetSingerDetailHandler(w http.ResponseWriter, r *http
 	ID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		err = fmt.Errorf("invalid path param: %w", err)
-		errorHandler(w, r, http.StatusBadRequest, err.Error())
+		badRequestHandler(w, r, err)
 		return
 	}
 	req := dto.GetSingerRequest{ID: ID}
-	albumID := req.ToModel()
-	singer, err := c.service.GetSingerService(r.Context(), *albumID)
+	singerID := req.ToModel()
+	include, err := dto.ParseInclude(r.URL.Query(), "albums", "stats")
 	if err != nil {
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+		badRequestHandler(w, r, err)
 		return
 	}
+	var singer *model.Singer
+	var discography *model.Discography
+	if len(include) > 0 {
+		singer, discography, err = c.service.GetSingerDiscographyService(r.Context(), *singerID)
+	} else {
+		singer, err = c.service.GetSingerService(r.Context(), *singerID)
+	}
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	// The version only covers the singer row, so embedded albums and stats get no ETag.
+	if len(include) == 0 {
+		w.Header().Set("ETag", singerETag(singer))
+		if notModified(r, singerETag(singer)) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	res := dto.NewSingerResponse(singer)
+	res := dto.NewSingerDetailResponse(singer, discography, include)
 	if err = json.NewEncoder(w).Encode(res); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// GetSingerAlbumsHandler GET /singers/{id}/albums
+func (c *singerController) GetSingerAlbumsHandler(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	albums, err := c.service.GetSingerAlbumsService(r.Context(), model.SingerID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewSingerAlbumListResponse(albums)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
 		return
 	}
 }
@@ -75,45 +130,222 @@ func (c *singerController) GetSingerDetailHandler(w http.ResponseWriter, r *http
 // PostSingerHandler POST /singers
 func (c *singerController) PostSingerHandler(w http.ResponseWriter, r *http.Request) {
 	req := dto.CreateSingerRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		err = fmt.Errorf("invalid body param: %w", err)
-		errorHandler(w, r, http.StatusBadRequest, err.Error())
+	if err := decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	if err := c.options.rejectClientID(req.ID); err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 	singer := req.ToModel()
 	if err := c.service.PostSingerService(r.Context(), singer); err != nil {
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+		errorHandler(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/singers/%d", singer.ID))
+	w.Header().Set("ETag", singerETag(singer))
 	w.WriteHeader(http.StatusCreated)
 	res := dto.NewSingerResponse(singer)
 	if err := json.NewEncoder(w).Encode(res); err != nil {
-		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// PutSingerHandler PUT /singers/{id}
+func (c *singerController) PutSingerHandler(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	version, ok := c.options.ifMatch(w, r, currentSinger(r.Context(), c.service.GetSingerService, model.SingerID(ID)))
+	if !ok {
+		return
+	}
+	req := dto.UpdateSingerRequest{}
+	if err = decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	c.updateSinger(w, r, req.ToModel(ID), version)
+}
+
+// PatchSingerHandler PATCH /singers/{id}
+func (c *singerController) PatchSingerHandler(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	current, err := c.service.GetSingerService(r.Context(), model.SingerID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	// the patch applies to current, so that is what If-Match is checked against
+	version, ok := c.options.ifMatch(w, r, func() (string, int64, error) {
+		return singerETag(current), current.Version, nil
+	})
+	if !ok {
+		return
+	}
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		err = fmt.Errorf("invalid body param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	target, err := json.Marshal(dto.NewUpdateSingerRequest(current))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	merged, err := dto.ApplyMergePatch(target, patch)
+	if err != nil {
+		err = fmt.Errorf("invalid body param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	req := dto.UpdateSingerRequest{}
+	if err = decodeJSON(merged, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	c.updateSinger(w, r, req.ToModel(ID), version)
+}
+
+func (c *singerController) updateSinger(w http.ResponseWriter, r *http.Request, singer *model.Singer, version int64) {
+	singer.Version = version
+	if err := c.service.UpdateSingerService(r.Context(), singer); err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", singerETag(singer))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewSingerResponse(singer)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
 		return
 	}
 }
 
-// DeleteSingerHandler DELETE /singers/{id}
+// DeleteSingerHandler DELETE /singers/{id}?cascade=&dry_run=
 func (c *singerController) DeleteSingerHandler(w http.ResponseWriter, r *http.Request) {
 	ID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		err = fmt.Errorf("invalid path param: %w", err)
-		errorHandler(w, r, http.StatusBadRequest, err.Error())
+		badRequestHandler(w, r, err)
+		return
+	}
+	req, err := dto.NewDeleteSingerRequest(ID, r.URL.Query())
+	if err != nil {
+		badRequestHandler(w, r, err)
 		return
 	}
-	req := dto.DeleteSingerRequest{ID: ID}
 	singerID := req.ToModel()
-	if err = c.service.DeleteSingerService(r.Context(), *singerID); err != nil {
-		var mysqlErr *mysql.MySQLError
-		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1451 {
-			err = fmt.Errorf("cannot delete singer: related albums exist: %w", err)
-			errorHandler(w, r, http.StatusConflict, err.Error())
+	var version int64
+	if !req.DryRun {
+		var ok bool
+		if version, ok = c.options.ifMatch(w, r, currentSinger(r.Context(), c.service.GetSingerService, *singerID)); !ok {
 			return
 		}
-		errorHandler(w, r, http.StatusInternalServerError, err.Error())
+	}
+	if !req.Cascade {
+		if err = c.service.DeleteSingerService(r.Context(), *singerID, version); err != nil {
+			errorHandler(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	deletion, err := c.service.DeleteSingerCascadeService(r.Context(), *singerID, version, req.DryRun)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewSingerDeletionResponse(deletion)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// RestoreSingerHandler POST /singers/{id}:restore
+func (c *singerController) RestoreSingerHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := actionID(w, r, "restore")
+	if !ok {
+		return
+	}
+	singer, err := c.service.RestoreSingerService(r.Context(), model.SingerID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewSingerResponse(singer)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// BatchSingersHandler POST /singers:batch
+func (c *singerController) BatchSingersHandler(w http.ResponseWriter, r *http.Request) {
+	req := dto.BatchRequest[dto.SingerOperationRequest]{}
+	if err := decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	mode, ops, err := batchOperations[*model.Singer](c.options, &req)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	results, err := c.service.BatchSingerService(r.Context(), mode, ops)
+	if err != nil {
+		batchErrorHandler(w, r, err)
+		return
+	}
+	writeBatchResults(w, r, results)
+}
+
+// ExportSingersHandler GET /exports/singers
+func (c *singerController) ExportSingersHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := dto.NewSingerExportQuery(r.URL.Query())
+	if err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+	format, ok := negotiateExport(w, r)
+	if !ok {
+		return
+	}
+
+	export := newExportWriter(w, r, format, "singers", dto.SingerExportHeader)
+	defer export.close(r)
+	err = c.service.ExportSingersService(r.Context(), q, func(singer *model.Singer) error {
+		return export.write(dto.NewSingerExportRow(singer))
+	})
+	if err != nil {
+		export.fail(r, err)
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+	export.finish(r)
 }
@@ -6,12 +6,14 @@ import (
 	"github.com/pulse227/server-recruit-challenge-sample/controller"
 	"github.com/pulse227/server-recruit-challenge-sample/dto"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 type MockSingerService struct {
@@ -22,12 +24,12 @@ func NewMockSingerService() *MockSingerService {
 	return &MockSingerService{}
 }
 
-func (m *MockSingerService) GetSingerListService(ctx context.Context) ([]*model.Singer, error) {
-	args := m.Called(ctx)
+func (m *MockSingerService) GetSingerListService(ctx context.Context, q *model.SingerQuery) (*model.Page[*model.Singer], error) {
+	args := m.Called(ctx, q)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Singer), args.Error(1)
+	return args.Get(0).(*model.Page[*model.Singer]), args.Error(1)
 }
 
 func (m *MockSingerService) GetSingerService(ctx context.Context, singerID model.SingerID) (*model.Singer, error) {
@@ -38,6 +40,22 @@ func (m *MockSingerService) GetSingerService(ctx context.Context, singerID model
 	return args.Get(0).(*model.Singer), args.Error(1)
 }
 
+func (m *MockSingerService) GetSingerAlbumsService(ctx context.Context, singerID model.SingerID) ([]*model.SingerAlbum, error) {
+	args := m.Called(ctx, singerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SingerAlbum), args.Error(1)
+}
+
+func (m *MockSingerService) GetSingerDiscographyService(ctx context.Context, singerID model.SingerID) (*model.Singer, *model.Discography, error) {
+	args := m.Called(ctx, singerID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Singer), args.Get(1).(*model.Discography), args.Error(2)
+}
+
 func (m *MockSingerService) PostSingerService(ctx context.Context, singer *model.Singer) error {
 	args := m.Called(ctx, singer)
 	if err, ok := args.Get(0).(error); ok {
@@ -46,14 +64,58 @@ func (m *MockSingerService) PostSingerService(ctx context.Context, singer *model
 	return nil
 }
 
-func (m *MockSingerService) DeleteSingerService(ctx context.Context, singerID model.SingerID) error {
-	args := m.Called(ctx, singerID)
+func (m *MockSingerService) UpdateSingerService(ctx context.Context, singer *model.Singer) error {
+	args := m.Called(ctx, singer)
 	if err, ok := args.Get(0).(error); ok {
 		return err
 	}
 	return nil
 }
 
+func (m *MockSingerService) DeleteSingerService(ctx context.Context, singerID model.SingerID, version int64) error {
+	args := m.Called(ctx, singerID, version)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockSingerService) DeleteSingerCascadeService(ctx context.Context, singerID model.SingerID, version int64, dryRun bool) (*model.SingerDeletion, error) {
+	args := m.Called(ctx, singerID, version, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SingerDeletion), args.Error(1)
+}
+
+func (m *MockSingerService) RestoreSingerService(ctx context.Context, singerID model.SingerID) (*model.Singer, error) {
+	args := m.Called(ctx, singerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Singer), args.Error(1)
+}
+
+func (m *MockSingerService) BatchSingerService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Singer]) ([]*model.BatchResult, error) {
+	args := m.Called(ctx, mode, ops)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.BatchResult), args.Error(1)
+}
+
+func (m *MockSingerService) ExportSingersService(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) error {
+	args := m.Called(ctx, q)
+	if singers, ok := args.Get(0).([]*model.Singer); ok {
+		for _, singer := range singers {
+			if err := fn(singer); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 type SingerControllerSuite struct {
 	suite.Suite
 	singerController  controller.SingerController
@@ -66,7 +128,7 @@ func TestSingerControllerSuite(t *testing.T) {
 
 func (suite *SingerControllerSuite) SetupTest() {
 	suite.mockSingerService = NewMockSingerService()
-	suite.singerController = controller.NewSingerController(suite.mockSingerService)
+	suite.singerController = controller.NewSingerController(suite.mockSingerService, controller.Options{})
 }
 
 func (suite *SingerControllerSuite) TestGetSingerListHandler() {
@@ -84,14 +146,16 @@ func (suite *SingerControllerSuite) TestGetSingerListHandler() {
 		},
 	}
 
-	suite.mockSingerService.On("GetSingerListService", req.Context()).Return(singers, nil)
+	q := &model.SingerQuery{Limit: model.DefaultPageLimit, Sort: model.SingerSortID}
+	suite.mockSingerService.On("GetSingerListService", req.Context(), q).Return(&model.Page[*model.Singer]{Items: singers}, nil)
 	suite.singerController.GetSingerListHandler(rr, req)
 
 	suite.Equal(http.StatusOK, rr.Code)
 
-	var res []*dto.SingerResponse
-	err := json.NewDecoder(rr.Body).Decode(&res)
+	var list dto.SingerListResponse
+	err := json.NewDecoder(rr.Body).Decode(&list)
 	suite.NoError(err)
+	res := list.Items
 
 	suite.Len(res, 2)
 	suite.Equal(1, res[0].ID)
@@ -105,7 +169,7 @@ func (suite *SingerControllerSuite) TestGetSingerListHandler() {
 func (suite *SingerControllerSuite) TestGetSingerDetailHandler() {}
 
 func (suite *SingerControllerSuite) TestPostSingerHandler() {
-	body := `{"id":1,"name":"Singer 1"}`
+	body := `{"name":"Singer 1"}`
 	req := httptest.NewRequest(http.MethodPost, "/singers", strings.NewReader(body))
 	rr := httptest.NewRecorder()
 
@@ -113,19 +177,433 @@ func (suite *SingerControllerSuite) TestPostSingerHandler() {
 	err := json.NewDecoder(strings.NewReader(body)).Decode(&singer)
 	suite.NoError(err)
 
-	suite.mockSingerService.On("PostSingerService", req.Context(), singer.ToModel()).Return(nil)
+	suite.mockSingerService.On("PostSingerService", req.Context(), singer.ToModel()).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Singer).ID = model.SingerID(6)
+		}).
+		Return(nil)
 	suite.singerController.PostSingerHandler(rr, req)
 
 	suite.Equal(http.StatusCreated, rr.Code)
+	suite.Equal("/singers/6", rr.Header().Get("Location"))
 
 	var res dto.SingerResponse
 	err = json.NewDecoder(rr.Body).Decode(&res)
 	suite.NoError(err)
 
-	suite.Equal(1, res.ID)
+	suite.Equal(6, res.ID)
 	suite.Equal("Singer 1", res.Name)
 
 	suite.mockSingerService.AssertExpectations(suite.T())
 }
 
+func (suite *SingerControllerSuite) TestPostSingerHandler_ClientIDRejected() {
+	req := httptest.NewRequest(http.MethodPost, "/singers", strings.NewReader(`{"id":1,"name":"Singer 1"}`))
+	rr := httptest.NewRecorder()
+
+	suite.singerController.PostSingerHandler(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+	suite.mockSingerService.AssertNotCalled(suite.T(), "PostSingerService", mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestPostSingerHandler_BodyTooLarge() {
+	req := httptest.NewRequest(http.MethodPost, "/singers", strings.NewReader(`{"name":"Singer 1"}`))
+	rr := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(rr, req.Body, 8)
+
+	suite.singerController.PostSingerHandler(rr, req)
+
+	suite.Equal(http.StatusRequestEntityTooLarge, rr.Code)
+	suite.Contains(rr.Body.String(), "request body exceeds 8 bytes")
+	suite.mockSingerService.AssertNotCalled(suite.T(), "PostSingerService", mock.Anything, mock.Anything)
+}
+
 func (suite *SingerControllerSuite) TestDeleteSingerHandler() {}
+
+func (suite *SingerControllerSuite) TestPutSingerHandler() {
+	body := `{"name":"Updated Singer"}`
+	req := httptest.NewRequest(http.MethodPut, "/singers/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	singer := &model.Singer{ID: model.SingerID(1), Name: "Updated Singer"}
+	suite.mockSingerService.On("UpdateSingerService", req.Context(), singer).Return(nil)
+	suite.singerController.PutSingerHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.SingerResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+
+	suite.Equal(1, res.ID)
+	suite.Equal("Updated Singer", res.Name)
+
+	suite.mockSingerService.AssertExpectations(suite.T())
+}
+
+func (suite *SingerControllerSuite) TestPutSingerHandler_IfMatchRequired() {
+	singerController := controller.NewSingerController(suite.mockSingerService, controller.Options{RequireIfMatch: true})
+	req := httptest.NewRequest(http.MethodPut, "/singers/1", strings.NewReader(`{"name":"Updated Singer"}`))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	singerController.PutSingerHandler(rr, req)
+
+	suite.Equal(http.StatusPreconditionRequired, rr.Code)
+	suite.Contains(rr.Body.String(), "/problems/precondition-required")
+	suite.mockSingerService.AssertNotCalled(suite.T(), "UpdateSingerService", mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestPatchSingerHandler_VersionMismatch() {
+	req := httptest.NewRequest(http.MethodPatch, "/singers/1", strings.NewReader(`{"name":"Patched"}`))
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `"1"`)
+	rr := httptest.NewRecorder()
+
+	suite.mockSingerService.On("GetSingerService", req.Context(), model.SingerID(1)).
+		Return(&model.Singer{ID: model.SingerID(1), Name: "Alice", Version: 2}, nil)
+	suite.singerController.PatchSingerHandler(rr, req)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+	suite.mockSingerService.AssertExpectations(suite.T())
+	suite.mockSingerService.AssertNotCalled(suite.T(), "UpdateSingerService", mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestGetSingerDetailHandler_NotModified() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/1", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-None-Match", `"7"`)
+	rr := httptest.NewRecorder()
+
+	suite.mockSingerService.On("GetSingerService", req.Context(), model.SingerID(1)).
+		Return(&model.Singer{ID: model.SingerID(1), Name: "Alice", Version: 7}, nil)
+	suite.singerController.GetSingerDetailHandler(rr, req)
+
+	suite.Equal(http.StatusNotModified, rr.Code)
+	suite.Equal(`"7"`, rr.Header().Get("ETag"))
+}
+
+func (suite *SingerControllerSuite) TestPatchSingerHandler_NullName() {
+	req := httptest.NewRequest(http.MethodPatch, "/singers/1", strings.NewReader(`{"name":null}`))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	current := &model.Singer{ID: model.SingerID(1), Name: "Singer 1"}
+	patched := &model.Singer{ID: model.SingerID(1), Name: ""}
+	suite.mockSingerService.On("GetSingerService", req.Context(), model.SingerID(1)).Return(current, nil)
+	suite.mockSingerService.On("UpdateSingerService", req.Context(), patched).Return(model.ErrInvalidParam)
+	suite.singerController.PatchSingerHandler(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	suite.mockSingerService.AssertExpectations(suite.T())
+}
+
+func (suite *SingerControllerSuite) TestDeleteSingerHandler_HasAlbums() {
+	req := httptest.NewRequest(http.MethodDelete, "/singers/1", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	suite.mockSingerService.On("DeleteSingerService", req.Context(), model.SingerID(1), int64(0)).
+		Return(repository.ErrorSingerHasAlbums)
+	suite.singerController.DeleteSingerHandler(rr, req)
+
+	suite.Equal(http.StatusConflict, rr.Code)
+
+	suite.mockSingerService.AssertExpectations(suite.T())
+}
+
+func (suite *SingerControllerSuite) TestDeleteSingerHandler_Cascade() {
+	req := httptest.NewRequest(http.MethodDelete, "/singers/1?cascade=true", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", `"2"`)
+	rr := httptest.NewRecorder()
+
+	deletion := &model.SingerDeletion{SingerID: model.SingerID(1), AlbumIDs: []model.AlbumID{1, 2}}
+	suite.mockSingerService.On("GetSingerService", req.Context(), model.SingerID(1)).
+		Return(&model.Singer{ID: model.SingerID(1), Name: "Alice", Version: 2}, nil)
+	suite.mockSingerService.On("DeleteSingerCascadeService", req.Context(), model.SingerID(1), int64(2), false).Return(deletion, nil)
+	suite.singerController.DeleteSingerHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.JSONEq(`{"singer_id":1,"deleted_album_ids":[1,2],"dry_run":false}`, rr.Body.String())
+
+	suite.mockSingerService.AssertExpectations(suite.T())
+}
+
+func (suite *SingerControllerSuite) TestDeleteSingerHandler_DryRun() {
+	req := httptest.NewRequest(http.MethodDelete, "/singers/3?cascade=true&dry_run=true", nil)
+	req.SetPathValue("id", "3")
+	rr := httptest.NewRecorder()
+
+	deletion := &model.SingerDeletion{SingerID: model.SingerID(3), AlbumIDs: []model.AlbumID{}, DryRun: true}
+	suite.mockSingerService.On("DeleteSingerCascadeService", req.Context(), model.SingerID(3), int64(0), true).Return(deletion, nil)
+	singerController := controller.NewSingerController(suite.mockSingerService, controller.Options{RequireIfMatch: true})
+	singerController.DeleteSingerHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.JSONEq(`{"singer_id":3,"deleted_album_ids":[],"dry_run":true}`, rr.Body.String())
+}
+
+func (suite *SingerControllerSuite) TestDeleteSingerHandler_DryRunWithoutCascade() {
+	req := httptest.NewRequest(http.MethodDelete, "/singers/4?dry_run=true", nil)
+	req.SetPathValue("id", "4")
+	rr := httptest.NewRecorder()
+
+	suite.singerController.DeleteSingerHandler(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+	suite.mockSingerService.AssertNotCalled(suite.T(), "DeleteSingerService", mock.Anything, model.SingerID(4), mock.Anything)
+	suite.mockSingerService.AssertNotCalled(suite.T(), "DeleteSingerCascadeService", mock.Anything, model.SingerID(4), mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestPostSingerHandler_InvalidParam() {
+	req := httptest.NewRequest(http.MethodPost, "/singers", strings.NewReader(`{"name":""}`))
+	rr := httptest.NewRecorder()
+
+	singer := &model.Singer{Name: ""}
+	suite.mockSingerService.On("PostSingerService", req.Context(), singer).Return(model.ErrInvalidParam)
+	suite.singerController.PostSingerHandler(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	suite.mockSingerService.AssertExpectations(suite.T())
+}
+
+func (suite *SingerControllerSuite) TestGetSingerAlbumsHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/2/albums", nil)
+	req.SetPathValue("id", "2")
+	rr := httptest.NewRecorder()
+
+	albums := []*model.SingerAlbum{
+		{
+			Album: &model.Album{
+				ID:     model.AlbumID(2),
+				Title:  "Alice's 2nd Album",
+				Singer: &model.Singer{ID: model.SingerID(1), Name: "Alice"},
+			},
+			Roles: []model.CreditRole{model.CreditRoleFeatured, model.CreditRoleProducer},
+		},
+	}
+	suite.mockSingerService.On("GetSingerAlbumsService", req.Context(), model.SingerID(2)).Return(albums, nil)
+	suite.singerController.GetSingerAlbumsHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.SingerAlbumListResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Len(res.Items, 1)
+	suite.Equal("Alice's 2nd Album", res.Items[0].Title)
+	suite.Equal("Alice", res.Items[0].Singer.Name)
+	suite.Equal([]string{"featured", "producer"}, res.Items[0].Roles)
+}
+
+func (suite *SingerControllerSuite) TestGetSingerAlbumsHandler_NotFound() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/99/albums", nil)
+	req.SetPathValue("id", "99")
+	rr := httptest.NewRecorder()
+
+	suite.mockSingerService.On("GetSingerAlbumsService", req.Context(), model.SingerID(99)).
+		Return(nil, repository.ErrorSingerNotFound)
+	suite.singerController.GetSingerAlbumsHandler(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *SingerControllerSuite) TestGetSingerDetailHandler_IncludeAlbumsAndStats() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/1?include=albums,stats", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	alice := &model.Singer{ID: model.SingerID(1), Name: "Alice"}
+	discography := model.NewDiscography([]*model.SingerAlbum{
+		{
+			Album: &model.Album{ID: 1, Title: "Alice's 1st Album", Singer: alice, CreatedAt: time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC)},
+			Roles: []model.CreditRole{model.CreditRolePrimary},
+		},
+		{
+			Album: &model.Album{ID: 2, Title: "Alice's 2nd Album", Singer: alice, CreatedAt: time.Date(2022, 8, 15, 9, 0, 0, 0, time.UTC)},
+			Roles: []model.CreditRole{model.CreditRolePrimary},
+		},
+	})
+	suite.mockSingerService.On("GetSingerDiscographyService", req.Context(), model.SingerID(1)).Return(alice, discography, nil)
+	suite.singerController.GetSingerDetailHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.SingerDetailResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("Alice", res.Name)
+	suite.Len(res.Albums, 2)
+	suite.Require().NotNil(res.Stats)
+	suite.Equal(2, res.Stats.AlbumCount)
+	suite.Equal(time.Date(2020, 3, 1, 9, 0, 0, 0, time.UTC), *res.Stats.FirstAddedAt)
+	suite.Equal(time.Date(2022, 8, 15, 9, 0, 0, 0, time.UTC), *res.Stats.LatestAddedAt)
+}
+
+func (suite *SingerControllerSuite) TestGetSingerDetailHandler_StatsOnly() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/5?include=stats", nil)
+	req.SetPathValue("id", "5")
+	rr := httptest.NewRecorder()
+
+	ellen := &model.Singer{ID: model.SingerID(5), Name: "Ellen"}
+	suite.mockSingerService.On("GetSingerDiscographyService", req.Context(), model.SingerID(5)).
+		Return(ellen, model.NewDiscography([]*model.SingerAlbum{}), nil)
+	suite.singerController.GetSingerDetailHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.JSONEq(`{"id":5,"name":"Ellen","stats":{"album_count":0,"first_added_at":null,"latest_added_at":null}}`, rr.Body.String())
+}
+
+func (suite *SingerControllerSuite) TestGetSingerDetailHandler_IncludeNotFound() {
+	req := httptest.NewRequest(http.MethodGet, "/singers/99?include=albums", nil)
+	req.SetPathValue("id", "99")
+	rr := httptest.NewRecorder()
+
+	suite.mockSingerService.On("GetSingerDiscographyService", req.Context(), model.SingerID(99)).
+		Return(nil, nil, repository.ErrorSingerNotFound)
+	suite.singerController.GetSingerDetailHandler(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *SingerControllerSuite) TestRestoreSingerHandler() {
+	req := httptest.NewRequest(http.MethodPost, "/singers/4:restore", nil)
+	req.SetPathValue("id", "4:restore")
+	rr := httptest.NewRecorder()
+
+	singer := &model.Singer{ID: model.SingerID(4), Name: "Daisy"}
+	suite.mockSingerService.On("RestoreSingerService", req.Context(), model.SingerID(4)).Return(singer, nil)
+	suite.singerController.RestoreSingerHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.SingerResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal("Daisy", res.Name)
+}
+
+func (suite *SingerControllerSuite) TestRestoreSingerHandler_UnknownAction() {
+	req := httptest.NewRequest(http.MethodPost, "/singers/4:archive", nil)
+	req.SetPathValue("id", "4:archive")
+	rr := httptest.NewRecorder()
+
+	suite.singerController.RestoreSingerHandler(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+	suite.mockSingerService.AssertNotCalled(suite.T(), "RestoreSingerService", mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestRestoreSingerHandler_NotInTrash() {
+	req := httptest.NewRequest(http.MethodPost, "/singers/1:restore", nil)
+	req.SetPathValue("id", "1:restore")
+	rr := httptest.NewRecorder()
+
+	suite.mockSingerService.On("RestoreSingerService", req.Context(), model.SingerID(1)).
+		Return(nil, repository.ErrorSingerNotInTrash)
+	suite.singerController.RestoreSingerHandler(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *SingerControllerSuite) TestBatchSingersHandler() {
+	body := `{"mode":"atomic","operations":[{"op":"create","name":"Erin"},{"op":"update","id":2,"version":3,"name":"Frank"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/singers:batch", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	ops := []model.BatchOperation[*model.Singer]{
+		{Action: model.BatchCreate, Item: &model.Singer{Name: "Erin"}},
+		{Action: model.BatchUpdate, Item: &model.Singer{ID: model.SingerID(2), Name: "Frank", Version: 3}},
+	}
+	results := []*model.BatchResult{
+		{Index: 0, Action: model.BatchCreate, ID: 9},
+		{Index: 1, Action: model.BatchUpdate, ID: 2},
+	}
+	suite.mockSingerService.On("BatchSingerService", req.Context(), model.BatchAtomic, ops).Return(results, nil)
+	suite.singerController.BatchSingersHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.BatchResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Require().Len(res.Results, 2)
+	suite.Equal(http.StatusCreated, res.Results[0].Status)
+	suite.Equal(http.StatusOK, res.Results[1].Status)
+}
+
+func (suite *SingerControllerSuite) TestBatchSingersHandler_InvalidOperations() {
+	suite.singerController = controller.NewSingerController(suite.mockSingerService, controller.Options{RequireIfMatch: true})
+	body := `{"mode":"all","operations":[{"op":"upsert","name":"Erin"},{"op":"update","name":"Frank"},{"op":"create","id":5,"name":"Gina"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/singers:batch", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	suite.singerController.BatchSingersHandler(rr, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	fields := make([]string, 0, len(res.Errors))
+	for _, f := range res.Errors {
+		fields = append(fields, f.Field)
+	}
+	suite.ElementsMatch([]string{"mode", "operations[0].op", "operations[1].id", "operations[1].version", "operations[2].id"}, fields)
+	suite.mockSingerService.AssertNotCalled(suite.T(), "BatchSingerService", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestExportSingersHandler_CSV() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/singers?name_prefix=A&order=desc", nil)
+	req.Header.Set("Accept", "application/xml, text/csv;q=0.9")
+	rr := httptest.NewRecorder()
+
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	q := &model.SingerQuery{Sort: model.SingerSortID, Desc: true, NamePrefix: "A"}
+	singers := []*model.Singer{
+		{ID: model.SingerID(2), Name: "Anna, Jr.", CreatedAt: createdAt},
+		{ID: model.SingerID(1), Name: "Alice", CreatedAt: createdAt},
+	}
+	suite.mockSingerService.On("ExportSingersService", req.Context(), q).Return(singers, nil)
+	suite.singerController.ExportSingersHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	suite.Equal(
+		"id,name,created_at\n"+
+			"2,\"Anna, Jr.\",2026-10-18T09:00:00Z\n"+
+			"1,Alice,2026-10-18T09:00:00Z\n",
+		rr.Body.String(),
+	)
+}
+
+func (suite *SingerControllerSuite) TestExportSingersHandler_NotAcceptable() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/singers", nil)
+	req.Header.Set("Accept", "application/xml")
+	rr := httptest.NewRecorder()
+
+	suite.singerController.ExportSingersHandler(rr, req)
+
+	suite.Equal(http.StatusNotAcceptable, rr.Code)
+	suite.mockSingerService.AssertNotCalled(suite.T(), "ExportSingersService", mock.Anything, mock.Anything)
+}
+
+func (suite *SingerControllerSuite) TestExportSingersHandler_InvalidFormat() {
+	req := httptest.NewRequest(http.MethodGet, "/exports/singers?format=xlsx", nil)
+	rr := httptest.NewRecorder()
+
+	suite.singerController.ExportSingersHandler(rr, req)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+
+	var res controller.Problem
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Require().Len(res.Errors, 1)
+	suite.Equal("format", res.Errors[0].Field)
+}
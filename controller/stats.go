@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+)
+
+// DBStatser is satisfied by *sql.DB.
+type DBStatser interface {
+	Stats() sql.DBStats
+}
+
+type StatsController interface {
+	GetDBStatsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type statsController struct {
+	db DBStatser
+}
+
+var _ StatsController = (*statsController)(nil)
+
+func NewStatsController(db DBStatser) StatsController {
+	return &statsController{db: db}
+}
+
+// GetDBStatsHandler GET /internal/db/stats
+func (c *statsController) GetDBStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewDBStatsResponse(c.db.Stats())
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package controller_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/stretchr/testify/suite"
+)
+
+type stubDBStatser sql.DBStats
+
+func (s stubDBStatser) Stats() sql.DBStats {
+	return sql.DBStats(s)
+}
+
+type StatsControllerSuite struct {
+	suite.Suite
+}
+
+func TestStatsControllerSuite(t *testing.T) {
+	suite.Run(t, new(StatsControllerSuite))
+}
+
+func (suite *StatsControllerSuite) TestGetDBStatsHandler() {
+	stats := stubDBStatser{
+		MaxOpenConnections: 25,
+		OpenConnections:    3,
+		InUse:              1,
+		Idle:               2,
+		WaitCount:          4,
+		WaitDuration:       1500 * time.Millisecond,
+		MaxLifetimeClosed:  7,
+	}
+	statsController := controller.NewStatsController(stats)
+
+	req := httptest.NewRequest(http.MethodGet, "/internal/db/stats", nil)
+	rr := httptest.NewRecorder()
+	statsController.GetDBStatsHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("no-store", rr.Header().Get("Cache-Control"))
+
+	var res dto.DBStatsResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal(dto.DBStatsResponse{
+		MaxOpenConnections: 25,
+		OpenConnections:    3,
+		InUse:              1,
+		Idle:               2,
+		WaitCount:          4,
+		WaitDurationMs:     1500,
+		MaxLifetimeClosed:  7,
+	}, res)
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type TrackController interface {
+	GetAlbumTracks(w http.ResponseWriter, r *http.Request)
+	CreateTrack(w http.ResponseWriter, r *http.Request)
+	ReorderTracks(w http.ResponseWriter, r *http.Request)
+	GetTrack(w http.ResponseWriter, r *http.Request)
+	PatchTrack(w http.ResponseWriter, r *http.Request)
+	DeleteTrack(w http.ResponseWriter, r *http.Request)
+}
+type trackController struct {
+	service service.TrackService
+}
+
+var _ TrackController = (*trackController)(nil)
+
+func NewTrackController(s service.TrackService) TrackController {
+	return &trackController{service: s}
+}
+
+// GetAlbumTracks GET /albums/{id}/tracks
+func (t trackController) GetAlbumTracks(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	tracks, err := t.service.GetTrackListService(r.Context(), model.AlbumID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewTrackListResponse(tracks)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// CreateTrack POST /albums/{id}/tracks
+func (t trackController) CreateTrack(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	req := dto.CreateTrackRequest{}
+	if err = decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	track := req.ToModel(ID)
+	if err = t.service.PostTrackService(r.Context(), track); err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/tracks/%d", track.ID))
+	w.WriteHeader(http.StatusCreated)
+	res := dto.NewTrackResponse(track)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// ReorderTracks POST /albums/{id}/tracks:reorder
+func (t trackController) ReorderTracks(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	req := dto.ReorderTracksRequest{}
+	if err = decodeBody(r.Body, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	tracks, err := t.service.ReorderTracksService(r.Context(), model.AlbumID(ID), req.ToModel())
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewTrackListResponse(tracks)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// GetTrack GET /tracks/{id}
+func (t trackController) GetTrack(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	track, err := t.service.GetTrackService(r.Context(), model.TrackID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewTrackResponse(track)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// PatchTrack PATCH /tracks/{id}
+func (t trackController) PatchTrack(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		err = fmt.Errorf("invalid body param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	current, err := t.service.GetTrackService(r.Context(), model.TrackID(ID))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	target, err := json.Marshal(dto.NewUpdateTrackRequest(current))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	merged, err := dto.ApplyMergePatch(target, patch)
+	if err != nil {
+		err = fmt.Errorf("invalid body param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	req := dto.UpdateTrackRequest{}
+	if err = decodeJSON(merged, &req); err != nil {
+		badRequestHandler(w, r, err)
+		return
+	}
+
+	track := req.ToModel(ID, current.AlbumID)
+	if err = t.service.UpdateTrackService(r.Context(), track); err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewTrackResponse(track)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
+
+// DeleteTrack DELETE /tracks/{id}
+func (t trackController) DeleteTrack(w http.ResponseWriter, r *http.Request) {
+	ID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		err = fmt.Errorf("invalid path param: %w", err)
+		badRequestHandler(w, r, err)
+		return
+	}
+	if err = t.service.DeleteTrackService(r.Context(), model.TrackID(ID)); err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,218 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type MockTrackService struct {
+	mock.Mock
+}
+
+func NewMockTrackService() *MockTrackService {
+	return &MockTrackService{}
+}
+
+func (m *MockTrackService) GetTrackListService(ctx context.Context, albumID model.AlbumID) ([]*model.Track, error) {
+	args := m.Called(ctx, albumID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Track), args.Error(1)
+}
+
+func (m *MockTrackService) GetTrackService(ctx context.Context, trackID model.TrackID) (*model.Track, error) {
+	args := m.Called(ctx, trackID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Track), args.Error(1)
+}
+
+func (m *MockTrackService) PostTrackService(ctx context.Context, track *model.Track) error {
+	args := m.Called(ctx, track)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackService) UpdateTrackService(ctx context.Context, track *model.Track) error {
+	args := m.Called(ctx, track)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackService) DeleteTrackService(ctx context.Context, trackID model.TrackID) error {
+	args := m.Called(ctx, trackID)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackService) ReorderTracksService(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) ([]*model.Track, error) {
+	args := m.Called(ctx, albumID, positions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Track), args.Error(1)
+}
+
+type TrackControllerSuite struct {
+	suite.Suite
+	trackController  controller.TrackController
+	mockTrackService *MockTrackService
+}
+
+func TestTrackControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrackControllerSuite))
+}
+
+func (suite *TrackControllerSuite) SetupTest() {
+	suite.mockTrackService = NewMockTrackService()
+	suite.trackController = controller.NewTrackController(suite.mockTrackService)
+}
+
+func (suite *TrackControllerSuite) TestGetAlbumTracks_Success() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/1/tracks", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	tracks := []*model.Track{
+		{ID: model.TrackID(1), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "Opening", DurationSeconds: 95},
+		{ID: model.TrackID(2), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 2, Title: "Closing", DurationSeconds: 240},
+	}
+	suite.mockTrackService.On("GetTrackListService", req.Context(), model.AlbumID(1)).Return(tracks, nil)
+	suite.trackController.GetAlbumTracks(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.TrackListResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Len(res.Items, 2)
+	suite.Equal(335, res.TotalDurationSeconds)
+}
+
+func (suite *TrackControllerSuite) TestGetAlbumTracks_AlbumNotFound() {
+	req := httptest.NewRequest(http.MethodGet, "/albums/99/tracks", nil)
+	req.SetPathValue("id", "99")
+	rr := httptest.NewRecorder()
+
+	suite.mockTrackService.On("GetTrackListService", req.Context(), model.AlbumID(99)).Return(nil, repository.ErrorAlbumNotFound)
+	suite.trackController.GetAlbumTracks(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *TrackControllerSuite) TestCreateTrack_Success() {
+	body := `{"track_number":3,"title":"Encore","duration_seconds":180,"isrc":"jp-abc-24-00003"}`
+	req := httptest.NewRequest(http.MethodPost, "/albums/1/tracks", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	track := &model.Track{AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 3, Title: "Encore", DurationSeconds: 180, ISRC: "JPABC2400003"}
+	suite.mockTrackService.On("PostTrackService", req.Context(), track).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Track).ID = model.TrackID(4)
+	})
+	suite.trackController.CreateTrack(rr, req)
+
+	suite.Equal(http.StatusCreated, rr.Code)
+	suite.Equal("/tracks/4", rr.Header().Get("Location"))
+
+	suite.mockTrackService.AssertExpectations(suite.T())
+}
+
+func (suite *TrackControllerSuite) TestCreateTrack_PositionTaken() {
+	body := `{"track_number":1,"title":"Again","duration_seconds":180}`
+	req := httptest.NewRequest(http.MethodPost, "/albums/1/tracks", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	suite.mockTrackService.On("PostTrackService", req.Context(), mock.Anything).Return(repository.ErrorTrackPositionTaken)
+	suite.trackController.CreateTrack(rr, req)
+
+	suite.Equal(http.StatusConflict, rr.Code)
+}
+
+func (suite *TrackControllerSuite) TestReorderTracks_Success() {
+	body := `{"tracks":[{"id":2,"track_number":1},{"id":1,"track_number":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/albums/1/tracks:reorder", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	positions := []model.TrackPosition{
+		{TrackID: model.TrackID(2), DiscNumber: 1, TrackNumber: 1},
+		{TrackID: model.TrackID(1), DiscNumber: 1, TrackNumber: 2},
+	}
+	tracks := []*model.Track{
+		{ID: model.TrackID(2), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "Closing", DurationSeconds: 240},
+		{ID: model.TrackID(1), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 2, Title: "Opening", DurationSeconds: 95},
+	}
+	suite.mockTrackService.On("ReorderTracksService", req.Context(), model.AlbumID(1), positions).Return(tracks, nil)
+	suite.trackController.ReorderTracks(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.TrackListResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Equal(2, res.Items[0].ID)
+}
+
+func (suite *TrackControllerSuite) TestPatchTrack_Success() {
+	body := `{"explicit":true}`
+	req := httptest.NewRequest(http.MethodPatch, "/tracks/1", strings.NewReader(body))
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+
+	current := &model.Track{ID: model.TrackID(1), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "Opening", DurationSeconds: 95}
+	updated := *current
+	updated.Explicit = true
+	suite.mockTrackService.On("GetTrackService", req.Context(), model.TrackID(1)).Return(current, nil)
+	suite.mockTrackService.On("UpdateTrackService", req.Context(), &updated).Return(nil)
+	suite.trackController.PatchTrack(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.TrackResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.True(res.Explicit)
+	suite.Equal("Opening", res.Title)
+}
+
+func (suite *TrackControllerSuite) TestDeleteTrack_NotFound() {
+	req := httptest.NewRequest(http.MethodDelete, "/tracks/9", nil)
+	req.SetPathValue("id", "9")
+	rr := httptest.NewRecorder()
+
+	suite.mockTrackService.On("DeleteTrackService", req.Context(), model.TrackID(9)).Return(repository.ErrorTrackNotFound)
+	suite.trackController.DeleteTrack(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *TrackControllerSuite) TestDeleteTrack_TrashedAlbum() {
+	req := httptest.NewRequest(http.MethodDelete, "/tracks/3", nil)
+	req.SetPathValue("id", "3")
+	rr := httptest.NewRecorder()
+
+	suite.mockTrackService.On("DeleteTrackService", req.Context(), model.TrackID(3)).Return(repository.ErrorAlbumNotFound)
+	suite.trackController.DeleteTrack(rr, req)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"net/http"
+)
+
+type TrashController interface {
+	GetTrashHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type trashController struct {
+	service service.TrashService
+}
+
+var _ TrashController = (*trashController)(nil)
+
+func NewTrashController(s service.TrashService) TrashController {
+	return &trashController{service: s}
+}
+
+// GetTrashHandler GET /trash
+func (c *trashController) GetTrashHandler(w http.ResponseWriter, r *http.Request) {
+	trash, err := c.service.GetTrashService(r.Context())
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	res := dto.NewTrashResponse(trash)
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "failed to encode response", "error", err)
+		return
+	}
+}
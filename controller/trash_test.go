@@ -0,0 +1,79 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/controller"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type MockTrashService struct {
+	mock.Mock
+}
+
+func (m *MockTrashService) GetTrashService(ctx context.Context) (*model.Trash, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Trash), args.Error(1)
+}
+
+func (m *MockTrashService) PurgeTrashService(ctx context.Context, deletedBefore time.Time) error {
+	args := m.Called(ctx, deletedBefore)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+type TrashControllerSuite struct {
+	suite.Suite
+	trashController  controller.TrashController
+	mockTrashService *MockTrashService
+}
+
+func TestTrashControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashControllerSuite))
+}
+
+func (suite *TrashControllerSuite) SetupTest() {
+	suite.mockTrashService = &MockTrashService{}
+	suite.trashController = controller.NewTrashController(suite.mockTrashService)
+}
+
+func (suite *TrashControllerSuite) TestGetTrashHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	rr := httptest.NewRecorder()
+
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	trash := &model.Trash{
+		Singers: []*model.Singer{{ID: model.SingerID(5), Name: "Ellen", DeletedAt: &deletedAt}},
+		Albums: []*model.Album{{
+			ID:        model.AlbumID(3),
+			Title:     "Bella's 1st Album",
+			Singer:    &model.Singer{ID: model.SingerID(2), Name: "Bella"},
+			DeletedAt: &deletedAt,
+		}},
+	}
+	suite.mockTrashService.On("GetTrashService", req.Context()).Return(trash, nil)
+	suite.trashController.GetTrashHandler(rr, req)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var res dto.TrashResponse
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	suite.NoError(err)
+	suite.Len(res.Singers, 1)
+	suite.Equal("Ellen", res.Singers[0].Name)
+	suite.Len(res.Albums, 1)
+	suite.Equal("Bella", res.Albums[0].Singer.Name)
+	suite.Equal(deletedAt, res.Albums[0].DeletedAt)
+}
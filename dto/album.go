@@ -3,17 +3,20 @@ package dto
 import "github.com/pulse227/server-recruit-challenge-sample/model"
 
 type CreateAlbumRequest struct {
-	ID       int    `json:"id"`
+	ID       *int   `json:"id,omitempty"`
 	Title    string `json:"title"`
 	SingerID int    `json:"singer_id"`
 }
 
 func (r *CreateAlbumRequest) ToModel() *model.Album {
-	return &model.Album{
-		ID:       model.AlbumID(r.ID),
+	album := &model.Album{
 		Title:    r.Title,
 		SingerID: model.SingerID(r.SingerID),
 	}
+	if r.ID != nil {
+		album.ID = model.AlbumID(*r.ID)
+	}
+	return album
 }
 
 type CreateAlbumResponse struct {
@@ -30,6 +33,40 @@ func NewCreateAlbumResponse(album *model.Album) *CreateAlbumResponse {
 	}
 }
 
+type UpdateAlbumRequest struct {
+	Title    string `json:"title"`
+	SingerID int    `json:"singer_id"`
+}
+
+func NewUpdateAlbumRequest(album *model.Album) *UpdateAlbumRequest {
+	return &UpdateAlbumRequest{
+		Title:    album.Title,
+		SingerID: int(album.SingerID),
+	}
+}
+
+func (r *UpdateAlbumRequest) ToModel(id int) *model.Album {
+	return &model.Album{
+		ID:       model.AlbumID(id),
+		Title:    r.Title,
+		SingerID: model.SingerID(r.SingerID),
+	}
+}
+
+type UpdateAlbumResponse struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	SingerID int    `json:"singer_id"`
+}
+
+func NewUpdateAlbumResponse(album *model.Album) *UpdateAlbumResponse {
+	return &UpdateAlbumResponse{
+		ID:       int(album.ID),
+		Title:    album.Title,
+		SingerID: int(album.SingerID),
+	}
+}
+
 type GetAlbumRequest struct {
 	ID int `json:"id"`
 }
@@ -49,13 +86,15 @@ func (r *DeleteAlbumRequest) ToModel() *model.AlbumID {
 }
 
 type AlbumResponse struct {
-	ID     int            `json:"id"`
-	Title  string         `json:"title"`
-	Singer SingerResponse `json:"singer"`
+	ID                   int              `json:"id"`
+	Title                string           `json:"title"`
+	Singer               SingerResponse   `json:"singer"`
+	Tracks               []*TrackResponse `json:"tracks,omitempty"`
+	TotalDurationSeconds *int             `json:"total_duration_seconds,omitempty"`
 }
 
 func NewAlbumResponse(album *model.Album) *AlbumResponse {
-	return &AlbumResponse{
+	res := &AlbumResponse{
 		ID:    int(album.ID),
 		Title: album.Title,
 		Singer: SingerResponse{
@@ -63,6 +102,13 @@ func NewAlbumResponse(album *model.Album) *AlbumResponse {
 			Name: album.Singer.Name,
 		},
 	}
+	// Tracks are only loaded on request; a nil slice means they were not asked for.
+	if album.Tracks != nil {
+		res.Tracks = NewTracksResponse(album.Tracks)
+		total := album.TotalDuration()
+		res.TotalDurationSeconds = &total
+	}
+	return res
 }
 
 func NewAlbumsResponse(albums []*model.Album) []*AlbumResponse {
@@ -72,3 +118,15 @@ func NewAlbumsResponse(albums []*model.Album) []*AlbumResponse {
 	}
 	return responses
 }
+
+type AlbumListResponse struct {
+	Items      []*AlbumResponse `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+func NewAlbumListResponse(page *model.Page[*model.Album]) *AlbumListResponse {
+	return &AlbumListResponse{
+		Items:      NewAlbumsResponse(page.Items),
+		NextCursor: encodeCursor(page.NextCursor),
+	}
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// NewAuditQuery builds an audit log query from URL query parameters.
+func NewAuditQuery(values url.Values) (*model.AuditQuery, error) {
+	v := &model.ValidationError{}
+	q := &model.AuditQuery{
+		Limit:      parseLimit(v, values),
+		Cursor:     parseCursor(v, values),
+		EntityType: model.AuditEntityType(values.Get("entity")),
+		Actor:      values.Get("actor"),
+	}
+	if raw := values.Get("id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			v.Add("id", model.CodeInvalidType, "id must be an integer")
+		}
+		q.EntityID = id
+	}
+	return q, v.Err()
+}
+
+type AuditEventResponse struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int             `json:"entity_id"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditEventListResponse struct {
+	Items      []*AuditEventResponse `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+func NewAuditEventListResponse(page *model.Page[*model.AuditEvent]) *AuditEventListResponse {
+	res := &AuditEventListResponse{
+		Items:      make([]*AuditEventResponse, len(page.Items)),
+		NextCursor: encodeCursor(page.NextCursor),
+	}
+	for i, event := range page.Items {
+		res.Items[i] = &AuditEventResponse{
+			ID:         event.ID,
+			Actor:      event.Actor,
+			Action:     string(event.Action),
+			EntityType: string(event.EntityType),
+			EntityID:   event.EntityID,
+			RequestID:  event.RequestID,
+			Before:     event.Before,
+			After:      event.After,
+			CreatedAt:  event.CreatedAt,
+		}
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// BatchRequest is the body of POST /singers:batch and POST /albums:batch.
+// Mode is "atomic" (the default) or "best_effort".
+type BatchRequest[T any] struct {
+	Mode       string `json:"mode,omitempty"`
+	Operations []T    `json:"operations"`
+}
+
+// BatchOperationHeader holds the fields every batch operation has. Version plays the part
+// of If-Match for updates and deletes.
+type BatchOperationHeader struct {
+	Op      string `json:"op"`
+	ID      *int   `json:"id,omitempty"`
+	Version int64  `json:"version,omitempty"`
+}
+
+func (h BatchOperationHeader) Header() BatchOperationHeader {
+	return h
+}
+
+func (h BatchOperationHeader) id() int {
+	if h.ID == nil {
+		return 0
+	}
+	return *h.ID
+}
+
+type SingerOperationRequest struct {
+	BatchOperationHeader
+	Name string `json:"name,omitempty"`
+}
+
+func (r SingerOperationRequest) ToModel() model.BatchOperation[*model.Singer] {
+	return model.BatchOperation[*model.Singer]{
+		Action: model.BatchAction(r.Op),
+		Item:   &model.Singer{ID: model.SingerID(r.id()), Name: r.Name, Version: r.Version},
+	}
+}
+
+type AlbumOperationRequest struct {
+	BatchOperationHeader
+	Title    string `json:"title,omitempty"`
+	SingerID int    `json:"singer_id,omitempty"`
+}
+
+func (r AlbumOperationRequest) ToModel() model.BatchOperation[*model.Album] {
+	return model.BatchOperation[*model.Album]{
+		Action: model.BatchAction(r.Op),
+		Item: &model.Album{
+			ID:       model.AlbumID(r.id()),
+			Title:    r.Title,
+			SingerID: model.SingerID(r.SingerID),
+			Version:  r.Version,
+		},
+	}
+}
+
+type BatchResponse struct {
+	Results []*BatchResultResponse `json:"results"`
+}
+
+// BatchResultResponse reports one operation with the status it would have had as a single request.
+type BatchResultResponse struct {
+	Index  int                   `json:"index"`
+	Op     string                `json:"op"`
+	Status int                   `json:"status"`
+	ID     int                   `json:"id,omitempty"`
+	Detail string                `json:"detail,omitempty"`
+	Errors []*BatchFieldResponse `json:"errors,omitempty"`
+}
+
+type BatchFieldResponse struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func NewBatchResultResponse(result *model.BatchResult, status int, detail string) *BatchResultResponse {
+	res := &BatchResultResponse{
+		Index:  result.Index,
+		Op:     string(result.Action),
+		Status: status,
+		ID:     result.ID,
+		Detail: detail,
+	}
+	var validationErr *model.ValidationError
+	if errors.As(result.Err, &validationErr) {
+		for _, f := range validationErr.Fields {
+			res.Errors = append(res.Errors, &BatchFieldResponse{Field: f.Field, Code: f.Code, Message: f.Message})
+		}
+	}
+	return res
+}
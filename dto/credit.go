@@ -0,0 +1,81 @@
+package dto
+
+import "github.com/pulse227/server-recruit-challenge-sample/model"
+
+type CreditRequest struct {
+	SingerID int    `json:"singer_id"`
+	Role     string `json:"role"`
+}
+
+type PutCreditsRequest struct {
+	Credits []CreditRequest `json:"credits"`
+}
+
+func (r *PutCreditsRequest) ToModel() []*model.Credit {
+	credits := make([]*model.Credit, len(r.Credits))
+	for i, c := range r.Credits {
+		credits[i] = &model.Credit{
+			SingerID: model.SingerID(c.SingerID),
+			Role:     model.CreditRole(c.Role),
+		}
+	}
+	return credits
+}
+
+type CreditResponse struct {
+	Singer   SingerResponse `json:"singer"`
+	Role     string         `json:"role"`
+	Position int            `json:"position"`
+}
+
+func NewCreditResponse(credit *model.Credit) *CreditResponse {
+	res := &CreditResponse{
+		Singer:   SingerResponse{ID: int(credit.SingerID)},
+		Role:     string(credit.Role),
+		Position: credit.Position,
+	}
+	if credit.Singer != nil {
+		res.Singer.Name = credit.Singer.Name
+	}
+	return res
+}
+
+type CreditListResponse struct {
+	Items []*CreditResponse `json:"items"`
+}
+
+func NewCreditListResponse(credits []*model.Credit) *CreditListResponse {
+	items := make([]*CreditResponse, len(credits))
+	for i, credit := range credits {
+		items[i] = NewCreditResponse(credit)
+	}
+	return &CreditListResponse{Items: items}
+}
+
+type SingerAlbumResponse struct {
+	AlbumResponse
+	Roles []string `json:"roles"`
+}
+
+func NewSingerAlbumResponse(album *model.SingerAlbum) *SingerAlbumResponse {
+	roles := make([]string, len(album.Roles))
+	for i, role := range album.Roles {
+		roles[i] = string(role)
+	}
+	return &SingerAlbumResponse{
+		AlbumResponse: *NewAlbumResponse(album.Album),
+		Roles:         roles,
+	}
+}
+
+type SingerAlbumListResponse struct {
+	Items []*SingerAlbumResponse `json:"items"`
+}
+
+func NewSingerAlbumListResponse(albums []*model.SingerAlbum) *SingerAlbumListResponse {
+	items := make([]*SingerAlbumResponse, len(albums))
+	for i, album := range albums {
+		items[i] = NewSingerAlbumResponse(album)
+	}
+	return &SingerAlbumListResponse{Items: items}
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// SingerExportHeader names the CSV columns of SingerExportRow.CSVRecord.
+var SingerExportHeader = []string{"id", "name", "created_at"}
+
+type SingerExportRow struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewSingerExportRow(singer *model.Singer) *SingerExportRow {
+	return &SingerExportRow{
+		ID:        int(singer.ID),
+		Name:      singer.Name,
+		CreatedAt: singer.CreatedAt,
+	}
+}
+
+func (r *SingerExportRow) CSVRecord() []string {
+	return []string{strconv.Itoa(r.ID), r.Name, r.CreatedAt.Format(time.RFC3339)}
+}
+
+// AlbumExportHeader names the CSV columns of AlbumExportRow.CSVRecord.
+var AlbumExportHeader = []string{"id", "title", "singer_id", "singer_name", "created_at"}
+
+type AlbumExportRow struct {
+	ID         int       `json:"id"`
+	Title      string    `json:"title"`
+	SingerID   int       `json:"singer_id"`
+	SingerName string    `json:"singer_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewAlbumExportRow(album *model.Album) *AlbumExportRow {
+	return &AlbumExportRow{
+		ID:         int(album.ID),
+		Title:      album.Title,
+		SingerID:   int(album.SingerID),
+		SingerName: album.Singer.Name,
+		CreatedAt:  album.CreatedAt,
+	}
+}
+
+func (r *AlbumExportRow) CSVRecord() []string {
+	return []string{strconv.Itoa(r.ID), r.Title, strconv.Itoa(r.SingerID), r.SingerName, r.CreatedAt.Format(time.RFC3339)}
+}
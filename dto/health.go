@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/pulse227/server-recruit-challenge-sample/model"
+
+type HealthCheckResponse struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks []*HealthCheckResponse `json:"checks"`
+}
+
+func NewHealthResponse(report *model.HealthReport) *HealthResponse {
+	res := &HealthResponse{
+		Status: string(report.Status),
+		Checks: make([]*HealthCheckResponse, len(report.Checks)),
+	}
+	for i, check := range report.Checks {
+		res.Checks[i] = &HealthCheckResponse{
+			Name:      check.Name,
+			Status:    string(check.Status),
+			LatencyMs: float64(check.Latency.Microseconds()) / 1000,
+			Error:     check.Error,
+		}
+	}
+	return res
+}
@@ -0,0 +1,200 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type ImportRequest struct {
+	DryRun bool
+}
+
+func NewImportRequest(values url.Values) (*ImportRequest, error) {
+	v := &model.ValidationError{}
+	req := &ImportRequest{DryRun: parseBool(v, values, "dry_run")}
+	return req, v.Err()
+}
+
+// importColumns are the columns of a CSV import. Only type is required in the header.
+var importColumns = []string{"type", "name", "title", "singer_id", "singer_name"}
+
+// importRecord is one line of an NDJSON import, or one CSV record keyed by column.
+type importRecord struct {
+	Type       string `json:"type"`
+	Name       string `json:"name,omitempty"`
+	Title      string `json:"title,omitempty"`
+	SingerID   int    `json:"singer_id,omitempty"`
+	SingerName string `json:"singer_name,omitempty"`
+}
+
+func (r *importRecord) toModel(line int) *model.ImportRow {
+	return &model.ImportRow{
+		Line:       line,
+		Type:       model.ImportRowType(r.Type),
+		Name:       r.Name,
+		Title:      r.Title,
+		SingerID:   model.SingerID(r.SingerID),
+		SingerName: r.SingerName,
+	}
+}
+
+// DecodeImportCSV reads a CSV import whose first record names the columns. Records that
+// cannot be decoded are reported in the file's Errors; a malformed header fails the file.
+func DecodeImportCSV(body io.Reader) (*model.ImportFile, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid body param: import file is empty")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid body param: %w", err)
+	}
+
+	v := &model.ValidationError{}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(importColumns, name):
+			v.Add(name, model.CodeUnknownField, "unknown column "+strconv.Quote(name))
+		case columns[name] != 0:
+			v.Add(name, model.CodeDuplicate, "column "+strconv.Quote(name)+" appears twice")
+		}
+		columns[name] = i + 1
+	}
+	if columns["type"] == 0 {
+		v.Add("type", model.CodeRequired, "type column is required")
+	}
+	if err = v.Err(); err != nil {
+		return nil, fmt.Errorf("invalid body param: %w", err)
+	}
+
+	file := &model.ImportFile{Rows: make([]*model.ImportRow, 0)}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			file.Errors = append(file.Errors, importRowError(parseErr.StartLine, "", model.CodeFormat,
+				fmt.Sprintf("record has %d fields, the header has %d", len(record), len(header))))
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid body param: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(column string) string {
+			if i := columns[column]; i != 0 {
+				return strings.TrimSpace(record[i-1])
+			}
+			return ""
+		}
+		r := importRecord{Type: value("type"), Name: value("name"), Title: value("title"), SingerName: value("singer_name")}
+		if raw := value("singer_id"); raw != "" {
+			if r.SingerID, err = strconv.Atoi(raw); err != nil {
+				file.Errors = append(file.Errors, importRowError(line, "singer_id", model.CodeInvalidType, "singer_id must be an integer"))
+				continue
+			}
+		}
+		file.Rows = append(file.Rows, r.toModel(line))
+	}
+	return file, nil
+}
+
+// maxImportLine bounds one NDJSON record.
+const maxImportLine = 1 << 20
+
+// DecodeImportNDJSON reads an import with one JSON object per line. Blank lines are skipped.
+func DecodeImportNDJSON(body io.Reader) (*model.ImportFile, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+
+	file := &model.ImportFile{Rows: make([]*model.ImportRow, 0)}
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		r := importRecord{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&r); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				file.Errors = append(file.Errors, importRowError(line, typeErr.Field, model.CodeInvalidType,
+					fmt.Sprintf("%s must be %s", typeErr.Field, typeErr.Type)))
+			} else {
+				file.Errors = append(file.Errors, importRowError(line, "", model.CodeFormat, err.Error()))
+			}
+			continue
+		}
+		file.Rows = append(file.Rows, r.toModel(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid body param: %w", err)
+	}
+	return file, nil
+}
+
+func importRowError(line int, field, code, message string) model.ImportRowError {
+	return model.ImportRowError{Line: line, FieldError: model.FieldError{Field: field, Code: code, Message: message}}
+}
+
+type ImportJobResponse struct {
+	ID             string                    `json:"id"`
+	Status         string                    `json:"status"`
+	DryRun         bool                      `json:"dry_run"`
+	TotalRows      int                       `json:"total_rows"`
+	ProcessedRows  int                       `json:"processed_rows"`
+	SingersCreated int                       `json:"singers_created"`
+	AlbumsCreated  int                       `json:"albums_created"`
+	Unchanged      int                       `json:"unchanged"`
+	Errors         []*ImportRowErrorResponse `json:"errors"`
+	Failure        string                    `json:"failure,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	FinishedAt     *time.Time                `json:"finished_at,omitempty"`
+}
+
+type ImportRowErrorResponse struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func NewImportJobResponse(job *model.ImportJob) *ImportJobResponse {
+	res := &ImportJobResponse{
+		ID:             job.ID,
+		Status:         string(job.Status),
+		DryRun:         job.DryRun,
+		TotalRows:      job.TotalRows,
+		ProcessedRows:  job.ProcessedRows,
+		SingersCreated: job.SingersCreated,
+		AlbumsCreated:  job.AlbumsCreated,
+		Unchanged:      job.Unchanged,
+		Errors:         make([]*ImportRowErrorResponse, len(job.Errors)),
+		Failure:        job.Failure,
+		CreatedAt:      job.CreatedAt,
+		FinishedAt:     job.FinishedAt,
+	}
+	for i, e := range job.Errors {
+		res.Errors[i] = &ImportRowErrorResponse{Line: e.Line, Field: e.Field, Code: e.Code, Message: e.Message}
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package dto_test
+
+import (
+	"errors"
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDecodeImportCSV(t *testing.T) {
+	body := "type,name,title,singer_id,singer_name\n" +
+		"singer,Alice,,,\n" +
+		"album,,Debut,,Alice\n" +
+		"album,,Second,one,\n" +
+		"album,,Third\n" +
+		"album,,\"Fourth, Live\",2,\n"
+
+	file, err := dto.DecodeImportCSV(strings.NewReader(body))
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.ImportRow{
+		{Line: 2, Type: model.ImportSinger, Name: "Alice"},
+		{Line: 3, Type: model.ImportAlbum, Title: "Debut", SingerName: "Alice"},
+		{Line: 6, Type: model.ImportAlbum, Title: "Fourth, Live", SingerID: 2},
+	}, file.Rows)
+	assert.Equal(t, []model.ImportRowError{
+		{Line: 4, FieldError: model.FieldError{Field: "singer_id", Code: model.CodeInvalidType, Message: "singer_id must be an integer"}},
+		{Line: 5, FieldError: model.FieldError{Code: model.CodeFormat, Message: "record has 3 fields, the header has 5"}},
+	}, file.Errors)
+}
+
+func TestDecodeImportCSV_InvalidHeader(t *testing.T) {
+	_, err := dto.DecodeImportCSV(strings.NewReader("name,year\nAlice,2001\n"))
+
+	var validationErr *model.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []model.FieldError{
+		{Field: "year", Code: model.CodeUnknownField, Message: `unknown column "year"`},
+		{Field: "type", Code: model.CodeRequired, Message: "type column is required"},
+	}, validationErr.Fields)
+
+	_, err = dto.DecodeImportCSV(strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestDecodeImportNDJSON(t *testing.T) {
+	body := `{"type":"singer","name":"Alice"}` + "\n" +
+		"\n" +
+		`{"type":"album","title":"Debut","singer_id":1}` + "\n" +
+		`{"type":"album","title":"Second","singer_id":"one"}` + "\n" +
+		`{"type":"album","title":"Third","year":2001}` + "\n" +
+		`not json`
+
+	file, err := dto.DecodeImportNDJSON(strings.NewReader(body))
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.ImportRow{
+		{Line: 1, Type: model.ImportSinger, Name: "Alice"},
+		{Line: 3, Type: model.ImportAlbum, Title: "Debut", SingerID: 1},
+	}, file.Rows)
+	if assert.Len(t, file.Errors, 3) {
+		assert.Equal(t, 4, file.Errors[0].Line)
+		assert.Equal(t, "singer_id", file.Errors[0].Field)
+		assert.Equal(t, model.CodeInvalidType, file.Errors[0].Code)
+		assert.Equal(t, 5, file.Errors[1].Line)
+		assert.Equal(t, model.CodeFormat, file.Errors[1].Code)
+		assert.Equal(t, 6, file.Errors[2].Line)
+	}
+}
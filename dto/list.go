@@ -0,0 +1,176 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// NewSingerQuery builds a singer listing query from URL query parameters.
+func NewSingerQuery(values url.Values) (*model.SingerQuery, error) {
+	v := &model.ValidationError{}
+	q := &model.SingerQuery{
+		Limit:        parseLimit(v, values),
+		Cursor:       parseCursor(v, values),
+		Sort:         model.SingerSortField(valueOr(values.Get("sort"), string(model.SingerSortID))),
+		Desc:         parseOrder(v, values),
+		NamePrefix:   values.Get("name_prefix"),
+		CreatedAfter: parseTime(v, values, "created_after"),
+	}
+	return q, v.Err()
+}
+
+// NewAlbumQuery builds an album listing query from URL query parameters.
+func NewAlbumQuery(values url.Values) (*model.AlbumQuery, error) {
+	v := &model.ValidationError{}
+	q := &model.AlbumQuery{
+		Limit:        parseLimit(v, values),
+		Cursor:       parseCursor(v, values),
+		Sort:         model.AlbumSortField(valueOr(values.Get("sort"), string(model.AlbumSortID))),
+		Desc:         parseOrder(v, values),
+		TitlePrefix:  values.Get("title_prefix"),
+		CreatedAfter: parseTime(v, values, "created_after"),
+		SingerID:     parseSingerID(v, values),
+	}
+	return q, v.Err()
+}
+
+// NewSingerExportQuery builds the query of a singer export. It takes the listing's filters
+// and ordering; an export has no pages, so limit and cursor do not apply.
+func NewSingerExportQuery(values url.Values) (*model.SingerQuery, error) {
+	v := &model.ValidationError{}
+	q := &model.SingerQuery{
+		Sort:         model.SingerSortField(valueOr(values.Get("sort"), string(model.SingerSortID))),
+		Desc:         parseOrder(v, values),
+		NamePrefix:   values.Get("name_prefix"),
+		CreatedAfter: parseTime(v, values, "created_after"),
+	}
+	return q, v.Err()
+}
+
+// NewAlbumExportQuery is NewSingerExportQuery for albums.
+func NewAlbumExportQuery(values url.Values) (*model.AlbumQuery, error) {
+	v := &model.ValidationError{}
+	q := &model.AlbumQuery{
+		Sort:         model.AlbumSortField(valueOr(values.Get("sort"), string(model.AlbumSortID))),
+		Desc:         parseOrder(v, values),
+		TitlePrefix:  values.Get("title_prefix"),
+		CreatedAfter: parseTime(v, values, "created_after"),
+		SingerID:     parseSingerID(v, values),
+	}
+	return q, v.Err()
+}
+
+func parseSingerID(v *model.ValidationError, values url.Values) *model.SingerID {
+	raw := values.Get("singer_id")
+	if raw == "" {
+		return nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		v.Add("singer_id", model.CodeInvalidType, "singer_id must be an integer")
+		return nil
+	}
+	singerID := model.SingerID(id)
+	return &singerID
+}
+
+func parseLimit(v *model.ValidationError, values url.Values) int {
+	raw := values.Get("limit")
+	if raw == "" {
+		return model.DefaultPageLimit
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil {
+		v.Add("limit", model.CodeInvalidType, "limit must be an integer")
+	}
+	return limit
+}
+
+func parseCursor(v *model.ValidationError, values url.Values) *model.Cursor {
+	raw := values.Get("cursor")
+	if raw == "" {
+		return nil
+	}
+	cursor, err := model.DecodeCursor(raw)
+	if err != nil {
+		v.Add("cursor", model.CodeInvalid, "cursor is malformed")
+	}
+	return cursor
+}
+
+func parseOrder(v *model.ValidationError, values url.Values) bool {
+	switch values.Get("order") {
+	case "", "asc":
+		return false
+	case "desc":
+		return true
+	}
+	v.Add("order", model.CodeInvalid, "order must be asc or desc")
+	return false
+}
+
+func parseTime(v *model.ValidationError, values url.Values, key string) *time.Time {
+	raw := values.Get(key)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		v.Add(key, model.CodeInvalidType, key+" must be an RFC 3339 timestamp")
+		return nil
+	}
+	return &t
+}
+
+func parseBool(v *model.ValidationError, values url.Values, key string) bool {
+	raw := values.Get(key)
+	if raw == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		v.Add(key, model.CodeInvalidType, key+" must be true or false")
+		return false
+	}
+	return b
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func encodeCursor(cursor *model.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return cursor.Encode()
+}
+
+// ParseInclude reads the comma separated include parameter, rejecting names outside allowed.
+func ParseInclude(values url.Values, allowed ...string) (map[string]bool, error) {
+	v := &model.ValidationError{}
+	include := make(map[string]bool)
+	for _, raw := range values["include"] {
+		for _, name := range strings.Split(raw, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(allowed, name) {
+				v.Add("include", model.CodeInvalid, fmt.Sprintf("include must be one of %s", strings.Join(allowed, ", ")))
+				continue
+			}
+			include[name] = true
+		}
+	}
+	return include, v.Err()
+}
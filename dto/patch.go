@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to the target document.
+func ApplyMergePatch(target, patch []byte) ([]byte, error) {
+	var targetDoc any
+	if err := unmarshalJSON(target, &targetDoc); err != nil {
+		return nil, err
+	}
+
+	var patchDoc any
+	if err := unmarshalJSON(patch, &patchDoc); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(targetDoc, patchDoc))
+}
+
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+func unmarshalJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
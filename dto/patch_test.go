@@ -0,0 +1,35 @@
+package dto_test
+
+import (
+	"github.com/pulse227/server-recruit-challenge-sample/dto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{name: "replace value", target: `{"title":"a","singer_id":1}`, patch: `{"title":"b"}`, want: `{"title":"b","singer_id":1}`},
+		{name: "remove value", target: `{"title":"a","singer_id":1}`, patch: `{"title":null}`, want: `{"singer_id":1}`},
+		{name: "add value", target: `{"title":"a"}`, patch: `{"singer_id":2}`, want: `{"title":"a","singer_id":2}`},
+		{name: "nested object", target: `{"a":{"b":1,"c":2}}`, patch: `{"a":{"c":null,"d":3}}`, want: `{"a":{"b":1,"d":3}}`},
+		{name: "non object patch", target: `{"title":"a"}`, patch: `["x"]`, want: `["x"]`},
+		{name: "empty patch", target: `{"title":"a"}`, patch: `{}`, want: `{"title":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dto.ApplyMergePatch([]byte(tt.target), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestApplyMergePatch_InvalidPatch(t *testing.T) {
+	_, err := dto.ApplyMergePatch([]byte(`{"title":"a"}`), []byte(`{"title":`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// NewSearchQuery builds a search query from URL query parameters.
+// type may be repeated or comma separated to restrict the result types.
+func NewSearchQuery(values url.Values) (*model.SearchQuery, error) {
+	v := &model.ValidationError{}
+	q := &model.SearchQuery{
+		Query:  values.Get("q"),
+		Limit:  parseLimit(v, values),
+		Cursor: parseCursor(v, values),
+	}
+	for _, raw := range values["type"] {
+		for _, t := range strings.Split(raw, ",") {
+			if t != "" {
+				q.Types = append(q.Types, model.SearchResultType(t))
+			}
+		}
+	}
+	return q, v.Err()
+}
+
+type SearchResultResponse struct {
+	Type      string          `json:"type"`
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Highlight string          `json:"highlight"`
+	Score     float64         `json:"score"`
+	Singer    *SingerResponse `json:"singer,omitempty"`
+}
+
+func NewSearchResultResponse(result *model.SearchResult) *SearchResultResponse {
+	res := &SearchResultResponse{
+		Type:      string(result.Type),
+		ID:        result.ID,
+		Name:      result.Name,
+		Highlight: result.Highlight,
+		Score:     result.Score,
+	}
+	if result.Singer != nil {
+		res.Singer = NewSingerResponse(result.Singer)
+	}
+	return res
+}
+
+type SearchResponse struct {
+	Items      []*SearchResultResponse `json:"items"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+func NewSearchResponse(page *model.Page[*model.SearchResult]) *SearchResponse {
+	items := make([]*SearchResultResponse, len(page.Items))
+	for i, result := range page.Items {
+		items[i] = NewSearchResultResponse(result)
+	}
+	return &SearchResponse{
+		Items:      items,
+		NextCursor: encodeCursor(page.NextCursor),
+	}
+}
@@ -1,6 +1,11 @@
 package dto
 
-import "github.com/pulse227/server-recruit-challenge-sample/model"
+import (
+	"net/url"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
 
 type SingerResponse struct {
 	ID   int    `json:"id"`
@@ -22,6 +27,18 @@ func NewSingersResponse(singers []*model.Singer) []*SingerResponse {
 	return res
 }
 
+type SingerListResponse struct {
+	Items      []*SingerResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func NewSingerListResponse(page *model.Page[*model.Singer]) *SingerListResponse {
+	return &SingerListResponse{
+		Items:      NewSingersResponse(page.Items),
+		NextCursor: encodeCursor(page.NextCursor),
+	}
+}
+
 type GetSingerRequest struct {
 	ID int `json:"id"`
 }
@@ -32,22 +49,107 @@ func (r *GetSingerRequest) ToModel() *model.SingerID {
 }
 
 type CreateSingerRequest struct {
-	ID   int    `json:"id"`
+	ID   *int   `json:"id,omitempty"`
 	Name string `json:"name"`
 }
 
 func (r *CreateSingerRequest) ToModel() *model.Singer {
+	singer := &model.Singer{
+		Name: r.Name,
+	}
+	if r.ID != nil {
+		singer.ID = model.SingerID(*r.ID)
+	}
+	return singer
+}
+
+type UpdateSingerRequest struct {
+	Name string `json:"name"`
+}
+
+func NewUpdateSingerRequest(singer *model.Singer) *UpdateSingerRequest {
+	return &UpdateSingerRequest{
+		Name: singer.Name,
+	}
+}
+
+func (r *UpdateSingerRequest) ToModel(id int) *model.Singer {
 	return &model.Singer{
-		ID:   model.SingerID(r.ID),
+		ID:   model.SingerID(id),
 		Name: r.Name,
 	}
 }
 
 type DeleteSingerRequest struct {
-	ID int `json:"id"`
+	ID      int  `json:"id"`
+	Cascade bool `json:"cascade"`
+	DryRun  bool `json:"dry_run"`
+}
+
+// NewDeleteSingerRequest reads the cascade and dry_run query parameters of a singer delete.
+func NewDeleteSingerRequest(id int, values url.Values) (*DeleteSingerRequest, error) {
+	v := &model.ValidationError{}
+	req := &DeleteSingerRequest{
+		ID:      id,
+		Cascade: parseBool(v, values, "cascade"),
+		DryRun:  parseBool(v, values, "dry_run"),
+	}
+	if req.DryRun && !req.Cascade {
+		v.Add("dry_run", model.CodeInvalid, "dry_run requires cascade=true")
+	}
+	return req, v.Err()
 }
 
 func (r *DeleteSingerRequest) ToModel() *model.SingerID {
 	id := model.SingerID(r.ID)
 	return &id
 }
+
+type SingerStatsResponse struct {
+	AlbumCount    int        `json:"album_count"`
+	FirstAddedAt  *time.Time `json:"first_added_at"`
+	LatestAddedAt *time.Time `json:"latest_added_at"`
+}
+
+type SingerDetailResponse struct {
+	SingerResponse
+	Albums []*SingerAlbumResponse `json:"albums,omitempty"`
+	Stats  *SingerStatsResponse   `json:"stats,omitempty"`
+}
+
+// NewSingerDetailResponse embeds the parts of the discography named in include.
+func NewSingerDetailResponse(singer *model.Singer, discography *model.Discography, include map[string]bool) *SingerDetailResponse {
+	res := &SingerDetailResponse{SingerResponse: *NewSingerResponse(singer)}
+	if discography == nil {
+		return res
+	}
+	if include["albums"] {
+		res.Albums = NewSingerAlbumListResponse(discography.Albums).Items
+	}
+	if include["stats"] {
+		res.Stats = &SingerStatsResponse{
+			AlbumCount:    discography.AlbumCount,
+			FirstAddedAt:  discography.FirstAddedAt,
+			LatestAddedAt: discography.LatestAddedAt,
+		}
+	}
+	return res
+}
+
+type SingerDeletionResponse struct {
+	SingerID        int   `json:"singer_id"`
+	DeletedAlbumIDs []int `json:"deleted_album_ids"`
+	DryRun          bool  `json:"dry_run"`
+}
+
+func NewSingerDeletionResponse(deletion *model.SingerDeletion) *SingerDeletionResponse {
+	ids := make([]int, 0, len(deletion.AlbumIDs))
+	for _, id := range deletion.AlbumIDs {
+		ids = append(ids, int(id))
+	}
+	return &SingerDeletionResponse{
+		SingerID:        int(deletion.SingerID),
+		DeletedAlbumIDs: ids,
+		DryRun:          deletion.DryRun,
+	}
+}
@@ -0,0 +1,141 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type CreateTrackRequest struct {
+	DiscNumber      *int   `json:"disc_number,omitempty"`
+	TrackNumber     int    `json:"track_number"`
+	Title           string `json:"title"`
+	DurationSeconds int    `json:"duration_seconds"`
+	ISRC            string `json:"isrc"`
+	Explicit        bool   `json:"explicit"`
+}
+
+func (r *CreateTrackRequest) ToModel(albumID int) *model.Track {
+	discNumber := 1
+	if r.DiscNumber != nil {
+		discNumber = *r.DiscNumber
+	}
+	return &model.Track{
+		AlbumID:         model.AlbumID(albumID),
+		DiscNumber:      discNumber,
+		TrackNumber:     r.TrackNumber,
+		Title:           r.Title,
+		DurationSeconds: r.DurationSeconds,
+		ISRC:            normalizeISRC(r.ISRC),
+		Explicit:        r.Explicit,
+	}
+}
+
+type UpdateTrackRequest struct {
+	DiscNumber      int    `json:"disc_number"`
+	TrackNumber     int    `json:"track_number"`
+	Title           string `json:"title"`
+	DurationSeconds int    `json:"duration_seconds"`
+	ISRC            string `json:"isrc"`
+	Explicit        bool   `json:"explicit"`
+}
+
+func NewUpdateTrackRequest(track *model.Track) *UpdateTrackRequest {
+	return &UpdateTrackRequest{
+		DiscNumber:      track.DiscNumber,
+		TrackNumber:     track.TrackNumber,
+		Title:           track.Title,
+		DurationSeconds: track.DurationSeconds,
+		ISRC:            track.ISRC,
+		Explicit:        track.Explicit,
+	}
+}
+
+func (r *UpdateTrackRequest) ToModel(id int, albumID model.AlbumID) *model.Track {
+	return &model.Track{
+		ID:              model.TrackID(id),
+		AlbumID:         albumID,
+		DiscNumber:      r.DiscNumber,
+		TrackNumber:     r.TrackNumber,
+		Title:           r.Title,
+		DurationSeconds: r.DurationSeconds,
+		ISRC:            normalizeISRC(r.ISRC),
+		Explicit:        r.Explicit,
+	}
+}
+
+// normalizeISRC accepts the hyphenated display form (JP-ABC-24-00001) as well as the compact one.
+func normalizeISRC(isrc string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(isrc), "-", ""))
+}
+
+type TrackPositionRequest struct {
+	ID          int  `json:"id"`
+	DiscNumber  *int `json:"disc_number,omitempty"`
+	TrackNumber int  `json:"track_number"`
+}
+
+type ReorderTracksRequest struct {
+	Tracks []TrackPositionRequest `json:"tracks"`
+}
+
+func (r *ReorderTracksRequest) ToModel() []model.TrackPosition {
+	positions := make([]model.TrackPosition, len(r.Tracks))
+	for i, t := range r.Tracks {
+		discNumber := 1
+		if t.DiscNumber != nil {
+			discNumber = *t.DiscNumber
+		}
+		positions[i] = model.TrackPosition{
+			TrackID:     model.TrackID(t.ID),
+			DiscNumber:  discNumber,
+			TrackNumber: t.TrackNumber,
+		}
+	}
+	return positions
+}
+
+type TrackResponse struct {
+	ID              int    `json:"id"`
+	AlbumID         int    `json:"album_id"`
+	DiscNumber      int    `json:"disc_number"`
+	TrackNumber     int    `json:"track_number"`
+	Title           string `json:"title"`
+	DurationSeconds int    `json:"duration_seconds"`
+	ISRC            string `json:"isrc,omitempty"`
+	Explicit        bool   `json:"explicit"`
+}
+
+func NewTrackResponse(track *model.Track) *TrackResponse {
+	return &TrackResponse{
+		ID:              int(track.ID),
+		AlbumID:         int(track.AlbumID),
+		DiscNumber:      track.DiscNumber,
+		TrackNumber:     track.TrackNumber,
+		Title:           track.Title,
+		DurationSeconds: track.DurationSeconds,
+		ISRC:            track.ISRC,
+		Explicit:        track.Explicit,
+	}
+}
+
+func NewTracksResponse(tracks []*model.Track) []*TrackResponse {
+	responses := make([]*TrackResponse, len(tracks))
+	for i, track := range tracks {
+		responses[i] = NewTrackResponse(track)
+	}
+	return responses
+}
+
+type TrackListResponse struct {
+	Items                []*TrackResponse `json:"items"`
+	TotalDurationSeconds int              `json:"total_duration_seconds"`
+}
+
+func NewTrackListResponse(tracks []*model.Track) *TrackListResponse {
+	album := model.Album{Tracks: tracks}
+	return &TrackListResponse{
+		Items:                NewTracksResponse(tracks),
+		TotalDurationSeconds: album.TotalDuration(),
+	}
+}
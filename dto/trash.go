@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type TrashedSingerResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedAlbumResponse struct {
+	ID        int            `json:"id"`
+	Title     string         `json:"title"`
+	Singer    SingerResponse `json:"singer"`
+	DeletedAt time.Time      `json:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Singers []*TrashedSingerResponse `json:"singers"`
+	Albums  []*TrashedAlbumResponse  `json:"albums"`
+}
+
+func NewTrashResponse(trash *model.Trash) *TrashResponse {
+	res := &TrashResponse{
+		Singers: make([]*TrashedSingerResponse, len(trash.Singers)),
+		Albums:  make([]*TrashedAlbumResponse, len(trash.Albums)),
+	}
+	for i, singer := range trash.Singers {
+		res.Singers[i] = &TrashedSingerResponse{
+			ID:        int(singer.ID),
+			Name:      singer.Name,
+			DeletedAt: *singer.DeletedAt,
+		}
+	}
+	for i, album := range trash.Albums {
+		res.Albums[i] = &TrashedAlbumResponse{
+			ID:    int(album.ID),
+			Title: album.Title,
+			Singer: SingerResponse{
+				ID:   int(album.Singer.ID),
+				Name: album.Singer.Name,
+			},
+			DeletedAt: *album.DeletedAt,
+		}
+	}
+	return res
+}
@@ -1,23 +1,170 @@
 package mysqldb
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Initialize(user, pass, addr, name string) (*sql.DB, error) {
-	c := mysql.Config{
-		User:      user,
-		Passwd:    pass,
-		Net:       "tcp",
-		Addr:      addr,
-		DBName:    name,
-		ParseTime: true,
-	}
-	db, err := sql.Open("mysql", c.FormatDSN())
+	db, err := sql.Open("mysql", baseConfig(user, pass, addr, name).FormatDSN())
 	if err != nil {
 		return nil, err
 	}
 	return db, nil
 }
+
+func baseConfig(user, pass, addr, name string) *mysql.Config {
+	c := mysql.NewConfig()
+	c.User = user
+	c.Passwd = pass
+	c.Net = "tcp"
+	c.Addr = addr
+	c.DBName = name
+	c.ParseTime = true
+	// report matched rows so an UPDATE without changes is not mistaken for a missing row
+	c.ClientFoundRows = true
+	return c
+}
+
+// Open builds a pool tuned by c without connecting yet. Every statement becomes a span of tp.
+func Open(c config.DB, tp trace.TracerProvider) (*sql.DB, error) {
+	mc, err := newConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(mc)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(TraceConnector(connector, tp, c.Name))
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+	return db, nil
+}
+
+// Connect opens the pool and waits until MySQL answers, so the server can start alongside a
+// database that is still booting.
+func Connect(ctx context.Context, c config.DB, tp trace.TracerProvider) (*sql.DB, error) {
+	db, err := Open(c, tp)
+	if err != nil {
+		return nil, err
+	}
+	retry := Retry{Timeout: c.StartupTimeout, InitialBackoff: c.RetryInitialBackoff, MaxBackoff: c.RetryMaxBackoff}
+	if err = WaitReady(ctx, db, retry); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func newConfig(c config.DB) (*mysql.Config, error) {
+	mc := baseConfig(c.User, c.Password, c.Addr, c.Name)
+	mc.Timeout = c.DialTimeout
+	mc.ReadTimeout = c.ReadTimeout
+	mc.WriteTimeout = c.WriteTimeout
+	// utf8mb4_0900_ai_ci is negotiated in the handshake; collation_connection then switches
+	// literals to the configured collation. Comparisons with a column use the column's own
+	// collation, declared in initdb/1_schema.sql, so this only matters for expressions
+	// without one and should name the same collation.
+	mc.Collation = "utf8mb4_0900_ai_ci"
+	if c.Collation != "" && c.Collation != mc.Collation {
+		mc.Params = map[string]string{"collation_connection": "'" + c.Collation + "'"}
+	}
+
+	switch c.TLS.Mode {
+	case config.TLSDisabled:
+	case config.TLSPreferred:
+		mc.TLSConfig = "preferred"
+	case config.TLSSkipVerify:
+		mc.TLSConfig = "skip-verify"
+	case config.TLSRequired:
+		tlsConfig, err := newTLSConfig(c.Addr, c.TLS)
+		if err != nil {
+			return nil, err
+		}
+		mc.TLS = tlsConfig
+	default:
+		return nil, fmt.Errorf("unknown TLS mode %q", c.TLS.Mode)
+	}
+	return mc, nil
+}
+
+func newTLSConfig(addr string, c config.TLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: c.ServerName}
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ServerName = host
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// Retry is an exponential backoff bounded by an overall deadline.
+type Retry struct {
+	Timeout        time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// WaitReady pings db until it answers, doubling the pause between attempts up to MaxBackoff,
+// and gives up with the last error once Timeout has passed or ctx is done.
+func WaitReady(ctx context.Context, db *sql.DB, retry Retry) error {
+	ctx, cancel := context.WithTimeout(ctx, retry.Timeout)
+	defer cancel()
+
+	backoff := retry.InitialBackoff
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		// a ping cut off by the deadline says nothing new, so report the failure before it
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+		slog.WarnContext(ctx, "database not ready", "attempt", attempt, "retry_in", backoff, "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("database not ready after %d attempts: %w", attempt, lastErr)
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, retry.MaxBackoff)
+	}
+}
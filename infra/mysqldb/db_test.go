@@ -0,0 +1,66 @@
+package mysqldb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var fastRetry = mysqldb.Retry{Timeout: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+func TestWaitReady_RetriesUntilReachable(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	booting := errors.New("connection refused")
+	mock.ExpectPing().WillReturnError(booting)
+	mock.ExpectPing().WillReturnError(booting)
+	mock.ExpectPing()
+
+	err = mysqldb.WaitReady(context.Background(), db, fastRetry)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWaitReady_GivesUpAtDeadline(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	booting := errors.New("connection refused")
+	for range 100 {
+		mock.ExpectPing().WillReturnError(booting)
+	}
+
+	retry := fastRetry
+	retry.Timeout = 20 * time.Millisecond
+	err = mysqldb.WaitReady(context.Background(), db, retry)
+	assert.ErrorIs(t, err, booting)
+	assert.ErrorContains(t, err, "database not ready after")
+}
+
+func TestOpen_AppliesPoolSettings(t *testing.T) {
+	c := config.Default().DB
+	c.MaxOpenConns = 7
+
+	db, err := mysqldb.Open(c, noop.NewTracerProvider())
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+}
+
+func TestOpen_RequiredTLSWithMissingCA(t *testing.T) {
+	c := config.Default().DB
+	c.TLS = config.TLS{Mode: config.TLSRequired, CAFile: "/nonexistent/ca.pem"}
+
+	_, err := mysqldb.Open(c, noop.NewTracerProvider())
+	assert.Error(t, err)
+}
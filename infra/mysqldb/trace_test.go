@@ -0,0 +1,82 @@
+package mysqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "SELECT id, name\n\t FROM singers WHERE id = ?", want: "SELECT id, name FROM singers WHERE id = ?"},
+		{query: "SELECT * FROM singers WHERE name = 'Alice' AND id > 10", want: "SELECT * FROM singers WHERE name = ? AND id > ?"},
+		{query: `UPDATE albums SET title = 'It\'s' WHERE id = 3.5`, want: "UPDATE albums SET title = ? WHERE id = ?"},
+		// digits inside identifiers are kept
+		{query: "SELECT utf8mb4_0900_ai_ci FROM t1", want: "SELECT utf8mb4_0900_ai_ci FROM t1"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, mysqldb.SanitizeStatement(tt.query), tt.query)
+	}
+}
+
+// dsnConnector opens connections of a registered driver, standing in for mysql.NewConnector.
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+func TestTraceConnector(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("trace_test")
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider(sdktrace.NewSimpleSpanProcessor(exporter), "test", 1)
+	db := sql.OpenDB(mysqldb.TraceConnector(dsnConnector{driver: mockDB.Driver(), dsn: "trace_test"}, tp, "myapp"))
+	defer db.Close()
+
+	mock.ExpectPrepare("SELECT id FROM singers").
+		ExpectQuery().WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("DELETE FROM albums").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE singers").WillReturnError(sql.ErrConnDone)
+
+	rows, err := db.QueryContext(context.Background(), "SELECT id FROM singers WHERE id >= ?", 1)
+	assert.NoError(t, err)
+	for rows.Next() {
+	}
+	assert.NoError(t, rows.Close())
+	_, err = db.ExecContext(context.Background(), "DELETE FROM albums WHERE deleted_at < NOW()")
+	assert.NoError(t, err)
+	_, err = db.ExecContext(context.Background(), "UPDATE singers SET name = 'x'")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+	assert.Equal(t, "SELECT myapp", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("db.statement", "SELECT id FROM singers WHERE id >= ?"))
+	assert.Contains(t, spans[0].Attributes, attribute.Int64("db.response.returned_rows", 2))
+	assert.Equal(t, "DELETE myapp", spans[1].Name)
+	assert.Contains(t, spans[1].Attributes, attribute.Int64("db.rows_affected", 3))
+	assert.Contains(t, spans[2].Attributes, attribute.String("db.statement", "UPDATE singers SET name = ?"))
+	assert.Equal(t, codes.Error, spans[2].Status.Code)
+}
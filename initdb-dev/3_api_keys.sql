@@ -0,0 +1,5 @@
+-- Keys for local development only; api-test.http sends them. compose.override.yaml mounts
+-- this file next to initdb, so databases created from initdb alone start without any keys.
+INSERT INTO `api_keys` (name, key_hash, role) VALUES ('local viewer', SHA2('local-viewer-key', 256), 'viewer');
+INSERT INTO `api_keys` (name, key_hash, role) VALUES ('local editor', SHA2('local-editor-key', 256), 'editor');
+INSERT INTO `api_keys` (name, key_hash, role) VALUES ('local admin', SHA2('local-admin-key', 256), 'admin');
@@ -1,18 +1,126 @@
+DROP TABLE IF EXISTS schema_migrations;
+DROP TABLE IF EXISTS audit_events;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS album_credits;
+DROP TABLE IF EXISTS tracks;
 DROP TABLE IF EXISTS albums;
 DROP TABLE IF EXISTS singers;
 
+-- Live singers have unique names. live is NULL once a singer is trashed, and NULLs never
+-- collide in a unique key, so the trash may hold any number of singers with a name.
+-- Names and titles use the Japanese collation, under which は, ば and ぱ, or Alice and alice,
+-- are different names rather than duplicates.
 CREATE TABLE singers (
   id INT NOT NULL AUTO_INCREMENT,
-  name VARCHAR(255) NOT NULL,
+  name VARCHAR(255) COLLATE utf8mb4_ja_0900_as_cs NOT NULL,
   created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-  PRIMARY KEY (id)
+  deleted_at DATETIME NULL,
+  version BIGINT NOT NULL DEFAULT 1,
+  live BOOLEAN AS (IF(deleted_at IS NULL, TRUE, NULL)) VIRTUAL,
+  PRIMARY KEY (id),
+  INDEX idx_singers_deleted (deleted_at),
+  UNIQUE KEY uq_singers_name (name, live),
+  FULLTEXT INDEX ft_singers_name (name) WITH PARSER ngram
 );
 
+-- A singer's live albums have unique titles; live works as on singers.
 CREATE TABLE albums (
   id INT NOT NULL AUTO_INCREMENT,
-  title VARCHAR(255) NOT NULL,
+  title VARCHAR(255) COLLATE utf8mb4_ja_0900_as_cs NOT NULL,
   singer_id INT NOT NULL,
   created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  deleted_at DATETIME NULL,
+  version BIGINT NOT NULL DEFAULT 1,
+  live BOOLEAN AS (IF(deleted_at IS NULL, TRUE, NULL)) VIRTUAL,
+  PRIMARY KEY (id),
+  INDEX idx_albums_deleted (deleted_at),
+  INDEX idx_albums_singer_created (singer_id, created_at),
+  UNIQUE KEY uq_albums_singer_title (singer_id, title, live),
+  FOREIGN KEY (singer_id) REFERENCES singers(id),
+  FULLTEXT INDEX ft_albums_title (title) WITH PARSER ngram
+);
+
+CREATE TABLE tracks (
+  id INT NOT NULL AUTO_INCREMENT,
+  album_id INT NOT NULL,
+  disc_number INT NOT NULL DEFAULT 1,
+  track_number INT NOT NULL,
+  title VARCHAR(255) COLLATE utf8mb4_ja_0900_as_cs NOT NULL,
+  duration_seconds INT NOT NULL,
+  isrc CHAR(12) NOT NULL DEFAULT '',
+  explicit BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (id),
+  UNIQUE KEY uq_tracks_position (album_id, disc_number, track_number),
+  FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE CASCADE
+);
+
+-- Secondary credits only; the primary artist stays in albums.singer_id.
+CREATE TABLE album_credits (
+  album_id INT NOT NULL,
+  singer_id INT NOT NULL,
+  role ENUM('featured', 'producer', 'composer') NOT NULL,
+  position INT NOT NULL,
+  PRIMARY KEY (album_id, singer_id, role),
+  INDEX idx_album_credits_singer (singer_id),
+  FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE CASCADE,
   FOREIGN KEY (singer_id) REFERENCES singers(id)
 );
+
+-- Responses to POST requests sent with an Idempotency-Key, per principal (subject is empty
+-- without authentication). status_code is 0 while the first request is still running.
+CREATE TABLE idempotency_keys (
+  subject VARCHAR(255) NOT NULL DEFAULT '',
+  idempotency_key VARCHAR(255) NOT NULL,
+  fingerprint CHAR(64) NOT NULL,
+  status_code INT NOT NULL DEFAULT 0,
+  header JSON NULL,
+  body MEDIUMBLOB NULL,
+  created_at DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL,
+  PRIMARY KEY (subject, idempotency_key),
+  INDEX idx_idempotency_keys_expires (expires_at)
+);
+
+-- Keys accepted in the X-API-Key header. Only the SHA-256 hex digest of a key is stored, so a
+-- leaked table does not leak usable keys. The name becomes the principal subject, which audit
+-- events, idempotency keys and import jobs are recorded under, so it stays unique even after
+-- the key is revoked.
+CREATE TABLE api_keys (
+  id INT NOT NULL AUTO_INCREMENT,
+  name VARCHAR(255) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  role ENUM('viewer', 'editor', 'admin') NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  revoked_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_api_keys_hash (key_hash),
+  UNIQUE KEY uq_api_keys_name (name)
+);
+
+-- Who changed which singer or album, and how. Rows are written in the transaction of the
+-- change and outlive the entity, so there are no foreign keys.
+CREATE TABLE audit_events (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  actor VARCHAR(255) NOT NULL,
+  action ENUM('create', 'update', 'delete', 'restore') NOT NULL,
+  entity_type ENUM('singer', 'album') NOT NULL,
+  entity_id INT NOT NULL,
+  request_id VARCHAR(128) NOT NULL DEFAULT '',
+  before_snapshot JSON NULL,
+  after_snapshot JSON NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_audit_events_entity (entity_type, entity_id, id),
+  INDEX idx_audit_events_actor (actor, id)
+);
+
+-- Applied schema versions; readiness fails until the latest one the server expects is present.
+CREATE TABLE schema_migrations (
+  version INT NOT NULL,
+  applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (version)
+);
+
+INSERT INTO schema_migrations (version) VALUES (1), (2), (3), (4), (5), (6), (7);
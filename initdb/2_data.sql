@@ -7,3 +7,10 @@ INSERT INTO `singers` (id, name) VALUES (5, 'Ellen');
 INSERT INTO `albums` (id, title, singer_id) VALUES (1, "Alice's 1st Album", 1);
 INSERT INTO `albums` (id, title, singer_id) VALUES (2, "Alice's 2nd Album", 1);
 INSERT INTO `albums` (id, title, singer_id) VALUES (3, "Bella's 1st Album", 2);
+
+INSERT INTO `album_credits` (album_id, singer_id, role, position) VALUES (2, 2, 'featured', 1);
+INSERT INTO `album_credits` (album_id, singer_id, role, position) VALUES (2, 3, 'producer', 2);
+
+INSERT INTO `tracks` (album_id, disc_number, track_number, title, duration_seconds, isrc) VALUES (1, 1, 1, "Opening", 215, 'JPAB02500001');
+INSERT INTO `tracks` (album_id, disc_number, track_number, title, duration_seconds, isrc) VALUES (1, 1, 2, "Blue Sky", 248, 'JPAB02500002');
+INSERT INTO `tracks` (album_id, disc_number, track_number, title, duration_seconds, isrc) VALUES (1, 1, 3, "Goodnight", 301, 'JPAB02500003');
@@ -0,0 +1,27 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), logging.FromContext(context.Background()))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With("request_id", "abc")
+	ctx := logging.NewContext(context.Background(), logger)
+
+	logging.FromContext(ctx).InfoContext(ctx, "hello")
+	assert.Contains(t, buf.String(), "request_id=abc")
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "", logging.RequestID(context.Background()))
+	assert.Equal(t, "abc", logging.RequestID(logging.WithRequestID(context.Background(), "abc")))
+}
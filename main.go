@@ -2,46 +2,79 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/pulse227/server-recruit-challenge-sample/api"
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(
-		os.Stdout,
-		&slog.HandlerOptions{
-			Level: slog.LevelDebug,
-		},
-	))
+	cfg, printConfig, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+	if printConfig {
+		if err = cfg.WriteRedacted(os.Stdout); err != nil {
+			log.Fatalf("print config error: %v", err)
+		}
+		return
+	}
+
+	logger := slog.New(tracing.NewLogHandler(cfg.Log.NewHandler(os.Stdout)))
 	slog.SetDefault(logger)
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	r, err := api.NewRouter("root", "root", "localhost:13306", "myapp")
+	r, err := api.NewRouter(ctx, cfg)
 	if err != nil {
 		log.Fatalf("new app error: %v", err)
 	}
 
 	server := &http.Server{
-		Addr:    ":8888",
-		Handler: r,
+		Addr:              cfg.Server.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
 	}
 
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		<-ctx.Done()
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		r.Drain()
+		slog.Info("draining before shutdown", "delay", cfg.Server.DrainDelay)
+		time.Sleep(cfg.Server.DrainDelay)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 		defer cancel()
-		if err = server.Shutdown(shutdownCtx); err != nil {
+		if err := server.Shutdown(shutdownCtx); err != nil {
 			slog.Error("server shutdown error", "error", err)
 		}
 	}()
-	slog.Info("server start running at :8888")
-	log.Fatal(server.ListenAndServe())
+	slog.Info("server start running at " + cfg.Server.Addr)
+	if err = server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	// ListenAndServe returns as soon as Shutdown starts; wait for in-flight requests.
+	<-shutdownDone
+	closeCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err = r.Close(closeCtx); err != nil {
+		slog.Error("close error", "error", err)
+	}
 }
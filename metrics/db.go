@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// QueryBuckets suit single repository calls, which are mostly well under a second.
+var QueryBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
+
+// RegisterDBStats exposes the pool statistics of sql.DB, read on every scrape.
+func RegisterDBStats(r *Registry, stats func() sql.DBStats) {
+	r.NewGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.",
+		func() float64 { return float64(stats().MaxOpenConnections) })
+	r.NewGaugeFunc("db_open_connections", "Number of established connections, in use and idle.",
+		func() float64 { return float64(stats().OpenConnections) })
+	r.NewGaugeFunc("db_in_use_connections", "Number of connections currently in use.",
+		func() float64 { return float64(stats().InUse) })
+	r.NewGaugeFunc("db_idle_connections", "Number of idle connections.",
+		func() float64 { return float64(stats().Idle) })
+	r.NewCounterFunc("db_wait_count_total", "Total number of connections waited for.",
+		func() float64 { return float64(stats().WaitCount) })
+	r.NewCounterFunc("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		func() float64 { return stats().WaitDuration.Seconds() })
+	r.NewCounterFunc("db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.",
+		func() float64 { return float64(stats().MaxIdleClosed) })
+	r.NewCounterFunc("db_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.",
+		func() float64 { return float64(stats().MaxIdleTimeClosed) })
+	r.NewCounterFunc("db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.",
+		func() float64 { return float64(stats().MaxLifetimeClosed) })
+}
+
+// QueryObserver records how long each repository method takes; it satisfies
+// repository.Observer.
+type QueryObserver struct {
+	duration *HistogramVec
+	errors   *CounterVec
+}
+
+func NewQueryObserver(r *Registry) *QueryObserver {
+	return &QueryObserver{
+		duration: r.NewHistogramVec("db_query_duration_seconds", "Duration of repository calls.",
+			QueryBuckets, "repository", "method"),
+		errors: r.NewCounterVec("db_query_errors_total", "Repository calls that returned an error, by model.ErrorKind.",
+			"repository", "method", "kind"),
+	}
+}
+
+func (o *QueryObserver) StartCall(ctx context.Context, repository, method string) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		o.duration.Observe(time.Since(start).Seconds(), repository, method)
+		if err != nil {
+			o.errors.Inc(repository, method, model.KindOf(err).String())
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package metrics_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/metrics"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDBStats(t *testing.T) {
+	r := metrics.NewRegistry()
+	metrics.RegisterDBStats(r, func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 25, OpenConnections: 4, InUse: 3, Idle: 1, WaitCount: 2, WaitDuration: 250 * time.Millisecond}
+	})
+
+	out := scrape(t, r)
+	assert.Contains(t, out, "# TYPE db_open_connections gauge\ndb_open_connections 4\n")
+	assert.Contains(t, out, "db_in_use_connections 3\n")
+	assert.Contains(t, out, "# TYPE db_wait_count_total counter\ndb_wait_count_total 2\n")
+	assert.Contains(t, out, "db_wait_duration_seconds_total 0.25\n")
+}
+
+func TestQueryObserver(t *testing.T) {
+	r := metrics.NewRegistry()
+	observer := metrics.NewQueryObserver(r)
+
+	ctx := context.Background()
+	_, done := observer.StartCall(ctx, "singer", "Get")
+	done(nil)
+	_, done = observer.StartCall(ctx, "singer", "Get")
+	done(model.NewError(model.KindNotFound, "singer not found", nil))
+
+	out := scrape(t, r)
+	assert.Contains(t, out, `db_query_duration_seconds_count{repository="singer",method="Get"} 2`)
+	assert.Contains(t, out, `db_query_errors_total{repository="singer",method="Get",kind="not_found"} 1`)
+}
@@ -0,0 +1,96 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T, r *metrics.Registry) string {
+	var b strings.Builder
+	err := r.WriteText(&b)
+	assert.NoError(t, err)
+	return b.String()
+}
+
+func TestRegistry_Counter(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.NewCounterVec("jobs_total", "Jobs done.", "queue", "result")
+	c.Inc("mail", "ok")
+	c.Add(2, "mail", "ok")
+	c.Inc("import", "fail")
+
+	assert.Equal(t,
+		"# HELP jobs_total Jobs done.\n"+
+			"# TYPE jobs_total counter\n"+
+			`jobs_total{queue="import",result="fail"} 1`+"\n"+
+			`jobs_total{queue="mail",result="ok"} 3`+"\n",
+		scrape(t, r))
+}
+
+func TestRegistry_GaugeEscapesLabelValues(t *testing.T) {
+	r := metrics.NewRegistry()
+	g := r.NewGaugeVec("temperature", "Line one\nline two.", "room")
+	g.Set(21.5, `a "quoted" \ room`)
+	g.Add(-1.5, `a "quoted" \ room`)
+
+	assert.Equal(t,
+		"# HELP temperature Line one\\nline two.\n"+
+			"# TYPE temperature gauge\n"+
+			`temperature{room="a \"quoted\" \\ room"} 20`+"\n",
+		scrape(t, r))
+}
+
+func TestRegistry_Histogram(t *testing.T) {
+	r := metrics.NewRegistry()
+	h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 0.5, 1})
+	h.Observe(0.05)
+	h.Observe(0.1)
+	h.Observe(0.7)
+	h.Observe(3)
+
+	assert.Equal(t,
+		"# HELP latency_seconds Latency.\n"+
+			"# TYPE latency_seconds histogram\n"+
+			`latency_seconds_bucket{le="0.1"} 2`+"\n"+
+			`latency_seconds_bucket{le="0.5"} 2`+"\n"+
+			`latency_seconds_bucket{le="1"} 3`+"\n"+
+			`latency_seconds_bucket{le="+Inf"} 4`+"\n"+
+			"latency_seconds_sum 3.85\n"+
+			"latency_seconds_count 4\n",
+		scrape(t, r))
+}
+
+func TestRegistry_SortedByName(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewGaugeFunc("b_gauge", "B.", func() float64 { return 2 })
+	r.NewCounterFunc("a_total", "A.", func() float64 { return 1 })
+
+	out := scrape(t, r)
+	assert.Less(t, strings.Index(out, "a_total 1"), strings.Index(out, "b_gauge 2"))
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.NewCounterVec("dup_total", "Dup.", "label")
+
+	assert.Panics(t, func() { r.NewCounterVec("dup_total", "Dup.") })
+	assert.Panics(t, func() { c.Inc() })
+	assert.Panics(t, func() { c.Add(-1, "x") })
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewGaugeFunc("up", "Always 1.", func() float64 { return 1 })
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, metrics.ContentType, rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "up 1\n")
+}
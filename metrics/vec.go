@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// series is one combination of label values.
+type series struct {
+	values  []string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// vec keeps the series of one metric keyed by their label values.
+type vec struct {
+	d      desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newVec(name, help, typ string, labels []string) vec {
+	return vec{d: desc{name: name, help: help, typ: typ, labels: labels}, series: make(map[string]*series)}
+}
+
+func (v *vec) desc() *desc {
+	return &v.d
+}
+
+// with returns the series for values, creating it on first use. v.mu must be held.
+func (v *vec) with(values []string) *series {
+	if len(values) != len(v.d.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", v.d.name, len(v.d.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{values: slices.Clone(values)}
+		v.series[key] = s
+	}
+	return s
+}
+
+// sorted returns the series ordered by label values for a stable output. v.mu must be held.
+func (v *vec) sorted() []*series {
+	all := make([]*series, 0, len(v.series))
+	for _, s := range v.series {
+		all = append(all, s)
+	}
+	slices.SortFunc(all, func(a, b *series) int { return slices.Compare(a.values, b.values) })
+	return all
+}
+
+// CounterVec only goes up.
+type CounterVec struct {
+	vec
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec: newVec(name, help, "counter", labels)}
+	r.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.d.name))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.with(values).value += delta
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.sorted() {
+		writeSample(w, c.d.name, c.d.labels, s.values, "", s.value)
+	}
+}
+
+// GaugeVec goes up and down.
+type GaugeVec struct {
+	vec
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{vec: newVec(name, help, "gauge", labels)}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeVec) Set(value float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.with(values).value = value
+}
+
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.with(values).value += delta
+}
+
+func (g *GaugeVec) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, s := range g.sorted() {
+		writeSample(w, g.d.name, g.d.labels, s.values, "", s.value)
+	}
+}
+
+// DefBuckets suit request latencies in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HistogramVec counts observations into cumulative buckets.
+type HistogramVec struct {
+	vec
+	bounds []float64
+}
+
+// NewHistogramVec takes the upper bounds of the buckets in increasing order; +Inf is implied.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if !slices.IsSorted(buckets) {
+		panic(fmt.Sprintf("metrics: buckets of %s are not sorted", name))
+	}
+	h := &HistogramVec{vec: newVec(name, help, "histogram", labels), bounds: slices.Clone(buckets)}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.with(values)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(h.bounds))
+	}
+	// buckets are stored per bound and summed up when written
+	if i, _ := slices.BinarySearch(h.bounds, value); i < len(h.bounds) {
+		s.buckets[i]++
+	}
+	s.sum += value
+	s.count++
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, s := range h.sorted() {
+		var cumulative uint64
+		for i, bound := range h.bounds {
+			cumulative += s.buckets[i]
+			writeSample(w, h.d.name+"_bucket", h.d.labels, s.values, `le="`+formatFloat(bound)+`"`, float64(cumulative))
+		}
+		writeSample(w, h.d.name+"_bucket", h.d.labels, s.values, `le="+Inf"`, float64(s.count))
+		writeSample(w, h.d.name+"_sum", h.d.labels, s.values, "", s.sum)
+		writeSample(w, h.d.name+"_count", h.d.labels, s.values, "", float64(s.count))
+	}
+}
+
+// funcMetric reads its single value when scraped, e.g. from sql.DB.Stats.
+type funcMetric struct {
+	d  desc
+	fn func() float64
+}
+
+func (m *funcMetric) desc() *desc {
+	return &m.d
+}
+
+func (m *funcMetric) write(w *bufio.Writer) {
+	writeSample(w, m.d.name, nil, nil, "", m.fn())
+}
+
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{d: desc{name: name, help: help, typ: "gauge"}, fn: fn})
+}
+
+// NewCounterFunc is for totals kept elsewhere; fn must never decrease.
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{d: desc{name: name, help: help, typ: "counter"}, fn: fn})
+}
@@ -1,20 +1,37 @@
 package model
 
+import "time"
+
 type AlbumID int
 
 type Album struct {
-	ID       AlbumID  `json:"id"`
-	Title    string   `json:"title"`
-	SingerID SingerID `json:"singer_id"`
-	Singer   *Singer  `json:"singer"`
+	ID        AlbumID    `json:"id"`
+	Title     string     `json:"title"`
+	SingerID  SingerID   `json:"singer_id"`
+	Singer    *Singer    `json:"singer"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	Tracks    []*Track   `json:"tracks"`
+	// Version works like Singer.Version.
+	Version int64 `json:"version"`
 }
 
 func (a *Album) Validate() error {
+	v := &ValidationError{}
 	if a.Title == "" {
-		return ErrInvalidParam
+		v.Add("title", CodeRequired, "title is required")
 	}
 	if len(a.Title) > 255 {
-		return ErrInvalidParam
+		v.Add("title", CodeMaxLength, "title must be at most 255 characters")
+	}
+	return v.Err()
+}
+
+// TotalDuration sums the duration of the album's tracks in seconds.
+func (a *Album) TotalDuration() int {
+	total := 0
+	for _, t := range a.Tracks {
+		total += t.DurationSeconds
 	}
-	return nil
+	return total
 }
@@ -40,3 +40,15 @@ func TestAlbum_Validate(t *testing.T) {
 	err = longTitleAlbum.Validate()
 	assert.ErrorIs(t, err, model.ErrInvalidParam)
 }
+
+func TestAlbum_Validate_FieldErrors(t *testing.T) {
+	album := model.Album{Title: strings.Repeat("a", 256)}
+	err := album.Validate()
+
+	var validationErr *model.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []model.FieldError{
+		{Field: "title", Code: model.CodeMaxLength, Message: "title must be at most 255 characters"},
+	}, validationErr.Fields)
+	assert.Equal(t, model.KindValidation, model.KindOf(err))
+}
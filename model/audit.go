@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+)
+
+type AuditEntityType string
+
+const (
+	AuditEntitySinger AuditEntityType = "singer"
+	AuditEntityAlbum  AuditEntityType = "album"
+)
+
+func (t AuditEntityType) Valid() bool {
+	return t == AuditEntitySinger || t == AuditEntityAlbum
+}
+
+// AuditActorAnonymous is the actor of changes made without an authenticated principal,
+// e.g. with authentication disabled.
+const AuditActorAnonymous = "anonymous"
+
+// AuditEvent records one change to a singer or album. Before and After hold the entity as
+// JSON; Before is nil for a create or restore and After is nil for a delete.
+type AuditEvent struct {
+	ID         int64
+	Actor      string
+	Action     AuditAction
+	EntityType AuditEntityType
+	EntityID   int
+	RequestID  string
+	Before     json.RawMessage
+	After      json.RawMessage
+	CreatedAt  time.Time
+}
+
+// AuditQuery lists audit events newest first, for one entity, one actor or both.
+type AuditQuery struct {
+	Limit      int
+	Cursor     *Cursor
+	EntityType AuditEntityType
+	EntityID   int
+	Actor      string
+}
+
+func (q *AuditQuery) Validate() error {
+	v := &ValidationError{}
+	validateLimit(v, q.Limit)
+	switch {
+	case q.EntityType == "" && q.EntityID == 0 && q.Actor == "":
+		v.Add("entity", CodeRequired, "entity and id, or actor, is required")
+	case q.EntityType != "" && !q.EntityType.Valid():
+		v.Add("entity", CodeInvalid, "entity must be one of singer, album")
+	case q.EntityType != "" && q.EntityID == 0:
+		v.Add("id", CodeRequired, "id is required with entity")
+	case q.EntityType == "" && q.EntityID != 0:
+		v.Add("entity", CodeRequired, "entity is required with id")
+	}
+	// the audit log is only ever listed newest first
+	validateCursor(v, q.Cursor, "id", true)
+	return v.Err()
+}
@@ -0,0 +1,32 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditQuery_Validate(t *testing.T) {
+	valid := []model.AuditQuery{
+		{Limit: 20, EntityType: model.AuditEntityAlbum, EntityID: 3},
+		{Limit: 20, Actor: "api_key:local admin"},
+		{Limit: 20, EntityType: model.AuditEntitySinger, EntityID: 1, Actor: "alice"},
+		{Limit: 20, Actor: "alice", Cursor: &model.Cursor{Sort: "id", Desc: true, ID: 10}},
+	}
+	for _, q := range valid {
+		assert.NoError(t, q.Validate())
+	}
+
+	invalid := []model.AuditQuery{
+		{Limit: 20},
+		{Limit: 20, EntityType: "track", EntityID: 1},
+		{Limit: 20, EntityType: model.AuditEntityAlbum},
+		{Limit: 20, EntityID: 3},
+		{Limit: 0, Actor: "alice"},
+		{Limit: 20, Actor: "alice", Cursor: &model.Cursor{Sort: "id", ID: 10}},
+	}
+	for _, q := range invalid {
+		assert.ErrorIs(t, q.Validate(), model.ErrInvalidParam)
+	}
+}
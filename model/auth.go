@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Role is what a caller may do. Each role includes the ones before it: viewers read, editors
+// also write, and admins also manage the trash and the internal endpoints.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Allows reports whether r may use a route that requires the role required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && r.rank() >= required.rank()
+}
+
+type AuthMethod string
+
+const (
+	AuthMethodAPIKey AuthMethod = "api_key"
+	AuthMethodJWT    AuthMethod = "jwt"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject is the JWT sub claim, or "api_key:<name>" for API keys, whose names are unique.
+	Subject string
+	Role    Role
+	Method  AuthMethod
+}
+
+// APIKey is a stored API key. Only the SHA-256 hex digest of the key itself is kept.
+type APIKey struct {
+	ID        int64
+	Name      string
+	Hash      string
+	Role      Role
+	CreatedAt time.Time
+}
+
+func (k *APIKey) Principal() *Principal {
+	return &Principal{Subject: "api_key:" + k.Name, Role: k.Role, Method: AuthMethodAPIKey}
+}
@@ -0,0 +1,27 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     model.Role
+		required model.Role
+		want     bool
+	}{
+		{role: model.RoleViewer, required: model.RoleViewer, want: true},
+		{role: model.RoleViewer, required: model.RoleEditor, want: false},
+		{role: model.RoleEditor, required: model.RoleViewer, want: true},
+		{role: model.RoleEditor, required: model.RoleAdmin, want: false},
+		{role: model.RoleAdmin, required: model.RoleAdmin, want: true},
+		{role: model.Role("root"), required: model.RoleViewer, want: false},
+		{role: model.Role(""), required: model.RoleViewer, want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.role.Allows(tt.required), "%s requires %s", tt.role, tt.required)
+	}
+}
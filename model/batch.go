@@ -0,0 +1,61 @@
+package model
+
+import "fmt"
+
+// BatchAction is what a single operation of a batch request does.
+type BatchAction string
+
+const (
+	BatchCreate BatchAction = "create"
+	BatchUpdate BatchAction = "update"
+	BatchDelete BatchAction = "delete"
+)
+
+func (a BatchAction) Valid() bool {
+	switch a {
+	case BatchCreate, BatchUpdate, BatchDelete:
+		return true
+	}
+	return false
+}
+
+// BatchMode selects whether a batch is applied all-or-nothing or item by item.
+type BatchMode string
+
+const (
+	BatchAtomic     BatchMode = "atomic"
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+func (m BatchMode) Valid() bool {
+	return m == BatchAtomic || m == BatchBestEffort
+}
+
+// BatchOperation applies Action to Item. Deletes only use the item's id and version.
+type BatchOperation[T any] struct {
+	Action BatchAction
+	Item   T
+}
+
+// BatchResult is the outcome of the operation at Index. ID is the id of the created,
+// updated or deleted resource.
+type BatchResult struct {
+	Index  int
+	Action BatchAction
+	ID     int
+	Err    error
+}
+
+// BatchError aborts an atomic batch because the operation at Index failed.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operations[%d]: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchError(t *testing.T) {
+	cause := model.NewError(model.KindConflict, "album already exists", nil)
+	err := error(&model.BatchError{Index: 2, Err: cause})
+
+	assert.Equal(t, "operations[2]: album already exists", err.Error())
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, model.KindConflict, model.KindOf(err))
+}
+
+func TestBatchAction_Valid(t *testing.T) {
+	assert.True(t, model.BatchDelete.Valid())
+	assert.False(t, model.BatchAction("upsert").Valid())
+	assert.True(t, model.BatchBestEffort.Valid())
+	assert.False(t, model.BatchMode("all").Valid())
+}
@@ -0,0 +1,70 @@
+package model
+
+import "fmt"
+
+type CreditRole string
+
+const (
+	CreditRolePrimary  CreditRole = "primary"
+	CreditRoleFeatured CreditRole = "featured"
+	CreditRoleProducer CreditRole = "producer"
+	CreditRoleComposer CreditRole = "composer"
+)
+
+func (r CreditRole) Valid() bool {
+	switch r {
+	case CreditRolePrimary, CreditRoleFeatured, CreditRoleProducer, CreditRoleComposer:
+		return true
+	}
+	return false
+}
+
+// Credit links a singer to an album in a role. The primary credit mirrors Album.SingerID;
+// Position orders the credits as they are displayed.
+type Credit struct {
+	AlbumID  AlbumID    `json:"album_id"`
+	SingerID SingerID   `json:"singer_id"`
+	Singer   *Singer    `json:"singer"`
+	Role     CreditRole `json:"role"`
+	Position int        `json:"position"`
+}
+
+// ValidateCredits checks a complete credit list for an album: exactly one primary artist,
+// known roles, and no singer credited twice in the same role.
+func ValidateCredits(credits []*Credit) error {
+	v := &ValidationError{}
+	primaries := 0
+	type creditKey struct {
+		singerID SingerID
+		role     CreditRole
+	}
+	seen := make(map[creditKey]bool, len(credits))
+	for i, c := range credits {
+		field := fmt.Sprintf("credits[%d].role", i)
+		if !c.Role.Valid() {
+			v.Add(field, CodeInvalid, "role must be one of primary, featured, producer, composer")
+			continue
+		}
+		if c.Role == CreditRolePrimary {
+			primaries++
+		}
+		key := creditKey{c.SingerID, c.Role}
+		if seen[key] {
+			v.Add(field, CodeDuplicate, fmt.Sprintf("singer %d is already credited as %s", c.SingerID, c.Role))
+		}
+		seen[key] = true
+	}
+	switch {
+	case primaries == 0:
+		v.Add("credits", CodeRequired, "exactly one primary credit is required")
+	case primaries > 1:
+		v.Add("credits", CodeDuplicate, "exactly one primary credit is required")
+	}
+	return v.Err()
+}
+
+// SingerAlbum is an album as seen from one of its credited singers.
+type SingerAlbum struct {
+	Album *Album
+	Roles []CreditRole
+}
@@ -0,0 +1,70 @@
+package model_test
+
+import (
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateCredits(t *testing.T) {
+	valid := []*model.Credit{
+		{SingerID: 1, Role: model.CreditRolePrimary},
+		{SingerID: 2, Role: model.CreditRoleFeatured},
+		{SingerID: 2, Role: model.CreditRoleComposer},
+	}
+	assert.NoError(t, model.ValidateCredits(valid))
+
+	tests := []struct {
+		name    string
+		credits []*model.Credit
+		want    []model.FieldError
+	}{
+		{
+			name:    "no primary",
+			credits: []*model.Credit{{SingerID: 2, Role: model.CreditRoleFeatured}},
+			want: []model.FieldError{
+				{Field: "credits", Code: model.CodeRequired, Message: "exactly one primary credit is required"},
+			},
+		},
+		{
+			name: "two primaries",
+			credits: []*model.Credit{
+				{SingerID: 1, Role: model.CreditRolePrimary},
+				{SingerID: 2, Role: model.CreditRolePrimary},
+			},
+			want: []model.FieldError{
+				{Field: "credits", Code: model.CodeDuplicate, Message: "exactly one primary credit is required"},
+			},
+		},
+		{
+			name: "unknown role",
+			credits: []*model.Credit{
+				{SingerID: 1, Role: model.CreditRolePrimary},
+				{SingerID: 2, Role: "arranger"},
+			},
+			want: []model.FieldError{
+				{Field: "credits[1].role", Code: model.CodeInvalid, Message: "role must be one of primary, featured, producer, composer"},
+			},
+		},
+		{
+			name: "same singer and role twice",
+			credits: []*model.Credit{
+				{SingerID: 1, Role: model.CreditRolePrimary},
+				{SingerID: 2, Role: model.CreditRoleFeatured},
+				{SingerID: 2, Role: model.CreditRoleFeatured},
+			},
+			want: []model.FieldError{
+				{Field: "credits[2].role", Code: model.CodeDuplicate, Message: "singer 2 is already credited as featured"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := model.ValidateCredits(tt.credits)
+
+			var validationErr *model.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.want, validationErr.Fields)
+		})
+	}
+}
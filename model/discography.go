@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Discography summarizes the albums a singer is credited on. Albums carry no release
+// date, so the bounds are when the first and latest albums were added.
+type Discography struct {
+	Albums        []*SingerAlbum
+	AlbumCount    int
+	FirstAddedAt  *time.Time
+	LatestAddedAt *time.Time
+}
+
+func NewDiscography(albums []*SingerAlbum) *Discography {
+	d := &Discography{Albums: albums, AlbumCount: len(albums)}
+	for _, a := range albums {
+		addedAt := a.Album.CreatedAt
+		if d.FirstAddedAt == nil || addedAt.Before(*d.FirstAddedAt) {
+			d.FirstAddedAt = &addedAt
+		}
+		if d.LatestAddedAt == nil || addedAt.After(*d.LatestAddedAt) {
+			d.LatestAddedAt = &addedAt
+		}
+	}
+	return d
+}
@@ -0,0 +1,27 @@
+package model_test
+
+import (
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewDiscography(t *testing.T) {
+	first := time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
+	latest := time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)
+	albums := []*model.SingerAlbum{
+		{Album: &model.Album{ID: 2, CreatedAt: latest}},
+		{Album: &model.Album{ID: 1, CreatedAt: first}},
+	}
+
+	d := model.NewDiscography(albums)
+	assert.Equal(t, 2, d.AlbumCount)
+	assert.Equal(t, first, *d.FirstAddedAt)
+	assert.Equal(t, latest, *d.LatestAddedAt)
+
+	empty := model.NewDiscography([]*model.SingerAlbum{})
+	assert.Equal(t, 0, empty.AlbumCount)
+	assert.Nil(t, empty.FirstAddedAt)
+	assert.Nil(t, empty.LatestAddedAt)
+}
@@ -6,3 +6,93 @@ var (
 	ErrNotFound     = errors.New("not found")
 	ErrInvalidParam = errors.New("invalid param")
 )
+
+// ErrorKind classifies domain errors independently of the transport and the storage driver.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindForeignKeyViolation
+	KindUnavailable
+	KindPreconditionFailed
+	KindPreconditionRequired
+	KindUnauthenticated
+	KindForbidden
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation"
+	case KindForeignKeyViolation:
+		return "foreign_key_violation"
+	case KindUnavailable:
+		return "unavailable"
+	case KindPreconditionFailed:
+		return "precondition_failed"
+	case KindPreconditionRequired:
+		return "precondition_required"
+	case KindUnauthenticated:
+		return "unauthenticated"
+	case KindForbidden:
+		return "forbidden"
+	default:
+		return "internal"
+	}
+}
+
+// Error is a classified domain error. Message is safe to show to clients,
+// while Err keeps the underlying cause for logging.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func NewError(kind ErrorKind, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is lets classified errors match the generic ErrNotFound and ErrInvalidParam sentinels.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Kind == KindNotFound
+	case ErrInvalidParam:
+		return e.Kind == KindValidation
+	}
+	return false
+}
+
+// KindOf reports the kind of err. Unclassified errors are treated as internal.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return KindNotFound
+	case errors.Is(err, ErrInvalidParam):
+		return KindValidation
+	}
+	return KindInternal
+}
@@ -0,0 +1,39 @@
+package model_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	cause := errors.New("driver error")
+	tests := []struct {
+		name string
+		err  error
+		want model.ErrorKind
+	}{
+		{name: "classified", err: model.NewError(model.KindConflict, "duplicate", cause), want: model.KindConflict},
+		{name: "wrapped classified", err: fmt.Errorf("wrap: %w", model.NewError(model.KindUnavailable, "down", cause)), want: model.KindUnavailable},
+		{name: "not found sentinel", err: model.ErrNotFound, want: model.KindNotFound},
+		{name: "invalid param sentinel", err: model.ErrInvalidParam, want: model.KindValidation},
+		{name: "unclassified", err: cause, want: model.KindInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, model.KindOf(tt.err))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("driver error")
+	err := model.NewError(model.KindNotFound, "album not found", cause)
+
+	assert.Equal(t, "album not found: driver error", err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NotErrorIs(t, err, model.ErrInvalidParam)
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthOK   HealthStatus = "ok"
+	HealthFail HealthStatus = "fail"
+)
+
+type HealthCheck struct {
+	Name    string
+	Status  HealthStatus
+	Latency time.Duration
+	// Error explains a failed check.
+	Error string
+}
+
+// HealthReport is ok only when every check is.
+type HealthReport struct {
+	Status HealthStatus
+	Checks []*HealthCheck
+}
+
+func NewHealthReport(checks ...*HealthCheck) *HealthReport {
+	report := &HealthReport{Status: HealthOK, Checks: checks}
+	for _, check := range checks {
+		if check.Status != HealthOK {
+			report.Status = HealthFail
+		}
+	}
+	return report
+}
+
+func (r *HealthReport) OK() bool {
+	return r.Status == HealthOK
+}
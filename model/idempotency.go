@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// IdempotencyRecord remembers the response to a request sent with an Idempotency-Key so that
+// retries of the same request get the same response.
+type IdempotencyRecord struct {
+	// Subject is the principal that sent the request, empty without authentication. Keys are
+	// scoped to it, so callers cannot replay each other's responses.
+	Subject string
+	Key     string
+	// Fingerprint identifies the request the key was first used with.
+	Fingerprint string
+	// StatusCode is zero while the first request is still being processed.
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+func (r *IdempotencyRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+type ImportRowType string
+
+const (
+	ImportSinger ImportRowType = "singer"
+	ImportAlbum  ImportRowType = "album"
+)
+
+// ImportRow is one record of an import file. Singers are keyed on their name and albums on
+// their singer and title, so importing the same file again changes nothing. Album rows
+// reference their singer by SingerID or by SingerName; a name that matches no singer
+// creates one.
+type ImportRow struct {
+	Line       int
+	Type       ImportRowType
+	Name       string
+	Title      string
+	SingerID   SingerID
+	SingerName string
+}
+
+func (r *ImportRow) Validate() error {
+	v := &ValidationError{}
+	switch r.Type {
+	case ImportSinger:
+		addFields(v, (&Singer{Name: r.Name}).Validate())
+	case ImportAlbum:
+		addFields(v, (&Album{Title: r.Title, SingerID: r.SingerID}).Validate())
+		switch {
+		case r.SingerID == 0 && r.SingerName == "":
+			v.Add("singer_id", CodeRequired, "singer_id or singer_name is required")
+		case r.SingerID != 0 && r.SingerName != "":
+			v.Add("singer_name", CodeInvalid, "singer_id and singer_name cannot both be set")
+		case r.SingerName != "":
+			if err := (&Singer{Name: r.SingerName}).Validate(); err != nil {
+				v.Add("singer_name", CodeMaxLength, "singer_name must be at most 255 characters")
+			}
+		}
+	default:
+		v.Add("type", CodeInvalid, "type must be singer or album")
+	}
+	return v.Err()
+}
+
+func addFields(v *ValidationError, err error) {
+	var fields *ValidationError
+	if errors.As(err, &fields) {
+		v.Fields = append(v.Fields, fields.Fields...)
+	}
+}
+
+// ImportFile is a decoded import file. Errors holds the records that could not be decoded.
+type ImportFile struct {
+	Rows   []*ImportRow
+	Errors []ImportRowError
+}
+
+type ImportRowError struct {
+	Line int
+	FieldError
+}
+
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	// ImportFailed means the job stopped early, for example because the database went away.
+	ImportFailed ImportStatus = "failed"
+)
+
+// ImportJob tracks an import. Rows with errors are skipped and reported; in a dry run the
+// counts say what an import would do without writing anything. Subject is the principal that
+// started the job; only it can poll the job.
+type ImportJob struct {
+	ID             string
+	Subject        string
+	DryRun         bool
+	Status         ImportStatus
+	TotalRows      int
+	ProcessedRows  int
+	SingersCreated int
+	AlbumsCreated  int
+	Unchanged      int
+	Errors         []ImportRowError
+	Failure        string
+	CreatedAt      time.Time
+	FinishedAt     *time.Time
+}
+
+func (j *ImportJob) Clone() *ImportJob {
+	clone := *j
+	clone.Errors = slices.Clone(j.Errors)
+	if j.FinishedAt != nil {
+		finishedAt := *j.FinishedAt
+		clone.FinishedAt = &finishedAt
+	}
+	return &clone
+}
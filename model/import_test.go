@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestImportRow_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		row  model.ImportRow
+		want []model.FieldError
+	}{
+		{name: "singer", row: model.ImportRow{Type: model.ImportSinger, Name: "Alice"}},
+		{name: "album by id", row: model.ImportRow{Type: model.ImportAlbum, Title: "Debut", SingerID: 1}},
+		{name: "album by name", row: model.ImportRow{Type: model.ImportAlbum, Title: "Debut", SingerName: "Alice"}},
+		{
+			name: "unknown type",
+			row:  model.ImportRow{Type: "track"},
+			want: []model.FieldError{{Field: "type", Code: model.CodeInvalid, Message: "type must be singer or album"}},
+		},
+		{
+			name: "singer without name",
+			row:  model.ImportRow{Type: model.ImportSinger},
+			want: []model.FieldError{{Field: "name", Code: model.CodeRequired, Message: "name is required"}},
+		},
+		{
+			name: "album without singer",
+			row:  model.ImportRow{Type: model.ImportAlbum, Title: "Debut"},
+			want: []model.FieldError{{Field: "singer_id", Code: model.CodeRequired, Message: "singer_id or singer_name is required"}},
+		},
+		{
+			name: "album with both singer references",
+			row:  model.ImportRow{Type: model.ImportAlbum, Title: "Debut", SingerID: 1, SingerName: "Alice"},
+			want: []model.FieldError{{Field: "singer_name", Code: model.CodeInvalid, Message: "singer_id and singer_name cannot both be set"}},
+		},
+		{
+			name: "album with long title and singer name",
+			row:  model.ImportRow{Type: model.ImportAlbum, Title: strings.Repeat("a", 256), SingerName: strings.Repeat("b", 256)},
+			want: []model.FieldError{
+				{Field: "title", Code: model.CodeMaxLength, Message: "title must be at most 255 characters"},
+				{Field: "singer_name", Code: model.CodeMaxLength, Message: "singer_name must be at most 255 characters"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.row.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *model.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.want, validationErr.Fields)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Cursor marks the last row of a page. It records the ordering it was issued for,
+// so it cannot be replayed against a differently sorted listing.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+	// Offset is used instead of a keyset by listings ranked by relevance.
+	Offset int `json:"o,omitempty"`
+}
+
+// Encode returns the opaque form handed to clients.
+func (c *Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalidCursor()
+	}
+	c := &Cursor{}
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, invalidCursor()
+	}
+	return c, nil
+}
+
+func invalidCursor() error {
+	v := &ValidationError{}
+	v.Add("cursor", CodeInvalid, "cursor is malformed")
+	return v
+}
+
+// Page is one slice of a listing plus the cursor for the next slice, if any.
+type Page[T any] struct {
+	Items      []T
+	NextCursor *Cursor
+}
+
+type SingerSortField string
+
+const (
+	SingerSortID        SingerSortField = "id"
+	SingerSortName      SingerSortField = "name"
+	SingerSortCreatedAt SingerSortField = "created_at"
+)
+
+type SingerQuery struct {
+	Limit        int
+	Cursor       *Cursor
+	Sort         SingerSortField
+	Desc         bool
+	NamePrefix   string
+	CreatedAfter *time.Time
+}
+
+func (q *SingerQuery) Validate() error {
+	v := &ValidationError{}
+	validateLimit(v, q.Limit)
+	q.validateSort(v)
+	validateCursor(v, q.Cursor, string(q.Sort), q.Desc)
+	return v.Err()
+}
+
+// ValidateExport checks the query of an export, which streams every match and so ignores
+// Limit and Cursor.
+func (q *SingerQuery) ValidateExport() error {
+	v := &ValidationError{}
+	q.validateSort(v)
+	return v.Err()
+}
+
+func (q *SingerQuery) validateSort(v *ValidationError) {
+	switch q.Sort {
+	case SingerSortID, SingerSortName, SingerSortCreatedAt:
+	default:
+		v.Add("sort", CodeInvalid, "sort must be one of id, name, created_at")
+	}
+}
+
+type AlbumSortField string
+
+const (
+	AlbumSortID        AlbumSortField = "id"
+	AlbumSortTitle     AlbumSortField = "title"
+	AlbumSortCreatedAt AlbumSortField = "created_at"
+)
+
+type AlbumQuery struct {
+	Limit        int
+	Cursor       *Cursor
+	Sort         AlbumSortField
+	Desc         bool
+	SingerID     *SingerID
+	TitlePrefix  string
+	CreatedAfter *time.Time
+}
+
+func (q *AlbumQuery) Validate() error {
+	v := &ValidationError{}
+	validateLimit(v, q.Limit)
+	q.validateSort(v)
+	validateCursor(v, q.Cursor, string(q.Sort), q.Desc)
+	return v.Err()
+}
+
+// ValidateExport works like SingerQuery.ValidateExport.
+func (q *AlbumQuery) ValidateExport() error {
+	v := &ValidationError{}
+	q.validateSort(v)
+	return v.Err()
+}
+
+func (q *AlbumQuery) validateSort(v *ValidationError) {
+	switch q.Sort {
+	case AlbumSortID, AlbumSortTitle, AlbumSortCreatedAt:
+	default:
+		v.Add("sort", CodeInvalid, "sort must be one of id, title, created_at")
+	}
+}
+
+func validateLimit(v *ValidationError, limit int) {
+	if limit < 1 || limit > MaxPageLimit {
+		v.Add("limit", CodeInvalid, "limit must be between 1 and 100")
+	}
+}
+
+func validateCursor(v *ValidationError, c *Cursor, sort string, desc bool) {
+	if c != nil && (c.Sort != sort || c.Desc != desc) {
+		v.Add("cursor", CodeInvalid, "cursor was issued for a different sort order")
+	}
+}
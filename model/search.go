@@ -0,0 +1,79 @@
+package model
+
+import "unicode/utf8"
+
+type SearchResultType string
+
+const (
+	SearchResultSinger SearchResultType = "singer"
+	SearchResultAlbum  SearchResultType = "album"
+)
+
+const searchRelevanceSort = "relevance"
+
+// SearchResult is one ranked hit. Name holds the singer name or the album title,
+// and Singer is the album's singer for album hits.
+type SearchResult struct {
+	Type      SearchResultType
+	ID        int
+	Name      string
+	Singer    *Singer
+	Score     float64
+	Highlight string
+}
+
+type SearchQuery struct {
+	Query  string
+	Types  []SearchResultType
+	Limit  int
+	Cursor *Cursor
+}
+
+func (q *SearchQuery) Validate() error {
+	v := &ValidationError{}
+	if q.Query == "" {
+		v.Add("q", CodeRequired, "q is required")
+	}
+	if utf8.RuneCountInString(q.Query) > 100 {
+		v.Add("q", CodeMaxLength, "q must be at most 100 characters")
+	}
+	for _, t := range q.Types {
+		if t != SearchResultSinger && t != SearchResultAlbum {
+			v.Add("type", CodeInvalid, "type must be singer or album")
+		}
+	}
+	validateLimit(v, q.Limit)
+	if q.Cursor != nil && (q.Cursor.Sort != searchRelevanceSort || q.Cursor.Offset < 0) {
+		v.Add("cursor", CodeInvalid, "cursor was issued for a different listing")
+	}
+	return v.Err()
+}
+
+// Offset is the number of ranked results already returned by previous pages.
+func (q *SearchQuery) Offset() int {
+	if q.Cursor == nil {
+		return 0
+	}
+	return q.Cursor.Offset
+}
+
+// Includes reports whether results of type t were requested.
+func (q *SearchQuery) Includes(t SearchResultType) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, requested := range q.Types {
+		if requested == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NextSearchCursor returns the cursor following a page of the given size, or nil when it was the last one.
+func NextSearchCursor(q *SearchQuery, fetched int) *Cursor {
+	if fetched <= q.Limit {
+		return nil
+	}
+	return &Cursor{Sort: searchRelevanceSort, Offset: q.Offset() + q.Limit}
+}
@@ -1,18 +1,33 @@
 package model
 
+import "time"
+
 type SingerID int
 
 type Singer struct {
-	ID   SingerID `json:"id"`
-	Name string   `json:"name"`
+	ID        SingerID   `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	// Version counts the writes to the singer. On update it carries the version the client
+	// last saw, or zero when the client sent none.
+	Version int64 `json:"version"`
 }
 
 func (s *Singer) Validate() error {
+	v := &ValidationError{}
 	if s.Name == "" {
-		return ErrInvalidParam
+		v.Add("name", CodeRequired, "name is required")
 	}
 	if len(s.Name) > 255 {
-		return ErrInvalidParam
+		v.Add("name", CodeMaxLength, "name must be at most 255 characters")
 	}
-	return nil
+	return v.Err()
+}
+
+// SingerDeletion describes what a cascading singer delete removes, or would remove in a dry run.
+type SingerDeletion struct {
+	SingerID SingerID
+	AlbumIDs []AlbumID
+	DryRun   bool
 }
@@ -31,3 +31,15 @@ func TestSinger_Validate(t *testing.T) {
 	err = longNameSinger.Validate()
 	assert.ErrorIs(t, err, model.ErrInvalidParam)
 }
+
+func TestSinger_Validate_FieldErrors(t *testing.T) {
+	singer := model.Singer{Name: ""}
+	err := singer.Validate()
+
+	var validationErr *model.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []model.FieldError{
+		{Field: "name", Code: model.CodeRequired, Message: "name is required"},
+	}, validationErr.Fields)
+	assert.Equal(t, model.KindValidation, model.KindOf(err))
+}
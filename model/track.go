@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type TrackID int
+
+type Track struct {
+	ID              TrackID `json:"id"`
+	AlbumID         AlbumID `json:"album_id"`
+	DiscNumber      int     `json:"disc_number"`
+	TrackNumber     int     `json:"track_number"`
+	Title           string  `json:"title"`
+	DurationSeconds int     `json:"duration_seconds"`
+	ISRC            string  `json:"isrc"`
+	Explicit        bool    `json:"explicit"`
+}
+
+// isrcPattern is the ISO 3901 layout: country, registrant, year of reference and designation code.
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{2}[0-9]{5}$`)
+
+func (t *Track) Validate() error {
+	v := &ValidationError{}
+	if t.Title == "" {
+		v.Add("title", CodeRequired, "title is required")
+	}
+	if len(t.Title) > 255 {
+		v.Add("title", CodeMaxLength, "title must be at most 255 characters")
+	}
+	if t.DiscNumber < 1 {
+		v.Add("disc_number", CodeOutOfRange, "disc_number must be at least 1")
+	}
+	if t.TrackNumber < 1 {
+		v.Add("track_number", CodeOutOfRange, "track_number must be at least 1")
+	}
+	if t.DurationSeconds < 1 {
+		v.Add("duration_seconds", CodeOutOfRange, "duration_seconds must be at least 1")
+	}
+	if t.ISRC != "" && !isrcPattern.MatchString(t.ISRC) {
+		v.Add("isrc", CodeFormat, "isrc must look like CCXXXYYNNNNN")
+	}
+	return v.Err()
+}
+
+// TrackPosition places a track on an album.
+type TrackPosition struct {
+	TrackID     TrackID
+	DiscNumber  int
+	TrackNumber int
+}
+
+// ValidateTrackPositions reports tracks of one album that share a disc and track number.
+func ValidateTrackPositions(tracks []*Track) error {
+	v := &ValidationError{}
+	seen := make(map[[2]int]TrackID, len(tracks))
+	for _, t := range tracks {
+		key := [2]int{t.DiscNumber, t.TrackNumber}
+		if other, ok := seen[key]; ok {
+			v.Add("track_number", CodeDuplicate,
+				fmt.Sprintf("disc %d track %d is used by both track %d and track %d", t.DiscNumber, t.TrackNumber, other, t.ID))
+			continue
+		}
+		seen[key] = t.ID
+	}
+	return v.Err()
+}
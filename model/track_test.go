@@ -0,0 +1,66 @@
+package model_test
+
+import (
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTrack_Validate(t *testing.T) {
+	valid := model.Track{DiscNumber: 1, TrackNumber: 1, Title: "Opening", DurationSeconds: 95, ISRC: "JPABC2400001"}
+	assert.NoError(t, valid.Validate())
+
+	noISRC := valid
+	noISRC.ISRC = ""
+	assert.NoError(t, noISRC.Validate())
+
+	tests := []struct {
+		name  string
+		track model.Track
+		field string
+		code  string
+	}{
+		{"empty title", model.Track{DiscNumber: 1, TrackNumber: 1, DurationSeconds: 1}, "title", model.CodeRequired},
+		{"long title", model.Track{DiscNumber: 1, TrackNumber: 1, DurationSeconds: 1, Title: strings.Repeat("a", 256)}, "title", model.CodeMaxLength},
+		{"disc zero", model.Track{TrackNumber: 1, DurationSeconds: 1, Title: "t"}, "disc_number", model.CodeOutOfRange},
+		{"track zero", model.Track{DiscNumber: 1, DurationSeconds: 1, Title: "t"}, "track_number", model.CodeOutOfRange},
+		{"no duration", model.Track{DiscNumber: 1, TrackNumber: 1, Title: "t"}, "duration_seconds", model.CodeOutOfRange},
+		{"short isrc", model.Track{DiscNumber: 1, TrackNumber: 1, DurationSeconds: 1, Title: "t", ISRC: "JPABC24"}, "isrc", model.CodeFormat},
+		{"lowercase isrc", model.Track{DiscNumber: 1, TrackNumber: 1, DurationSeconds: 1, Title: "t", ISRC: "jpabc2400001"}, "isrc", model.CodeFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.track.Validate()
+
+			var validationErr *model.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Fields[0].Field)
+			assert.Equal(t, tt.code, validationErr.Fields[0].Code)
+		})
+	}
+}
+
+func TestValidateTrackPositions(t *testing.T) {
+	tracks := []*model.Track{
+		{ID: 1, DiscNumber: 1, TrackNumber: 1},
+		{ID: 2, DiscNumber: 1, TrackNumber: 2},
+		{ID: 3, DiscNumber: 2, TrackNumber: 1},
+	}
+	assert.NoError(t, model.ValidateTrackPositions(tracks))
+
+	tracks = append(tracks, &model.Track{ID: 4, DiscNumber: 1, TrackNumber: 2})
+	err := model.ValidateTrackPositions(tracks)
+
+	var validationErr *model.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []model.FieldError{
+		{Field: "track_number", Code: model.CodeDuplicate, Message: "disc 1 track 2 is used by both track 2 and track 4"},
+	}, validationErr.Fields)
+}
+
+func TestAlbum_TotalDuration(t *testing.T) {
+	album := model.Album{Tracks: []*model.Track{{DurationSeconds: 95}, {DurationSeconds: 240}}}
+	assert.Equal(t, 335, album.TotalDuration())
+	assert.Equal(t, 0, (&model.Album{}).TotalDuration())
+}
@@ -0,0 +1,7 @@
+package model
+
+// Trash holds the soft-deleted singers and albums that have not been purged yet.
+type Trash struct {
+	Singers []*Singer
+	Albums  []*Album
+}
@@ -0,0 +1,52 @@
+package model
+
+import "strings"
+
+// Machine-readable codes describing why a field is invalid.
+const (
+	CodeRequired     = "required"
+	CodeMaxLength    = "max_length"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+	CodeInvalid      = "invalid"
+	CodeReadOnly     = "read_only"
+	CodeDuplicate    = "duplicate"
+	CodeFormat       = "invalid_format"
+	CodeOutOfRange   = "out_of_range"
+)
+
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// ValidationError collects every invalid field found while validating a value.
+// It matches ErrInvalidParam so callers checking the sentinel keep working.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err returns nil when no field was reported, so Validate methods can return it directly.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Code
+	}
+	return ErrInvalidParam.Error() + ": " + strings.Join(messages, ", ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidParam
+}
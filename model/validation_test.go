@@ -0,0 +1,20 @@
+package model_test
+
+import (
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	v := &model.ValidationError{}
+	assert.NoError(t, v.Err())
+
+	v.Add("title", model.CodeRequired, "title is required")
+	v.Add("extra", model.CodeUnknownField, "unknown field")
+
+	err := v.Err()
+	assert.ErrorIs(t, err, model.ErrInvalidParam)
+	assert.Equal(t, "invalid param: title: required, extra: unknown_field", err.Error())
+	assert.Len(t, v.Fields, 2)
+}
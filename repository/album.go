@@ -4,15 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
-	"log/slog"
+	"time"
 )
 
 type AlbumRepository interface {
-	GetAll(ctx context.Context) ([]*model.Album, error)
+	GetAll(ctx context.Context, q *model.AlbumQuery) (*model.Page[*model.Album], error)
+	Export(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) error
 	Get(ctx context.Context, id model.AlbumID) (*model.Album, error)
+	GetBySingerAndTitle(ctx context.Context, singerID model.SingerID, title string) (*model.Album, error)
+	GetBySinger(ctx context.Context, singerID model.SingerID) ([]*model.SingerAlbum, error)
+	GetIDsBySinger(ctx context.Context, singerID model.SingerID) ([]model.AlbumID, error)
 	Add(ctx context.Context, album *model.Album) error
-	Delete(ctx context.Context, id model.AlbumID) error
+	// AddIfAbsent adds album unless the singer already has a live album with its title, in
+	// which case album is filled in from that one. It reports whether album was added.
+	AddIfAbsent(ctx context.Context, album *model.Album) (bool, error)
+	AddBatch(ctx context.Context, albums []*model.Album) error
+	Update(ctx context.Context, album *model.Album) error
+	Delete(ctx context.Context, id model.AlbumID, version int64) error
+	Restore(ctx context.Context, id model.AlbumID) error
+	GetDeleted(ctx context.Context) ([]*model.Album, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 type albumRepository struct {
 	db *sql.DB
@@ -26,20 +40,42 @@ func NewAlbumRepository(db *sql.DB) AlbumRepository {
 	}
 }
 
-func (r *albumRepository) GetAll(ctx context.Context) ([]*model.Album, error) {
-	query := `
-		SELECT a.id, a.title, a.singer_id, s.name
+var albumSortColumns = map[model.AlbumSortField]string{
+	model.AlbumSortID:        "a.id",
+	model.AlbumSortTitle:     "a.title",
+	model.AlbumSortCreatedAt: "a.created_at",
+}
+
+func (r *albumRepository) GetAll(ctx context.Context, q *model.AlbumQuery) (*model.Page[*model.Album], error) {
+	column, ok := albumSortColumns[q.Sort]
+	if !ok {
+		return nil, model.NewError(model.KindValidation, "unsupported sort field", nil)
+	}
+
+	lq := albumFilters(q)
+	if q.Cursor != nil {
+		value, err := cursorValue(q.Cursor, q.Sort == model.AlbumSortCreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		lq.after(column, "a.id", q.Cursor, value)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.title, a.singer_id, s.name, a.created_at
 		FROM albums a
 		JOIN singers s ON a.singer_id = s.id
-		ORDER BY a.id 
-	`
-	rows, err := r.db.QueryContext(ctx, query)
+		%s
+		%s
+		LIMIT ?
+	`, lq.whereClause(), orderBy(column, "a.id", q.Desc))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(lq.args, q.Limit+1)...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, nil)
 	}
 	defer func() {
 		if err = rows.Close(); err != nil {
-			slog.Error("failed to close rows", "error", err)
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
 		}
 	}()
 
@@ -47,8 +83,8 @@ func (r *albumRepository) GetAll(ctx context.Context) ([]*model.Album, error) {
 	for rows.Next() {
 		album := model.Album{}
 		singer := model.Singer{}
-		if err = rows.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name); err != nil {
-			return nil, err
+		if err = rows.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name, &album.CreatedAt); err != nil {
+			return nil, translateError(err, nil)
 		}
 		singer.ID = album.SingerID
 		album.Singer = &singer
@@ -56,27 +92,103 @@ func (r *albumRepository) GetAll(ctx context.Context) ([]*model.Album, error) {
 		albums = append(albums, &album)
 	}
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err, nil)
 	}
-	return albums, nil
+
+	page := &model.Page[*model.Album]{Items: albums}
+	if len(albums) > q.Limit {
+		page.Items = albums[:q.Limit]
+		last := page.Items[q.Limit-1]
+		page.NextCursor = &model.Cursor{Sort: string(q.Sort), Desc: q.Desc, Value: albumSortValue(last, q.Sort), ID: int(last.ID)}
+	}
+	return page, nil
+}
+
+// albumFilters holds the conditions shared by the listing and the export.
+func albumFilters(q *model.AlbumQuery) *listQuery {
+	lq := &listQuery{}
+	lq.where("a.deleted_at IS NULL")
+	if q.SingerID != nil {
+		lq.where("a.singer_id = ?", *q.SingerID)
+	}
+	if q.TitlePrefix != "" {
+		lq.where("a.title LIKE ?", escapeLike(q.TitlePrefix)+"%")
+	}
+	if q.CreatedAfter != nil {
+		lq.where("a.created_at > ?", *q.CreatedAfter)
+	}
+	return lq
+}
+
+// Export works like singerRepository.Export.
+func (r *albumRepository) Export(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) error {
+	column, ok := albumSortColumns[q.Sort]
+	if !ok {
+		return model.NewError(model.KindValidation, "unsupported sort field", nil)
+	}
+
+	lq := albumFilters(q)
+	query := fmt.Sprintf(`
+		SELECT a.id, a.title, a.singer_id, s.name, a.created_at, a.version
+		FROM albums a
+		JOIN singers s ON a.singer_id = s.id
+		%s
+		%s
+	`, lq.whereClause(), orderBy(column, "a.id", q.Desc))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, lq.args...)
+	if err != nil {
+		return translateError(err, nil)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		album := model.Album{}
+		singer := model.Singer{}
+		if err = rows.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name, &album.CreatedAt, &album.Version); err != nil {
+			return translateError(err, nil)
+		}
+		singer.ID = album.SingerID
+		album.Singer = &singer
+		if err = fn(&album); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+func albumSortValue(album *model.Album, sort model.AlbumSortField) string {
+	switch sort {
+	case model.AlbumSortTitle:
+		return album.Title
+	case model.AlbumSortCreatedAt:
+		return album.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return ""
 }
 
 func (r *albumRepository) Get(ctx context.Context, id model.AlbumID) (*model.Album, error) {
 	query := `
-		SELECT a.id, a.title, a.singer_id, s.name
+		SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version
 		FROM albums a
 		JOIN singers s ON a.singer_id = s.id
-		WHERE a.id = ?
+		WHERE a.id = ? AND a.deleted_at IS NULL
 	`
 
 	album := model.Album{}
 	singer := model.Singer{}
-	row := r.db.QueryRowContext(ctx, query, id)
-	if err := row.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name); err != nil {
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, id)
+	if err := row.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name, &singer.Version, &album.CreatedAt, &album.Version); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrorAlbumNotFound
 		}
-		return nil, err
+		return nil, translateError(err, nil)
 	}
 
 	singer.ID = album.SingerID
@@ -85,29 +197,423 @@ func (r *albumRepository) Get(ctx context.Context, id model.AlbumID) (*model.Alb
 	return &album, nil
 }
 
+// GetBySingerAndTitle finds a live album by its natural key.
+func (r *albumRepository) GetBySingerAndTitle(ctx context.Context, singerID model.SingerID, title string) (*model.Album, error) {
+	query := `
+		SELECT a.id, a.title, a.singer_id, s.name, a.created_at, a.version
+		FROM albums a
+		JOIN singers s ON a.singer_id = s.id
+		WHERE a.singer_id = ? AND a.title = ? AND a.deleted_at IS NULL
+	`
+
+	album := model.Album{}
+	singer := model.Singer{}
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, singerID, title)
+	if err := row.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name, &album.CreatedAt, &album.Version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorAlbumNotFound
+		}
+		return nil, translateError(err, nil)
+	}
+
+	singer.ID = album.SingerID
+	album.Singer = &singer
+
+	return &album, nil
+}
+
+// GetBySinger lists every album the singer is credited on, as the primary artist through
+// albums.singer_id or in any other role through album_credits, oldest first.
+func (r *albumRepository) GetBySinger(ctx context.Context, singerID model.SingerID) ([]*model.SingerAlbum, error) {
+	query := `
+		SELECT a.id, a.title, a.singer_id, s.name, a.created_at, c.role
+		FROM (
+			SELECT id AS album_id, 'primary' AS role, 0 AS position FROM albums WHERE singer_id = ?
+			UNION ALL
+			SELECT album_id, role, position FROM album_credits WHERE singer_id = ?
+		) c
+		JOIN albums a ON c.album_id = a.id
+		JOIN singers s ON a.singer_id = s.id
+		WHERE a.deleted_at IS NULL
+		ORDER BY a.created_at, a.id, c.position
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, singerID, singerID)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	albums := make([]*model.SingerAlbum, 0)
+	for rows.Next() {
+		album := model.Album{}
+		singer := model.Singer{}
+		var role model.CreditRole
+		if err = rows.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name, &album.CreatedAt, &role); err != nil {
+			return nil, translateError(err, nil)
+		}
+		// Rows of the same album are adjacent, one per role the singer holds on it.
+		if n := len(albums); n > 0 && albums[n-1].Album.ID == album.ID {
+			albums[n-1].Roles = append(albums[n-1].Roles, role)
+			continue
+		}
+		singer.ID = album.SingerID
+		album.Singer = &singer
+		albums = append(albums, &model.SingerAlbum{Album: &album, Roles: []model.CreditRole{role}})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+	return albums, nil
+}
+
+// GetIDsBySinger lists the live albums the singer is the primary artist of.
+func (r *albumRepository) GetIDsBySinger(ctx context.Context, singerID model.SingerID) ([]model.AlbumID, error) {
+	query := `SELECT id FROM albums WHERE singer_id = ? AND deleted_at IS NULL ORDER BY id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, singerID)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	ids := make([]model.AlbumID, 0)
+	for rows.Next() {
+		var id model.AlbumID
+		if err = rows.Scan(&id); err != nil {
+			return nil, translateError(err, nil)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+	return ids, nil
+}
+
+// Add inserts album. When album.ID is zero the database allocates the id and it is written back to album.
+// The row is selected from the live singers, so a trashed singer is reported like a missing one
+// instead of slipping past the foreign key.
 func (r *albumRepository) Add(ctx context.Context, album *model.Album) error {
-	query := `INSERT INTO albums (id, title, singer_id) VALUES (?, ?, ?)`
-	if _, err := r.db.ExecContext(ctx, query, album.ID, album.Title, album.SingerID); err != nil {
+	query := `INSERT INTO albums (title, singer_id) SELECT ?, id FROM singers WHERE id = ? AND deleted_at IS NULL`
+	args := []any{album.Title, album.SingerID}
+	if album.ID != 0 {
+		query = `INSERT INTO albums (id, title, singer_id) SELECT ?, ?, id FROM singers WHERE id = ? AND deleted_at IS NULL`
+		args = []any{album.ID, album.Title, album.SingerID}
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateAlbumInsertError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+	if rowsAffected == 0 {
+		return ErrorReferencedSingerNotFound
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return translateError(err, nil)
+	}
+	album.ID = model.AlbumID(id)
+	album.Version = 1
+	return nil
+}
+
+// AddIfAbsent settles races the way SingerRepository.AddIfAbsent does, with the unique
+// title key.
+func (r *albumRepository) AddIfAbsent(ctx context.Context, album *model.Album) (bool, error) {
+	err := r.Add(ctx, album)
+	if !errors.Is(err, ErrorAlbumTitleTaken) {
+		return err == nil, err
+	}
+
+	query := `SELECT id, created_at, version FROM albums WHERE singer_id = ? AND title = ? AND deleted_at IS NULL FOR SHARE`
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, album.SingerID, album.Title)
+	if scanErr := row.Scan(&album.ID, &album.CreatedAt, &album.Version); scanErr != nil {
+		if errors.Is(scanErr, sql.ErrNoRows) {
+			// Trashed again in the meantime.
+			return false, err
+		}
+		return false, translateError(scanErr, nil)
+	}
+	return false, nil
+}
+
+// AddBatch inserts albums with multi-row INSERT statements, one for the albums with an id
+// and one for those without. Any failure fails the whole statement, so callers that need to
+// know which album was rejected retry them one by one with Add. Run it within
+// Transactor.WithinTx so the generated ids are read back from rows no one else can change yet.
+func (r *albumRepository) AddBatch(ctx context.Context, albums []*model.Album) error {
+	if len(albums) == 0 {
+		return nil
+	}
+	if err := r.checkLiveSingers(ctx, albums); err != nil {
 		return err
 	}
+
+	withID, withoutID := splitAlbumsByID(albums)
+	if len(withID) > 0 {
+		args := make([]any, 0, len(withID)*3)
+		for _, album := range withID {
+			args = append(args, album.ID, album.Title, album.SingerID)
+		}
+		query := `INSERT INTO albums (id, title, singer_id) VALUES ` + placeholders(len(withID), 3)
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+			return translateAlbumInsertError(err)
+		}
+	}
+	if len(withoutID) > 0 {
+		args := make([]any, 0, len(withoutID)*2)
+		for _, album := range withoutID {
+			args = append(args, album.Title, album.SingerID)
+		}
+		query := `INSERT INTO albums (title, singer_id) VALUES ` + placeholders(len(withoutID), 2)
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+			return translateAlbumInsertError(err)
+		}
+		if err := r.readBackIDs(ctx, withoutID); err != nil {
+			return err
+		}
+	}
+
+	for _, album := range albums {
+		album.Version = 1
+	}
 	return nil
 }
 
-func (r *albumRepository) Delete(ctx context.Context, id model.AlbumID) error {
-	query := `DELETE FROM albums WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+type albumKey struct {
+	singerID model.SingerID
+	title    string
+}
+
+// readBackIDs fills in the ids generated for albums by their live singer and title, which the
+// unique key makes unambiguous. A multi-row INSERT need not generate consecutive ids, e.g.
+// with innodb_autoinc_lock_mode=2 or an auto_increment_increment other than 1.
+func (r *albumRepository) readBackIDs(ctx context.Context, albums []*model.Album) error {
+	args := make([]any, 0, len(albums)*2)
+	for _, album := range albums {
+		args = append(args, album.SingerID, album.Title)
+	}
+	query := `SELECT id, singer_id, title FROM albums WHERE (singer_id, title) IN (` + placeholders(len(albums), 2) + `) AND deleted_at IS NULL`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	ids := make(map[albumKey]model.AlbumID, len(albums))
+	for rows.Next() {
+		var id model.AlbumID
+		var key albumKey
+		if err = rows.Scan(&id, &key.singerID, &key.title); err != nil {
+			return translateError(err, nil)
+		}
+		ids[key] = id
+	}
+	if err = rows.Err(); err != nil {
+		return translateError(err, nil)
+	}
+
+	for _, album := range albums {
+		id, ok := ids[albumKey{singerID: album.SingerID, title: album.Title}]
+		if !ok {
+			return model.NewError(model.KindInternal, "inserted album not found", nil)
+		}
+		album.ID = id
+	}
+	return nil
+}
+
+// checkLiveSingers fails with ErrorReferencedSingerNotFound unless every album's singer is live.
+// The foreign key alone would let albums of a trashed singer through.
+func (r *albumRepository) checkLiveSingers(ctx context.Context, albums []*model.Album) error {
+	seen := make(map[model.SingerID]bool)
+	args := make([]any, 0, len(albums))
+	for _, album := range albums {
+		if !seen[album.SingerID] {
+			seen[album.SingerID] = true
+			args = append(args, album.SingerID)
+		}
+	}
+
+	query := `SELECT COUNT(*) FROM singers WHERE id IN (` + placeholderList(len(args)) + `) AND deleted_at IS NULL`
+	var live int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&live); err != nil {
+		return translateError(err, nil)
+	}
+	if live != len(args) {
+		return ErrorReferencedSingerNotFound
+	}
+	return nil
+}
+
+func splitAlbumsByID(albums []*model.Album) (withID, withoutID []*model.Album) {
+	for _, album := range albums {
+		if album.ID != 0 {
+			withID = append(withID, album)
+		} else {
+			withoutID = append(withoutID, album)
+		}
+	}
+	return withID, withoutID
+}
+
+func translateAlbumInsertError(err error) error {
+	return translateDuplicate(err, albumTitleKey, ErrorAlbumTitleTaken, mysqlErrors{
+		mysqlErrDupEntry:        ErrorAlbumAlreadyExists,
+		mysqlErrNoReferencedRow: ErrorReferencedSingerNotFound,
+	})
+}
+
+// Update writes album if it is still at album.Version and advances the version.
+func (r *albumRepository) Update(ctx context.Context, album *model.Album) error {
+	query := `
+		UPDATE albums SET title = ?, singer_id = ?, version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, album.Title, album.SingerID, album.ID, album.Version, album.SingerID)
+	if err != nil {
+		return translateDuplicate(err, albumTitleKey, ErrorAlbumTitleTaken, mysqlErrors{mysqlErrNoReferencedRow: ErrorReferencedSingerNotFound})
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return translateError(err, nil)
+	}
+
+	if rowsAffected == 0 {
+		current, err := r.Get(ctx, album.ID)
+		if err != nil {
+			return err
+		}
+		if current.Version != album.Version {
+			return ErrorAlbumVersionMismatch
+		}
+		return ErrorReferencedSingerNotFound
+	}
+
+	album.Version++
+	return nil
+}
+
+// Delete moves the album to the trash; its tracks and credits stay until it is purged.
+func (r *albumRepository) Delete(ctx context.Context, id model.AlbumID, version int64) error {
+	query := `UPDATE albums SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, version)
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	if rowsAffected == 0 {
+		if _, err = r.Get(ctx, id); err != nil {
+			return err
+		}
+		return ErrorAlbumVersionMismatch
+	}
+
+	return nil
+}
+
+// Restore takes the album out of the trash. It stays there while its singer is trashed.
+func (r *albumRepository) Restore(ctx context.Context, id model.AlbumID) error {
+	query := `
+		UPDATE albums SET deleted_at = NULL, version = version + 1
+		WHERE id = ? AND deleted_at IS NOT NULL
+			AND singer_id IN (SELECT id FROM singers WHERE deleted_at IS NULL)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return translateDuplicate(err, albumTitleKey, ErrorAlbumTitleTaken, nil)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
 	}
 
 	if rowsAffected == 0 {
-		return ErrorAlbumNotFound
+		query = `SELECT EXISTS (SELECT 1 FROM albums WHERE id = ? AND deleted_at IS NOT NULL)`
+		var trashed bool
+		if err = conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&trashed); err != nil {
+			return translateError(err, nil)
+		}
+		if trashed {
+			return ErrorReferencedSingerNotFound
+		}
+		return ErrorAlbumNotInTrash
 	}
 
 	return nil
 }
+
+func (r *albumRepository) GetDeleted(ctx context.Context) ([]*model.Album, error) {
+	query := `
+		SELECT a.id, a.title, a.singer_id, s.name, a.created_at, a.deleted_at
+		FROM albums a
+		JOIN singers s ON a.singer_id = s.id
+		WHERE a.deleted_at IS NOT NULL
+		ORDER BY a.deleted_at DESC, a.id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	albums := make([]*model.Album, 0)
+	for rows.Next() {
+		album := model.Album{}
+		singer := model.Singer{}
+		if err = rows.Scan(&album.ID, &album.Title, &album.SingerID, &singer.Name, &album.CreatedAt, &album.DeletedAt); err != nil {
+			return nil, translateError(err, nil)
+		}
+		singer.ID = album.SingerID
+		album.Singer = &singer
+		albums = append(albums, &album)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+	return albums, nil
+}
+
+// Purge hard-deletes albums trashed before deletedBefore, together with their tracks and credits.
+func (r *albumRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `DELETE FROM albums WHERE deleted_at < ?`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+	return purged, nil
+}
@@ -3,12 +3,15 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
 	"github.com/pulse227/server-recruit-challenge-sample/repository"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type AlbumRepositorySuite struct {
@@ -50,7 +53,7 @@ func (suite *AlbumRepositorySuite) TestAlbumRepositoryAdd() {
 		},
 	}
 	mock := suite.MockDB()
-	mock.ExpectExec("INSERT INTO albums (id, title, singer_id) VALUES (?, ?, ?)").
+	mock.ExpectExec("INSERT INTO albums (id, title, singer_id) SELECT ?, ?, id FROM singers WHERE id = ? AND deleted_at IS NULL").
 		WithArgs(album.ID, album.Title, album.SingerID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -95,18 +98,21 @@ func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetAll() {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "singer_id", "name"})
+	rows := sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at"})
 	for _, album := range albums {
-		rows.AddRow(album.ID, album.Title, album.SingerID, album.Singer.Name)
+		rows.AddRow(album.ID, album.Title, album.SingerID, album.Singer.Name, album.CreatedAt)
 	}
 	mock := suite.MockDB()
 	mock.ExpectQuery(
-		"SELECT a.id, a.title, a.singer_id, s.name FROM albums a JOIN singers s ON a.singer_id = s.id ORDER BY a.id",
-	).WillReturnRows(rows)
+		"SELECT a.id, a.title, a.singer_id, s.name, a.created_at FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.deleted_at IS NULL ORDER BY a.id ASC LIMIT ?",
+	).WithArgs(model.DefaultPageLimit + 1).WillReturnRows(rows)
 
-	result, err := suite.albumRepository.GetAll(ctx)
+	q := &model.AlbumQuery{Limit: model.DefaultPageLimit, Sort: model.AlbumSortID}
+	page, err := suite.albumRepository.GetAll(ctx, q)
 	suite.NoError(err)
+	result := page.Items
 	suite.Len(result, len(albums))
+	suite.Nil(page.NextCursor)
 	for i, album := range albums {
 		suite.Equal(album.ID, result[i].ID)
 		suite.Equal(album.Title, result[i].Title)
@@ -132,12 +138,12 @@ func (suite *AlbumRepositorySuite) TestAlbumRepositoryGet() {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "singer_id", "name"}).
-		AddRow(album.ID, album.Title, album.SingerID, album.Singer.Name)
+	rows := sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "singer_version", "created_at", "version"}).
+		AddRow(album.ID, album.Title, album.SingerID, album.Singer.Name, 5, album.CreatedAt, 3)
 
 	mock := suite.MockDB()
 	mock.ExpectQuery(
-		"SELECT a.id, a.title, a.singer_id, s.name FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ?",
+		"SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ? AND a.deleted_at IS NULL",
 	).WithArgs(album.ID).WillReturnRows(rows)
 
 	result, err := suite.albumRepository.Get(ctx, album.ID)
@@ -149,6 +155,8 @@ func (suite *AlbumRepositorySuite) TestAlbumRepositoryGet() {
 	suite.Equal(album.SingerID, result.SingerID)
 	suite.Equal(album.Singer.ID, result.Singer.ID)
 	suite.Equal(album.Singer.Name, result.Singer.Name)
+	suite.Equal(int64(5), result.Singer.Version)
+	suite.Equal(int64(3), result.Version)
 
 	err = mock.ExpectationsWereMet()
 	suite.NoError(err)
@@ -160,15 +168,15 @@ func (suite *AlbumRepositorySuite) TestAlbumRepositoryDelete() {
 
 	mock := suite.MockDB()
 
-	mock.ExpectExec("DELETE FROM albums WHERE id = ?").
-		WithArgs(albumID).
+	mock.ExpectExec("UPDATE albums SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL").
+		WithArgs(albumID, int64(2)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err := suite.albumRepository.Delete(ctx, albumID)
+	err := suite.albumRepository.Delete(ctx, albumID, 2)
 	suite.NoError(err)
 
 	mock.ExpectQuery(
-		"SELECT a.id, a.title, a.singer_id, s.name FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ?",
+		"SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ? AND a.deleted_at IS NULL",
 	).WithArgs(albumID).
 		WillReturnError(sql.ErrNoRows)
 
@@ -182,3 +190,526 @@ func (suite *AlbumRepositorySuite) TestAlbumRepositoryDelete() {
 	suite.NoError(err)
 
 }
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryUpdate() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "Updated Album",
+		SingerID: model.SingerID(2),
+		Version:  4,
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"UPDATE albums SET title = ?, singer_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)",
+	).WithArgs(album.Title, album.SingerID, album.ID, album.Version, album.SingerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.albumRepository.Update(ctx, album)
+	suite.NoError(err)
+	suite.Equal(int64(5), album.Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryUpdate_NotFound() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(99),
+		Title:    "Missing Album",
+		SingerID: model.SingerID(1),
+		Version:  1,
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"UPDATE albums SET title = ?, singer_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)",
+	).WithArgs(album.Title, album.SingerID, album.ID, album.Version, album.SingerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ? AND a.deleted_at IS NULL",
+	).WithArgs(album.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	err := suite.albumRepository.Update(ctx, album)
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryUpdate_SingerNotFound() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "First Album",
+		SingerID: model.SingerID(99),
+		Version:  2,
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"UPDATE albums SET title = ?, singer_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)",
+	).WithArgs(album.Title, album.SingerID, album.ID, album.Version, album.SingerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ? AND a.deleted_at IS NULL",
+	).WithArgs(album.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "singer_version", "created_at", "version"}).
+			AddRow(1, "First Album", 1, "Alice", 1, time.Now(), 2))
+
+	err := suite.albumRepository.Update(ctx, album)
+	suite.ErrorIs(err, repository.ErrorReferencedSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryUpdate_VersionMismatch() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "Stale Title",
+		SingerID: model.SingerID(1),
+		Version:  2,
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"UPDATE albums SET title = ?, singer_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)",
+	).WithArgs(album.Title, album.SingerID, album.ID, album.Version, album.SingerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ? AND a.deleted_at IS NULL",
+	).WithArgs(album.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "singer_version", "created_at", "version"}).
+			AddRow(1, "First Album", 1, "Alice", 1, time.Now(), 3))
+
+	err := suite.albumRepository.Update(ctx, album)
+	suite.ErrorIs(err, repository.ErrorAlbumVersionMismatch)
+	suite.Equal(model.KindPreconditionFailed, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryDelete_VersionMismatch() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE albums SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL").
+		WithArgs(model.AlbumID(1), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, s.version, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.id = ? AND a.deleted_at IS NULL",
+	).WithArgs(model.AlbumID(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "singer_version", "created_at", "version"}).
+			AddRow(1, "First Album", 1, "Alice", 1, time.Now(), 2))
+
+	err := suite.albumRepository.Delete(ctx, model.AlbumID(1), 1)
+	suite.ErrorIs(err, repository.ErrorAlbumVersionMismatch)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAdd_Duplicate() {
+	ctx := context.Background()
+
+	album := &model.Album{ID: model.AlbumID(1), Title: "Test Album", SingerID: model.SingerID(1)}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO albums (id, title, singer_id) SELECT ?, ?, id FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(album.ID, album.Title, album.SingerID).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'albums.PRIMARY'"})
+
+	err := suite.albumRepository.Add(ctx, album)
+	suite.ErrorIs(err, repository.ErrorAlbumAlreadyExists)
+	suite.Equal(model.KindConflict, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAdd_TitleTaken() {
+	ctx := context.Background()
+
+	album := &model.Album{Title: "Test Album", SingerID: model.SingerID(1)}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO albums (title, singer_id) SELECT ?, id FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(album.Title, album.SingerID).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-Test Album-1' for key 'albums.uq_albums_singer_title'"})
+
+	err := suite.albumRepository.Add(ctx, album)
+	suite.ErrorIs(err, repository.ErrorAlbumTitleTaken)
+	suite.Equal(model.KindConflict, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAddIfAbsent() {
+	ctx := context.Background()
+
+	album := &model.Album{Title: "Test Album", SingerID: model.SingerID(1)}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO albums (title, singer_id) SELECT ?, id FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(album.Title, album.SingerID).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-Test Album-1' for key 'albums.uq_albums_singer_title'"})
+	mock.ExpectQuery("SELECT id, created_at, version FROM albums WHERE singer_id = ? AND title = ? AND deleted_at IS NULL FOR SHARE").
+		WithArgs(album.SingerID, album.Title).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "version"}).AddRow(5, time.Now(), 2))
+
+	added, err := suite.albumRepository.AddIfAbsent(ctx, album)
+	suite.NoError(err)
+	suite.False(added)
+	suite.Equal(model.AlbumID(5), album.ID)
+	suite.Equal(int64(2), album.Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetAll_ConnectionError() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, a.created_at FROM albums a JOIN singers s ON a.singer_id = s.id WHERE a.deleted_at IS NULL ORDER BY a.id ASC LIMIT ?",
+	).WillReturnError(mysql.ErrInvalidConn)
+
+	q := &model.AlbumQuery{Limit: model.DefaultPageLimit, Sort: model.AlbumSortID}
+	result, err := suite.albumRepository.GetAll(ctx, q)
+	suite.Nil(result)
+	suite.Equal(model.KindUnavailable, model.KindOf(err))
+	suite.ErrorIs(err, mysql.ErrInvalidConn)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAdd_GeneratedID() {
+	ctx := context.Background()
+
+	album := &model.Album{Title: "Test Album", SingerID: model.SingerID(1)}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO albums (title, singer_id) SELECT ?, id FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(album.Title, album.SingerID).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	err := suite.albumRepository.Add(ctx, album)
+	suite.NoError(err)
+	suite.Equal(model.AlbumID(42), album.ID)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetAll_FilteredPage() {
+	ctx := context.Background()
+
+	createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	singerID := model.SingerID(1)
+	cursor := &model.Cursor{Sort: "title", Desc: true, Value: "M", ID: 5}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at"}).
+		AddRow(4, "Love_Song", 1, "Alice", createdAfter.Add(time.Hour)).
+		AddRow(2, "Love 100%", 1, "Alice", createdAfter.Add(2*time.Hour))
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, a.created_at FROM albums a JOIN singers s ON a.singer_id = s.id "+
+			"WHERE a.deleted_at IS NULL AND a.singer_id = ? AND a.title LIKE ? AND a.created_at > ? AND (a.title < ? OR (a.title = ? AND a.id < ?)) "+
+			"ORDER BY a.title DESC, a.id DESC LIMIT ?",
+	).WithArgs(singerID, `Lo\_%`, createdAfter, "M", "M", 5, 2).WillReturnRows(rows)
+
+	q := &model.AlbumQuery{
+		Limit:        1,
+		Cursor:       cursor,
+		Sort:         model.AlbumSortTitle,
+		Desc:         true,
+		SingerID:     &singerID,
+		TitlePrefix:  "Lo_",
+		CreatedAfter: &createdAfter,
+	}
+	page, err := suite.albumRepository.GetAll(ctx, q)
+	suite.NoError(err)
+	suite.Len(page.Items, 1)
+	suite.Equal(model.AlbumID(4), page.Items[0].ID)
+	suite.Equal(&model.Cursor{Sort: "title", Desc: true, Value: "Love_Song", ID: 4}, page.NextCursor)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryExport() {
+	ctx := context.Background()
+
+	singerID := model.SingerID(1)
+	rows := sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at", "version"}).
+		AddRow(3, "Zeta", 1, "Alice", time.Now(), 1).
+		AddRow(1, "Alpha", 1, "Alice", time.Now(), 2).
+		AddRow(2, "Beta", 1, "Alice", time.Now(), 1)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id " +
+			"WHERE a.deleted_at IS NULL AND a.singer_id = ? ORDER BY a.title DESC, a.id DESC",
+	).WithArgs(singerID).WillReturnRows(rows)
+
+	// The callback stops the export by returning an error.
+	stop := errors.New("stop")
+	var exported []model.AlbumID
+	q := &model.AlbumQuery{Sort: model.AlbumSortTitle, Desc: true, SingerID: &singerID}
+	err := suite.albumRepository.Export(ctx, q, func(album *model.Album) error {
+		if len(exported) == 2 {
+			return stop
+		}
+		exported = append(exported, album.ID)
+		suite.Equal("Alice", album.Singer.Name)
+		return nil
+	})
+	suite.ErrorIs(err, stop)
+	suite.Equal([]model.AlbumID{3, 1}, exported)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetBySinger() {
+	ctx := context.Background()
+	singerID := model.SingerID(2)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, a.created_at, c.role FROM ( "+
+			"SELECT id AS album_id, 'primary' AS role, 0 AS position FROM albums WHERE singer_id = ? "+
+			"UNION ALL SELECT album_id, role, position FROM album_credits WHERE singer_id = ? ) c "+
+			"JOIN albums a ON c.album_id = a.id JOIN singers s ON a.singer_id = s.id WHERE a.deleted_at IS NULL ORDER BY a.created_at, a.id, c.position",
+	).WithArgs(singerID, singerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at", "role"}).
+			AddRow(2, "Alice's 2nd Album", 1, "Alice", createdAt, "featured").
+			AddRow(2, "Alice's 2nd Album", 1, "Alice", createdAt, "producer").
+			AddRow(3, "Bella's 1st Album", 2, "Bella", createdAt, "primary"))
+
+	albums, err := suite.albumRepository.GetBySinger(ctx, singerID)
+	suite.NoError(err)
+	suite.Len(albums, 2)
+	suite.Equal(model.AlbumID(2), albums[0].Album.ID)
+	suite.Equal("Alice", albums[0].Album.Singer.Name)
+	suite.Equal([]model.CreditRole{model.CreditRoleFeatured, model.CreditRoleProducer}, albums[0].Roles)
+	suite.Equal([]model.CreditRole{model.CreditRolePrimary}, albums[1].Roles)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetIDsBySinger() {
+	ctx := context.Background()
+	singerID := model.SingerID(1)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id FROM albums WHERE singer_id = ? AND deleted_at IS NULL ORDER BY id").
+		WithArgs(singerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	ids, err := suite.albumRepository.GetIDsBySinger(ctx, singerID)
+	suite.NoError(err)
+	suite.Equal([]model.AlbumID{1, 2}, ids)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAdd_TrashedSinger() {
+	ctx := context.Background()
+
+	album := &model.Album{Title: "Orphan Album", SingerID: model.SingerID(5)}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO albums (title, singer_id) SELECT ?, id FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(album.Title, album.SingerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.albumRepository.Add(ctx, album)
+	suite.ErrorIs(err, repository.ErrorReferencedSingerNotFound)
+	suite.Zero(album.ID)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetBySingerAndTitle() {
+	ctx := context.Background()
+	query := "SELECT a.id, a.title, a.singer_id, s.name, a.created_at, a.version FROM albums a JOIN singers s ON a.singer_id = s.id " +
+		"WHERE a.singer_id = ? AND a.title = ? AND a.deleted_at IS NULL"
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(query).
+		WithArgs(model.SingerID(1), "Known Album").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at", "version"}).
+			AddRow(5, "Known Album", 1, "Alice", time.Now(), 1))
+	mock.ExpectQuery(query).
+		WithArgs(model.SingerID(1), "New Album").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at", "version"}))
+
+	album, err := suite.albumRepository.GetBySingerAndTitle(ctx, model.SingerID(1), "Known Album")
+	suite.NoError(err)
+	suite.Equal(model.AlbumID(5), album.ID)
+	suite.Equal("Alice", album.Singer.Name)
+
+	_, err = suite.albumRepository.GetBySingerAndTitle(ctx, model.SingerID(1), "New Album")
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAddBatch() {
+	ctx := context.Background()
+
+	albums := []*model.Album{
+		{Title: "First", SingerID: model.SingerID(1)},
+		{Title: "Second", SingerID: model.SingerID(2)},
+		{Title: "Third", SingerID: model.SingerID(1)},
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT COUNT(*) FROM singers WHERE id IN (?, ?) AND deleted_at IS NULL").
+		WithArgs(model.SingerID(1), model.SingerID(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectExec("INSERT INTO albums (title, singer_id) VALUES (?, ?), (?, ?), (?, ?)").
+		WithArgs("First", model.SingerID(1), "Second", model.SingerID(2), "Third", model.SingerID(1)).
+		WillReturnResult(sqlmock.NewResult(20, 3))
+	// ids interleaved with another transaction's insert, returned in index order
+	mock.ExpectQuery("SELECT id, singer_id, title FROM albums WHERE (singer_id, title) IN ((?, ?), (?, ?), (?, ?)) AND deleted_at IS NULL").
+		WithArgs(model.SingerID(1), "First", model.SingerID(2), "Second", model.SingerID(1), "Third").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "singer_id", "title"}).
+			AddRow(20, 1, "First").
+			AddRow(23, 1, "Third").
+			AddRow(24, 2, "Second"))
+
+	err := suite.albumRepository.AddBatch(ctx, albums)
+	suite.NoError(err)
+	suite.Equal(model.AlbumID(20), albums[0].ID)
+	suite.Equal(model.AlbumID(24), albums[1].ID)
+	suite.Equal(model.AlbumID(23), albums[2].ID)
+	suite.Equal(int64(1), albums[1].Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryAddBatch_TrashedSinger() {
+	ctx := context.Background()
+
+	albums := []*model.Album{
+		{ID: model.AlbumID(30), Title: "First", SingerID: model.SingerID(1)},
+		{ID: model.AlbumID(31), Title: "Second", SingerID: model.SingerID(5)},
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT COUNT(*) FROM singers WHERE id IN (?, ?) AND deleted_at IS NULL").
+		WithArgs(model.SingerID(1), model.SingerID(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := suite.albumRepository.AddBatch(ctx, albums)
+	suite.ErrorIs(err, repository.ErrorReferencedSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryRestore() {
+	ctx := context.Background()
+
+	restore := "UPDATE albums SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL " +
+		"AND singer_id IN (SELECT id FROM singers WHERE deleted_at IS NULL)"
+	trashed := "SELECT EXISTS (SELECT 1 FROM albums WHERE id = ? AND deleted_at IS NOT NULL)"
+
+	tests := []struct {
+		name     string
+		restored int64
+		trashed  bool
+		expected error
+	}{
+		{name: "restored", restored: 1},
+		{name: "singer trashed", trashed: true, expected: repository.ErrorReferencedSingerNotFound},
+		{name: "not in trash", expected: repository.ErrorAlbumNotInTrash},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			mock := suite.MockDB()
+			mock.ExpectExec(restore).
+				WithArgs(model.AlbumID(3)).
+				WillReturnResult(sqlmock.NewResult(0, tt.restored))
+			if tt.restored == 0 {
+				mock.ExpectQuery(trashed).
+					WithArgs(model.AlbumID(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"trashed"}).AddRow(tt.trashed))
+			}
+
+			err := suite.albumRepository.Restore(ctx, model.AlbumID(3))
+			if tt.expected == nil {
+				suite.NoError(err)
+			} else {
+				suite.ErrorIs(err, tt.expected)
+			}
+
+			err = mock.ExpectationsWereMet()
+			suite.NoError(err)
+		})
+	}
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryGetDeleted() {
+	ctx := context.Background()
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT a.id, a.title, a.singer_id, s.name, a.created_at, a.deleted_at FROM albums a " +
+			"JOIN singers s ON a.singer_id = s.id WHERE a.deleted_at IS NOT NULL ORDER BY a.deleted_at DESC, a.id",
+	).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "singer_id", "name", "created_at", "deleted_at"}).
+		AddRow(3, "Bella's 1st Album", 2, "Bella", deletedAt.AddDate(-1, 0, 0), deletedAt))
+
+	albums, err := suite.albumRepository.GetDeleted(ctx)
+	suite.NoError(err)
+	suite.Len(albums, 1)
+	suite.Equal("Bella", albums[0].Singer.Name)
+	suite.Equal(deletedAt, *albums[0].DeletedAt)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *AlbumRepositorySuite) TestAlbumRepositoryPurge() {
+	ctx := context.Background()
+	before := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectExec("DELETE FROM albums WHERE deleted_at < ?").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	purged, err := suite.albumRepository.Purge(ctx, before)
+	suite.NoError(err)
+	suite.Equal(int64(2), purged)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
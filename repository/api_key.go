@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type APIKeyRepository interface {
+	// GetByHash returns the unrevoked key whose SHA-256 hex digest is hash.
+	GetByHash(ctx context.Context, hash string) (*model.APIKey, error)
+}
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+var _ APIKeyRepository = (*apiKeyRepository)(nil)
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	query := `
+		SELECT id, name, key_hash, role, created_at
+		FROM api_keys
+		WHERE key_hash = ? AND revoked_at IS NULL
+	`
+	key := &model.APIKey{}
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(&key.ID, &key.Name, &key.Hash, &key.Role, &key.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrorAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	return key, nil
+}
@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	apiKeyRepository repository.APIKeyRepository
+}
+
+func TestAPIKeyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositorySuite))
+}
+
+func (suite *APIKeyRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.apiKeyRepository = repository.NewAPIKeyRepository(suite.DB)
+}
+
+func (suite *APIKeyRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.apiKeyRepository = repository.NewAPIKeyRepository(mockDB)
+	return mock
+}
+
+func (suite *APIKeyRepositorySuite) AfterTest() {
+	suite.apiKeyRepository = repository.NewAPIKeyRepository(suite.DB)
+}
+
+func (suite *APIKeyRepositorySuite) TestAPIKeyRepositoryGetByHash() {
+	ctx := context.Background()
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id, name, key_hash, role, created_at FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL").
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key_hash", "role", "created_at"}).
+			AddRow(1, "ci", "abc", "editor", createdAt))
+
+	key, err := suite.apiKeyRepository.GetByHash(ctx, "abc")
+	suite.NoError(err)
+	suite.Equal(&model.APIKey{ID: 1, Name: "ci", Hash: "abc", Role: model.RoleEditor, CreatedAt: createdAt}, key)
+	suite.NoError(mock.ExpectationsWereMet())
+}
+
+func (suite *APIKeyRepositorySuite) TestAPIKeyRepositoryGetByHash_NotFound() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id, name, key_hash, role, created_at FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL").
+		WithArgs("revoked").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key_hash", "role", "created_at"}))
+
+	_, err := suite.apiKeyRepository.GetByHash(ctx, "revoked")
+	suite.ErrorIs(err, repository.ErrorAPIKeyNotFound)
+	suite.NoError(mock.ExpectationsWereMet())
+}
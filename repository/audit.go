@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type AuditRepository interface {
+	// Add records event. Given a context from Transactor.WithinTx it is written in that
+	// transaction, so it commits or rolls back with the change it describes.
+	Add(ctx context.Context, event *model.AuditEvent) error
+	GetAll(ctx context.Context, q *model.AuditQuery) (*model.Page[*model.AuditEvent], error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+var _ AuditRepository = (*auditRepository)(nil)
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Add(ctx context.Context, event *model.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (actor, action, entity_type, entity_id, request_id, before_snapshot, after_snapshot)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		event.Actor, event.Action, event.EntityType, event.EntityID, event.RequestID,
+		jsonArg(event.Before), jsonArg(event.After))
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return translateError(err, nil)
+	}
+	event.ID = id
+	return nil
+}
+
+// jsonArg passes a snapshot as text; MySQL refuses to read JSON from binary strings.
+func jsonArg(raw json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+	return string(raw)
+}
+
+func (r *auditRepository) GetAll(ctx context.Context, q *model.AuditQuery) (*model.Page[*model.AuditEvent], error) {
+	lq := &listQuery{}
+	if q.EntityType != "" {
+		lq.where("entity_type = ? AND entity_id = ?", q.EntityType, q.EntityID)
+	}
+	if q.Actor != "" {
+		lq.where("actor = ?", q.Actor)
+	}
+	if q.Cursor != nil {
+		lq.after("id", "id", q.Cursor, nil)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, actor, action, entity_type, entity_id, request_id, before_snapshot, after_snapshot, created_at
+		FROM audit_events %s %s LIMIT ?
+	`, lq.whereClause(), orderBy("id", "id", true))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(lq.args, q.Limit+1)...)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	events := make([]*model.AuditEvent, 0)
+	for rows.Next() {
+		event := model.AuditEvent{}
+		var before, after []byte
+		if err = rows.Scan(&event.ID, &event.Actor, &event.Action, &event.EntityType, &event.EntityID,
+			&event.RequestID, &before, &after, &event.CreatedAt); err != nil {
+			return nil, translateError(err, nil)
+		}
+		event.Before, event.After = before, after
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+
+	page := &model.Page[*model.AuditEvent]{Items: events}
+	if len(events) > q.Limit {
+		page.Items = events[:q.Limit]
+		last := page.Items[q.Limit-1]
+		page.NextCursor = &model.Cursor{Sort: "id", Desc: true, ID: int(last.ID)}
+	}
+	return page, nil
+}
@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	auditRepository repository.AuditRepository
+}
+
+func TestAuditRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositorySuite))
+}
+
+func (suite *AuditRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.auditRepository = repository.NewAuditRepository(suite.DB)
+}
+
+func (suite *AuditRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.auditRepository = repository.NewAuditRepository(mockDB)
+	return mock
+}
+
+func (suite *AuditRepositorySuite) AfterTest() {
+	suite.auditRepository = repository.NewAuditRepository(suite.DB)
+}
+
+var auditColumns = []string{"id", "actor", "action", "entity_type", "entity_id", "request_id", "before_snapshot", "after_snapshot", "created_at"}
+
+func (suite *AuditRepositorySuite) TestAuditRepositoryAdd() {
+	ctx := context.Background()
+	event := &model.AuditEvent{
+		Actor:      "api_key:ci",
+		Action:     model.AuditDelete,
+		EntityType: model.AuditEntityAlbum,
+		EntityID:   3,
+		RequestID:  "req-1",
+		Before:     json.RawMessage(`{"id":3}`),
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO audit_events (actor, action, entity_type, entity_id, request_id, before_snapshot, after_snapshot) VALUES (?, ?, ?, ?, ?, ?, ?)").
+		WithArgs("api_key:ci", model.AuditDelete, model.AuditEntityAlbum, 3, "req-1", `{"id":3}`, nil).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	err := suite.auditRepository.Add(ctx, event)
+	suite.NoError(err)
+	suite.Equal(int64(12), event.ID)
+	suite.NoError(mock.ExpectationsWereMet())
+}
+
+func (suite *AuditRepositorySuite) TestAuditRepositoryGetAll() {
+	ctx := context.Background()
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id, actor, action, entity_type, entity_id, request_id, before_snapshot, after_snapshot, created_at FROM audit_events WHERE entity_type = ? AND entity_id = ? AND id < ? ORDER BY id DESC LIMIT ?").
+		WithArgs(model.AuditEntityAlbum, 3, 10, 2).
+		WillReturnRows(sqlmock.NewRows(auditColumns).
+			AddRow(9, "alice", "update", "album", 3, "req-2", []byte(`{"title":"a"}`), []byte(`{"title":"b"}`), createdAt).
+			AddRow(4, "alice", "create", "album", 3, "", nil, []byte(`{"title":"a"}`), createdAt))
+
+	page, err := suite.auditRepository.GetAll(ctx, &model.AuditQuery{
+		Limit:      1,
+		Cursor:     &model.Cursor{Sort: "id", Desc: true, ID: 10},
+		EntityType: model.AuditEntityAlbum,
+		EntityID:   3,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(page.Items, 1)
+	suite.Equal(&model.AuditEvent{
+		ID:         9,
+		Actor:      "alice",
+		Action:     model.AuditUpdate,
+		EntityType: model.AuditEntityAlbum,
+		EntityID:   3,
+		RequestID:  "req-2",
+		Before:     json.RawMessage(`{"title":"a"}`),
+		After:      json.RawMessage(`{"title":"b"}`),
+		CreatedAt:  createdAt,
+	}, page.Items[0])
+	suite.Equal(&model.Cursor{Sort: "id", Desc: true, ID: 9}, page.NextCursor)
+	suite.NoError(mock.ExpectationsWereMet())
+}
+
+func (suite *AuditRepositorySuite) TestAuditRepositoryGetAll_ByActor() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id, actor, action, entity_type, entity_id, request_id, before_snapshot, after_snapshot, created_at FROM audit_events WHERE actor = ? ORDER BY id DESC LIMIT ?").
+		WithArgs("bob", 21).
+		WillReturnRows(sqlmock.NewRows(auditColumns))
+
+	page, err := suite.auditRepository.GetAll(ctx, &model.AuditQuery{Limit: 20, Actor: "bob"})
+	suite.NoError(err)
+	suite.Empty(page.Items)
+	suite.Nil(page.NextCursor)
+	suite.NoError(mock.ExpectationsWereMet())
+}
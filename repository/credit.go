@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type CreditRepository interface {
+	GetByAlbum(ctx context.Context, albumID model.AlbumID) ([]*model.Credit, error)
+	Replace(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) error
+}
+
+type creditRepository struct {
+	db *sql.DB
+}
+
+var _ CreditRepository = (*creditRepository)(nil)
+
+func NewCreditRepository(db *sql.DB) CreditRepository {
+	return &creditRepository{
+		db: db,
+	}
+}
+
+// GetByAlbum returns the album's secondary credits. The primary credit is not stored here;
+// it is albums.singer_id.
+func (r *creditRepository) GetByAlbum(ctx context.Context, albumID model.AlbumID) ([]*model.Credit, error) {
+	query := `
+		SELECT c.album_id, c.singer_id, s.name, c.role, c.position
+		FROM album_credits c
+		JOIN singers s ON c.singer_id = s.id
+		WHERE c.album_id = ? AND s.deleted_at IS NULL
+		ORDER BY c.position
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, albumID)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	credits := make([]*model.Credit, 0)
+	for rows.Next() {
+		credit := model.Credit{}
+		singer := model.Singer{}
+		if err = rows.Scan(&credit.AlbumID, &credit.SingerID, &singer.Name, &credit.Role, &credit.Position); err != nil {
+			return nil, translateError(err, nil)
+		}
+		singer.ID = credit.SingerID
+		credit.Singer = &singer
+		credits = append(credits, &credit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+	return credits, nil
+}
+
+// Replace swaps the album's whole credit list if the album is still at version: the primary
+// credit becomes albums.singer_id and the rest are rewritten in album_credits. Run it within
+// Transactor.WithinTx so the rewrite is atomic. Updating the live album row first both rejects
+// trashed albums and locks the album for the rewrite.
+func (r *creditRepository) Replace(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) error {
+	tx := conn(ctx, r.db)
+	for _, c := range credits {
+		if c.Role != model.CreditRolePrimary {
+			continue
+		}
+		query := `
+			UPDATE albums SET singer_id = ?, version = version + 1
+			WHERE id = ? AND version = ? AND deleted_at IS NULL
+				AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)
+		`
+		result, err := tx.ExecContext(ctx, query, c.SingerID, albumID, version, c.SingerID)
+		if err != nil {
+			return translateDuplicate(err, albumTitleKey, ErrorAlbumTitleTaken, mysqlErrors{mysqlErrNoReferencedRow: ErrorReferencedSingerNotFound})
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return translateError(err, nil)
+		}
+		if rowsAffected == 0 {
+			return r.replaceMissError(ctx, albumID, version)
+		}
+	}
+
+	query := `DELETE FROM album_credits WHERE album_id = ?`
+	if _, err := tx.ExecContext(ctx, query, albumID); err != nil {
+		return translateError(err, nil)
+	}
+
+	query = `INSERT INTO album_credits (album_id, singer_id, role, position) VALUES (?, ?, ?, ?)`
+	for _, c := range credits {
+		if c.Role == model.CreditRolePrimary {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, query, albumID, c.SingerID, c.Role, c.Position); err != nil {
+			return translateError(err, mysqlErrors{mysqlErrNoReferencedRow: ErrorReferencedSingerNotFound})
+		}
+	}
+	return nil
+}
+
+// replaceMissError tells why the primary credit's UPDATE matched no row: the album is gone,
+// has moved past version, or the new primary singer is not live.
+func (r *creditRepository) replaceMissError(ctx context.Context, albumID model.AlbumID, version int64) error {
+	query := `SELECT version FROM albums WHERE id = ? AND deleted_at IS NULL`
+	var current int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, albumID).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrorAlbumNotFound
+		}
+		return translateError(err, nil)
+	}
+	if current != version {
+		return ErrorAlbumVersionMismatch
+	}
+	return ErrorReferencedSingerNotFound
+}
@@ -0,0 +1,201 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CreditRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	creditRepository repository.CreditRepository
+}
+
+func TestCreditRepositorySuite(t *testing.T) {
+	suite.Run(t, new(CreditRepositorySuite))
+}
+
+func (suite *CreditRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.creditRepository = repository.NewCreditRepository(suite.DB)
+}
+
+func (suite *CreditRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.creditRepository = repository.NewCreditRepository(mockDB)
+	return mock
+}
+
+func (suite *CreditRepositorySuite) AfterTest() {
+	suite.creditRepository = repository.NewCreditRepository(suite.DB)
+}
+
+const replaceAlbumQuery = "UPDATE albums SET singer_id = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL AND EXISTS (SELECT 1 FROM singers WHERE id = ? AND deleted_at IS NULL)"
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryGetByAlbum() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT c.album_id, c.singer_id, s.name, c.role, c.position FROM album_credits c JOIN singers s ON c.singer_id = s.id WHERE c.album_id = ? AND s.deleted_at IS NULL ORDER BY c.position",
+	).WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"album_id", "singer_id", "name", "role", "position"}).
+			AddRow(2, 2, "Bella", "featured", 1).
+			AddRow(2, 3, "Chris", "producer", 2))
+
+	credits, err := suite.creditRepository.GetByAlbum(ctx, albumID)
+	suite.NoError(err)
+	suite.Equal([]*model.Credit{
+		{AlbumID: albumID, SingerID: 2, Singer: &model.Singer{ID: 2, Name: "Bella"}, Role: model.CreditRoleFeatured, Position: 1},
+		{AlbumID: albumID, SingerID: 3, Singer: &model.Singer{ID: 3, Name: "Chris"}, Role: model.CreditRoleProducer, Position: 2},
+	}, credits)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryReplace() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{
+		{SingerID: 1, Role: model.CreditRolePrimary},
+		{SingerID: 2, Role: model.CreditRoleFeatured, Position: 1},
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(replaceAlbumQuery).
+		WithArgs(model.SingerID(1), albumID, int64(4), model.SingerID(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM album_credits WHERE album_id = ?").
+		WithArgs(albumID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO album_credits (album_id, singer_id, role, position) VALUES (?, ?, ?, ?)").
+		WithArgs(albumID, model.SingerID(2), model.CreditRoleFeatured, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.creditRepository.Replace(ctx, albumID, 4, credits)
+	suite.NoError(err)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryReplace_SingerNotFound() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{
+		{SingerID: 1, Role: model.CreditRolePrimary},
+		{SingerID: 99, Role: model.CreditRoleProducer, Position: 1},
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(replaceAlbumQuery).
+		WithArgs(model.SingerID(1), albumID, int64(4), model.SingerID(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM album_credits WHERE album_id = ?").
+		WithArgs(albumID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO album_credits (album_id, singer_id, role, position) VALUES (?, ?, ?, ?)").
+		WithArgs(albumID, model.SingerID(99), model.CreditRoleProducer, 1).
+		WillReturnError(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"})
+
+	err := suite.creditRepository.Replace(ctx, albumID, 4, credits)
+	suite.ErrorIs(err, repository.ErrorReferencedSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryReplace_AlbumNotFound() {
+	ctx := context.Background()
+	albumID := model.AlbumID(99)
+	credits := []*model.Credit{{SingerID: 1, Role: model.CreditRolePrimary}}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(replaceAlbumQuery).
+		WithArgs(model.SingerID(1), albumID, int64(4), model.SingerID(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM albums WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+	err := suite.creditRepository.Replace(ctx, albumID, 4, credits)
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryReplace_TrashedAlbum() {
+	ctx := context.Background()
+	albumID := model.AlbumID(3)
+	credits := []*model.Credit{
+		{SingerID: 1, Role: model.CreditRolePrimary},
+		{SingerID: 2, Role: model.CreditRoleFeatured, Position: 1},
+	}
+
+	mock := suite.MockDB()
+	// the album row exists but is in the trash, so the live-only UPDATE matches nothing
+	mock.ExpectExec(replaceAlbumQuery).
+		WithArgs(model.SingerID(1), albumID, int64(4), model.SingerID(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM albums WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+	err := suite.creditRepository.Replace(ctx, albumID, 4, credits)
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+	suite.Equal(model.KindNotFound, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryReplace_VersionMismatch() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{{SingerID: 1, Role: model.CreditRolePrimary}}
+
+	mock := suite.MockDB()
+	mock.ExpectExec(replaceAlbumQuery).
+		WithArgs(model.SingerID(1), albumID, int64(4), model.SingerID(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM albums WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+
+	err := suite.creditRepository.Replace(ctx, albumID, 4, credits)
+	suite.ErrorIs(err, repository.ErrorAlbumVersionMismatch)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *CreditRepositorySuite) TestCreditRepositoryReplace_TrashedPrimarySinger() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{{SingerID: 7, Role: model.CreditRolePrimary}}
+
+	mock := suite.MockDB()
+	// the album is live and current, so it is the singer that is not
+	mock.ExpectExec(replaceAlbumQuery).
+		WithArgs(model.SingerID(7), albumID, int64(4), model.SingerID(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM albums WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+
+	err := suite.creditRepository.Replace(ctx, albumID, 4, credits)
+	suite.ErrorIs(err, repository.ErrorReferencedSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
@@ -1,8 +1,105 @@
 package repository
 
-import "errors"
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
 
 var (
-	ErrorSingerNotFound = errors.New("singer not found")
-	ErrorAlbumNotFound  = errors.New("album not found")
+	ErrorSingerNotFound           = model.NewError(model.KindNotFound, "singer not found", nil)
+	ErrorAlbumNotFound            = model.NewError(model.KindNotFound, "album not found", nil)
+	ErrorReferencedSingerNotFound = model.NewError(model.KindForeignKeyViolation, "referenced singer not found", nil)
+	ErrorSingerAlreadyExists      = model.NewError(model.KindConflict, "singer ID already exists", nil)
+	ErrorAlbumAlreadyExists       = model.NewError(model.KindConflict, "album ID already exists", nil)
+	ErrorSingerNameTaken          = model.NewError(model.KindConflict, "a singer with this name already exists", nil)
+	ErrorAlbumTitleTaken          = model.NewError(model.KindConflict, "the singer already has an album with this title", nil)
+	ErrorSingerVersionMismatch    = model.NewError(model.KindPreconditionFailed, "singer has been modified since it was read", nil)
+	ErrorAlbumVersionMismatch     = model.NewError(model.KindPreconditionFailed, "album has been modified since it was read", nil)
+	ErrorSingerHasAlbums          = model.NewError(model.KindConflict, "cannot delete singer: related albums exist, use cascade=true", nil)
+	ErrorSingerNotInTrash         = model.NewError(model.KindNotFound, "singer not found in trash", nil)
+	ErrorAlbumNotInTrash          = model.NewError(model.KindNotFound, "album not found in trash", nil)
+	ErrorTrackNotFound            = model.NewError(model.KindNotFound, "track not found", nil)
+	ErrorReferencedAlbumNotFound  = model.NewError(model.KindForeignKeyViolation, "referenced album not found", nil)
+	ErrorTrackPositionTaken       = model.NewError(model.KindConflict, "disc and track number already used on this album", nil)
+	ErrorTrackNotReordered        = model.NewError(model.KindConflict, "a track of the album was left out of the new order", nil)
+	ErrorIdempotencyKeyInUse      = model.NewError(model.KindConflict, "a request with this Idempotency-Key is still being processed", nil)
+	ErrorImportJobNotFound        = model.NewError(model.KindNotFound, "import job not found", nil)
+	ErrorAPIKeyNotFound           = model.NewError(model.KindNotFound, "api key not found", nil)
+)
+
+// MySQL server error numbers the repositories translate.
+const (
+	mysqlErrDupEntry         = 1062
+	mysqlErrRowIsReferenced  = 1451
+	mysqlErrNoReferencedRow  = 1452
+	mysqlErrTooManyConns     = 1040
+	mysqlErrLockWaitTimeout  = 1205
+	mysqlErrQueryInterrupted = 1317
+	mysqlErrQueryTimeout     = 3024
 )
+
+// mysqlErrors maps MySQL error numbers to the domain error a specific statement should report.
+type mysqlErrors map[uint16]*model.Error
+
+// Unique keys on natural keys, named as in initdb/1_schema.sql.
+const (
+	singerNameKey = "uq_singers_name"
+	albumTitleKey = "uq_albums_singer_title"
+)
+
+// translateDuplicate is translateError for statements that can break a natural unique key: a
+// duplicate entry for key becomes keyErr, anything else is translated with specific.
+func translateDuplicate(err error, key string, keyErr *model.Error, specific mysqlErrors) error {
+	var mysqlErr *mysql.MySQLError
+	// MySQL names the key as 'table.key' at the end of the message.
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry && strings.HasSuffix(mysqlErr.Message, "."+key+"'") {
+		return fmt.Errorf("%w: %w", keyErr, err)
+	}
+	return translateError(err, specific)
+}
+
+// translateError converts driver errors into classified domain errors. The original
+// error stays in the chain so it can be logged, but never becomes the client message.
+func translateError(err error, specific mysqlErrors) error {
+	if err == nil {
+		return nil
+	}
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if known, ok := specific[mysqlErr.Number]; ok {
+			return fmt.Errorf("%w: %w", known, err)
+		}
+		switch mysqlErr.Number {
+		case mysqlErrDupEntry:
+			return model.NewError(model.KindConflict, "resource already exists", err)
+		case mysqlErrRowIsReferenced:
+			return model.NewError(model.KindConflict, "resource is still referenced", err)
+		case mysqlErrNoReferencedRow:
+			return model.NewError(model.KindForeignKeyViolation, "referenced resource not found", err)
+		case mysqlErrTooManyConns, mysqlErrLockWaitTimeout, mysqlErrQueryInterrupted, mysqlErrQueryTimeout:
+			return model.NewError(model.KindUnavailable, "database unavailable", err)
+		}
+		return model.NewError(model.KindInternal, "database error", err)
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return model.NewError(model.KindUnavailable, "request cancelled", err)
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, mysql.ErrInvalidConn), errors.As(err, &netErr):
+		return model.NewError(model.KindUnavailable, "database unavailable", err)
+	}
+	return model.NewError(model.KindInternal, "database error", err)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SchemaVersion is the latest schema_migrations version this build expects; bump it together
+// with initdb/1_schema.sql.
+const SchemaVersion = 7
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	// SchemaVersion returns the latest applied migration, or 0 when none is recorded.
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+type healthRepository struct {
+	db *sql.DB
+}
+
+var _ HealthRepository = (*healthRepository)(nil)
+
+func NewHealthRepository(db *sql.DB) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+func (r *healthRepository) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+func (r *healthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+	return version, nil
+}
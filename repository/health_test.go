@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	healthRepository repository.HealthRepository
+}
+
+func TestHealthRepositorySuite(t *testing.T) {
+	suite.Run(t, new(HealthRepositorySuite))
+}
+
+func (suite *HealthRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.healthRepository = repository.NewHealthRepository(suite.DB)
+}
+
+func (suite *HealthRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.healthRepository = repository.NewHealthRepository(mockDB)
+	return mock
+}
+
+func (suite *HealthRepositorySuite) TestHealthRepositorySchemaVersion() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(7))
+
+	version, err := suite.healthRepository.SchemaVersion(ctx)
+	suite.NoError(err)
+	suite.Equal(repository.SchemaVersion, version)
+	suite.NoError(mock.ExpectationsWereMet())
+}
+
+func (suite *HealthRepositorySuite) TestHealthRepositorySchemaVersion_MissingTable() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").
+		WillReturnError(&mysql.MySQLError{Number: 1146, Message: "Table 'myapp.schema_migrations' doesn't exist"})
+
+	_, err := suite.healthRepository.SchemaVersion(ctx)
+	suite.Error(err)
+	suite.Equal(model.KindInternal, model.KindOf(err))
+}
+
+func (suite *HealthRepositorySuite) TestHealthRepositoryPing_Unreachable() {
+	ctx := context.Background()
+
+	mockDB, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	suite.Require().NoError(err)
+	suite.healthRepository = repository.NewHealthRepository(mockDB)
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	err = suite.healthRepository.Ping(ctx)
+	suite.Error(err)
+	suite.NoError(mock.ExpectationsWereMet())
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores record as in progress. If an unexpired record with the same subject and
+	// key exists, nothing is stored and that record is returned instead.
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+	// Complete saves the response of the reserved request.
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	// Release forgets a reservation that never completed so the key can be retried.
+	Release(ctx context.Context, subject, key string) error
+	Purge(ctx context.Context, expiredBefore time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *sql.DB
+}
+
+var _ IdempotencyRepository = (*idempotencyRepository)(nil)
+
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	query := `DELETE FROM idempotency_keys WHERE subject = ? AND idempotency_key = ? AND expires_at <= ?`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, record.Subject, record.Key, record.CreatedAt); err != nil {
+		return nil, translateError(err, nil)
+	}
+
+	query = `INSERT INTO idempotency_keys (subject, idempotency_key, fingerprint, created_at, expires_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, record.Subject, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt)
+	if err == nil {
+		return nil, nil
+	}
+	err = translateError(err, mysqlErrors{mysqlErrDupEntry: ErrorIdempotencyKeyInUse})
+	if !errors.Is(err, ErrorIdempotencyKeyInUse) {
+		return nil, err
+	}
+
+	query = `
+		SELECT subject, idempotency_key, fingerprint, status_code, header, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE subject = ? AND idempotency_key = ?
+	`
+	existing := model.IdempotencyRecord{}
+	var header []byte
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, record.Subject, record.Key).Scan(
+		&existing.Subject, &existing.Key, &existing.Fingerprint, &existing.StatusCode, &header, &existing.Body, &existing.CreatedAt, &existing.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between the insert and the select; the client may simply retry.
+		return nil, ErrorIdempotencyKeyInUse
+	} else if err != nil {
+		return nil, translateError(err, nil)
+	}
+	if header != nil {
+		if err = json.Unmarshal(header, &existing.Header); err != nil {
+			return nil, translateError(err, nil)
+		}
+	}
+	return &existing, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	query := `UPDATE idempotency_keys SET status_code = ?, header = ?, body = ? WHERE subject = ? AND idempotency_key = ? AND fingerprint = ?`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, record.StatusCode, header, record.Body, record.Subject, record.Key, record.Fingerprint)
+	if err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, subject, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE subject = ? AND idempotency_key = ? AND status_code = 0`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, subject, key); err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Purge(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= ?`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, expiredBefore)
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+	return purged, nil
+}
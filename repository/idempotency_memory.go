@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type inMemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]*model.IdempotencyRecord
+}
+
+type idempotencyKey struct {
+	subject string
+	key     string
+}
+
+var _ IdempotencyRepository = (*inMemoryIdempotencyRepository)(nil)
+
+// NewInMemoryIdempotencyRepository keeps idempotency records in process memory. It suits tests
+// and single-instance deployments; records do not survive a restart.
+func NewInMemoryIdempotencyRepository() IdempotencyRepository {
+	return &inMemoryIdempotencyRepository{
+		records: make(map[idempotencyKey]*model.IdempotencyRecord),
+	}
+}
+
+func (r *inMemoryIdempotencyRepository) Reserve(_ context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := idempotencyKey{subject: record.Subject, key: record.Key}
+	if existing, ok := r.records[k]; ok && !existing.Expired(record.CreatedAt) {
+		return cloneIdempotencyRecord(existing), nil
+	}
+	r.records[k] = cloneIdempotencyRecord(record)
+	return nil, nil
+}
+
+func (r *inMemoryIdempotencyRepository) Complete(_ context.Context, record *model.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := idempotencyKey{subject: record.Subject, key: record.Key}
+	if existing, ok := r.records[k]; ok && existing.Fingerprint == record.Fingerprint {
+		r.records[k] = cloneIdempotencyRecord(record)
+	}
+	return nil
+}
+
+func (r *inMemoryIdempotencyRepository) Release(_ context.Context, subject, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := idempotencyKey{subject: subject, key: key}
+	if existing, ok := r.records[k]; ok && !existing.Completed() {
+		delete(r.records, k)
+	}
+	return nil
+}
+
+func (r *inMemoryIdempotencyRepository) Purge(_ context.Context, expiredBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for key, record := range r.records {
+		if record.Expired(expiredBefore) {
+			delete(r.records, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func cloneIdempotencyRecord(record *model.IdempotencyRecord) *model.IdempotencyRecord {
+	clone := *record
+	clone.Header = maps.Clone(record.Header)
+	clone.Body = slices.Clone(record.Body)
+	return &clone
+}
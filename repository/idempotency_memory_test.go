@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInMemoryIdempotencyRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	idempotencyRepository := repository.NewInMemoryIdempotencyRepository()
+
+	record := &model.IdempotencyRecord{Key: "key-1", Fingerprint: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	existing, err := idempotencyRepository.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = idempotencyRepository.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, existing.Completed())
+
+	// A pending reservation can be released, a completed one cannot.
+	assert.NoError(t, idempotencyRepository.Release(ctx, "", "key-1"))
+	existing, err = idempotencyRepository.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	record.StatusCode = 201
+	record.Body = []byte(`{"id":7}`)
+	assert.NoError(t, idempotencyRepository.Complete(ctx, record))
+	assert.NoError(t, idempotencyRepository.Release(ctx, "", "key-1"))
+
+	existing, err = idempotencyRepository.Reserve(ctx, &model.IdempotencyRecord{Key: "key-1", Fingerprint: "other", CreatedAt: now})
+	assert.NoError(t, err)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, "abc", existing.Fingerprint)
+
+	// Another principal using the same key gets a reservation of its own.
+	existing, err = idempotencyRepository.Reserve(ctx, &model.IdempotencyRecord{Subject: "api_key:other", Key: "key-1", Fingerprint: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// Once expired the key is free again.
+	later := now.Add(2 * time.Hour)
+	existing, err = idempotencyRepository.Reserve(ctx, &model.IdempotencyRecord{Key: "key-1", Fingerprint: "other", CreatedAt: later, ExpiresAt: later.Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	purged, err := idempotencyRepository.Purge(ctx, later.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
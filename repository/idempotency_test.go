@@ -0,0 +1,132 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type IdempotencyRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	idempotencyRepository repository.IdempotencyRepository
+}
+
+func TestIdempotencyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositorySuite))
+}
+
+func (suite *IdempotencyRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.idempotencyRepository = repository.NewIdempotencyRepository(suite.DB)
+}
+
+func (suite *IdempotencyRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.idempotencyRepository = repository.NewIdempotencyRepository(mockDB)
+	return mock
+}
+
+func (suite *IdempotencyRepositorySuite) AfterTest() {
+	suite.idempotencyRepository = repository.NewIdempotencyRepository(suite.DB)
+}
+
+func newIdempotencyRecord() *model.IdempotencyRecord {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	return &model.IdempotencyRecord{Subject: "api_key:ci", Key: "key-1", Fingerprint: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+}
+
+func (suite *IdempotencyRepositorySuite) TestIdempotencyRepositoryReserve() {
+	ctx := context.Background()
+	record := newIdempotencyRecord()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE subject = ? AND idempotency_key = ? AND expires_at <= ?").
+		WithArgs(record.Subject, record.Key, record.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO idempotency_keys (subject, idempotency_key, fingerprint, created_at, expires_at) VALUES (?, ?, ?, ?, ?)").
+		WithArgs(record.Subject, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	existing, err := suite.idempotencyRepository.Reserve(ctx, record)
+	suite.NoError(err)
+	suite.Nil(existing)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *IdempotencyRepositorySuite) TestIdempotencyRepositoryReserve_Existing() {
+	ctx := context.Background()
+	record := newIdempotencyRecord()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE subject = ? AND idempotency_key = ? AND expires_at <= ?").
+		WithArgs(record.Subject, record.Key, record.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO idempotency_keys (subject, idempotency_key, fingerprint, created_at, expires_at) VALUES (?, ?, ?, ?, ?)").
+		WithArgs(record.Subject, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'key-1' for key 'idempotency_keys.PRIMARY'"})
+	mock.ExpectQuery("SELECT subject, idempotency_key, fingerprint, status_code, header, body, created_at, expires_at FROM idempotency_keys WHERE subject = ? AND idempotency_key = ?").
+		WithArgs(record.Subject, record.Key).
+		WillReturnRows(sqlmock.NewRows([]string{"subject", "idempotency_key", "fingerprint", "status_code", "header", "body", "created_at", "expires_at"}).
+			AddRow(record.Subject, record.Key, record.Fingerprint, 201, []byte(`{"Location":"/albums/7"}`), []byte(`{"id":7}`), record.CreatedAt, record.ExpiresAt))
+
+	existing, err := suite.idempotencyRepository.Reserve(ctx, record)
+	suite.NoError(err)
+	suite.Equal(201, existing.StatusCode)
+	suite.Equal(map[string]string{"Location": "/albums/7"}, existing.Header)
+	suite.Equal([]byte(`{"id":7}`), existing.Body)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *IdempotencyRepositorySuite) TestIdempotencyRepositoryComplete() {
+	ctx := context.Background()
+	record := newIdempotencyRecord()
+	record.StatusCode = 201
+	record.Header = map[string]string{"Content-Type": "application/json"}
+	record.Body = []byte(`{"id":7}`)
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code = ?, header = ?, body = ? WHERE subject = ? AND idempotency_key = ? AND fingerprint = ?").
+		WithArgs(201, []byte(`{"Content-Type":"application/json"}`), record.Body, record.Subject, record.Key, record.Fingerprint).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.idempotencyRepository.Complete(ctx, record)
+	suite.NoError(err)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *IdempotencyRepositorySuite) TestIdempotencyRepositoryReleaseAndPurge() {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE subject = ? AND idempotency_key = ? AND status_code = 0").
+		WithArgs("api_key:ci", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE expires_at <= ?").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := suite.idempotencyRepository.Release(ctx, "api_key:ci", "key-1")
+	suite.NoError(err)
+
+	purged, err := suite.idempotencyRepository.Purge(ctx, now)
+	suite.NoError(err)
+	suite.Equal(int64(3), purged)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
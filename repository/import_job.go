@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// ImportJobRepository stores the progress of imports while they run.
+type ImportJobRepository interface {
+	Save(ctx context.Context, job *model.ImportJob) error
+	// Get returns the job subject started; the jobs of other principals are not found.
+	Get(ctx context.Context, subject, id string) (*model.ImportJob, error)
+}
+
+type importJobKey struct {
+	subject string
+	id      string
+}
+
+type inMemoryImportJobRepository struct {
+	mu        sync.Mutex
+	jobs      map[importJobKey]*model.ImportJob
+	retention time.Duration
+}
+
+var _ ImportJobRepository = (*inMemoryImportJobRepository)(nil)
+
+// NewInMemoryImportJobRepository keeps import jobs in process memory. Jobs are only needed
+// while a client polls them, so finished jobs are dropped after retention and nothing
+// survives a restart.
+func NewInMemoryImportJobRepository(retention time.Duration) ImportJobRepository {
+	return &inMemoryImportJobRepository{
+		jobs:      make(map[importJobKey]*model.ImportJob),
+		retention: retention,
+	}
+}
+
+// Save stores a copy of job, replacing the previous state of the same job.
+func (r *inMemoryImportJobRepository) Save(_ context.Context, job *model.ImportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-r.retention)
+	for k, existing := range r.jobs {
+		if existing.FinishedAt != nil && existing.FinishedAt.Before(cutoff) {
+			delete(r.jobs, k)
+		}
+	}
+	r.jobs[importJobKey{subject: job.Subject, id: job.ID}] = job.Clone()
+	return nil
+}
+
+func (r *inMemoryImportJobRepository) Get(_ context.Context, subject, id string) (*model.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[importJobKey{subject: subject, id: id}]
+	if !ok {
+		return nil, ErrorImportJobNotFound
+	}
+	return job.Clone(), nil
+}
@@ -0,0 +1,62 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInMemoryImportJobRepository(t *testing.T) {
+	ctx := context.Background()
+	importJobRepository := repository.NewInMemoryImportJobRepository(time.Hour)
+
+	_, err := importJobRepository.Get(ctx, "", "missing")
+	assert.ErrorIs(t, err, repository.ErrorImportJobNotFound)
+
+	job := &model.ImportJob{ID: "job-1", Status: model.ImportRunning, TotalRows: 2}
+	assert.NoError(t, importJobRepository.Save(ctx, job))
+
+	// The stored job is a copy, so later changes only show up once saved.
+	job.ProcessedRows = 1
+	job.Errors = append(job.Errors, model.ImportRowError{Line: 2})
+	stored, err := importJobRepository.Get(ctx, "", "job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stored.ProcessedRows)
+	assert.Empty(t, stored.Errors)
+
+	assert.NoError(t, importJobRepository.Save(ctx, job))
+	stored, err = importJobRepository.Get(ctx, "", "job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stored.ProcessedRows)
+	assert.Len(t, stored.Errors, 1)
+}
+
+func TestInMemoryImportJobRepository_DropsOldJobs(t *testing.T) {
+	ctx := context.Background()
+	importJobRepository := repository.NewInMemoryImportJobRepository(time.Hour)
+
+	finishedAt := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, importJobRepository.Save(ctx, &model.ImportJob{ID: "old", Status: model.ImportCompleted, FinishedAt: &finishedAt}))
+	assert.NoError(t, importJobRepository.Save(ctx, &model.ImportJob{ID: "new", Status: model.ImportRunning}))
+
+	_, err := importJobRepository.Get(ctx, "", "old")
+	assert.ErrorIs(t, err, repository.ErrorImportJobNotFound)
+	_, err = importJobRepository.Get(ctx, "", "new")
+	assert.NoError(t, err)
+}
+
+func TestInMemoryImportJobRepository_ScopedToSubject(t *testing.T) {
+	ctx := context.Background()
+	importJobRepository := repository.NewInMemoryImportJobRepository(time.Hour)
+
+	assert.NoError(t, importJobRepository.Save(ctx, &model.ImportJob{ID: "job-1", Subject: "alice", Status: model.ImportRunning}))
+
+	stored, err := importJobRepository.Get(ctx, "alice", "job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", stored.Subject)
+	_, err = importJobRepository.Get(ctx, "bob", "job-1")
+	assert.ErrorIs(t, err, repository.ErrorImportJobNotFound)
+}
@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// Observer is told about every call to an observed repository, e.g. to record its duration.
+// StartCall may return a derived context for the call; done receives the call's error.
+type Observer interface {
+	StartCall(ctx context.Context, repository, method string) (_ context.Context, done func(err error))
+}
+
+// Observers reports each call to every observer, ending them in reverse order.
+func Observers(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) StartCall(ctx context.Context, repository, method string) (context.Context, func(err error)) {
+	dones := make([]func(err error), len(m))
+	for i, observer := range m {
+		ctx, dones[i] = observer.StartCall(ctx, repository, method)
+	}
+	return ctx, func(err error) {
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](err)
+		}
+	}
+}
+
+type observedSingerRepository struct {
+	next     SingerRepository
+	observer Observer
+}
+
+var _ SingerRepository = (*observedSingerRepository)(nil)
+
+// ObserveSingerRepository reports every call of next to observer.
+func ObserveSingerRepository(next SingerRepository, observer Observer) SingerRepository {
+	return &observedSingerRepository{next: next, observer: observer}
+}
+
+func (r *observedSingerRepository) GetAll(ctx context.Context, q *model.SingerQuery) (_ *model.Page[*model.Singer], err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "GetAll")
+	defer func() { done(err) }()
+	return r.next.GetAll(ctx, q)
+}
+
+func (r *observedSingerRepository) Export(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Export")
+	defer func() { done(err) }()
+	return r.next.Export(ctx, q, fn)
+}
+
+func (r *observedSingerRepository) Get(ctx context.Context, id model.SingerID) (_ *model.Singer, err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Get")
+	defer func() { done(err) }()
+	return r.next.Get(ctx, id)
+}
+
+func (r *observedSingerRepository) GetByName(ctx context.Context, name string) (_ *model.Singer, err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "GetByName")
+	defer func() { done(err) }()
+	return r.next.GetByName(ctx, name)
+}
+
+func (r *observedSingerRepository) Add(ctx context.Context, singer *model.Singer) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Add")
+	defer func() { done(err) }()
+	return r.next.Add(ctx, singer)
+}
+
+func (r *observedSingerRepository) AddIfAbsent(ctx context.Context, singer *model.Singer) (_ bool, err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "AddIfAbsent")
+	defer func() { done(err) }()
+	return r.next.AddIfAbsent(ctx, singer)
+}
+
+func (r *observedSingerRepository) AddBatch(ctx context.Context, singers []*model.Singer) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "AddBatch")
+	defer func() { done(err) }()
+	return r.next.AddBatch(ctx, singers)
+}
+
+func (r *observedSingerRepository) Update(ctx context.Context, singer *model.Singer) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Update")
+	defer func() { done(err) }()
+	return r.next.Update(ctx, singer)
+}
+
+func (r *observedSingerRepository) Delete(ctx context.Context, id model.SingerID, version int64) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Delete")
+	defer func() { done(err) }()
+	return r.next.Delete(ctx, id, version)
+}
+
+func (r *observedSingerRepository) Restore(ctx context.Context, id model.SingerID) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Restore")
+	defer func() { done(err) }()
+	return r.next.Restore(ctx, id)
+}
+
+func (r *observedSingerRepository) GetDeleted(ctx context.Context) (_ []*model.Singer, err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "GetDeleted")
+	defer func() { done(err) }()
+	return r.next.GetDeleted(ctx)
+}
+
+func (r *observedSingerRepository) Purge(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	ctx, done := r.observer.StartCall(ctx, "singer", "Purge")
+	defer func() { done(err) }()
+	return r.next.Purge(ctx, deletedBefore)
+}
+
+type observedAlbumRepository struct {
+	next     AlbumRepository
+	observer Observer
+}
+
+var _ AlbumRepository = (*observedAlbumRepository)(nil)
+
+// ObserveAlbumRepository reports every call of next to observer.
+func ObserveAlbumRepository(next AlbumRepository, observer Observer) AlbumRepository {
+	return &observedAlbumRepository{next: next, observer: observer}
+}
+
+func (r *observedAlbumRepository) GetAll(ctx context.Context, q *model.AlbumQuery) (_ *model.Page[*model.Album], err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "GetAll")
+	defer func() { done(err) }()
+	return r.next.GetAll(ctx, q)
+}
+
+func (r *observedAlbumRepository) Export(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Export")
+	defer func() { done(err) }()
+	return r.next.Export(ctx, q, fn)
+}
+
+func (r *observedAlbumRepository) Get(ctx context.Context, id model.AlbumID) (_ *model.Album, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Get")
+	defer func() { done(err) }()
+	return r.next.Get(ctx, id)
+}
+
+func (r *observedAlbumRepository) GetBySingerAndTitle(ctx context.Context, singerID model.SingerID, title string) (_ *model.Album, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "GetBySingerAndTitle")
+	defer func() { done(err) }()
+	return r.next.GetBySingerAndTitle(ctx, singerID, title)
+}
+
+func (r *observedAlbumRepository) GetBySinger(ctx context.Context, singerID model.SingerID) (_ []*model.SingerAlbum, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "GetBySinger")
+	defer func() { done(err) }()
+	return r.next.GetBySinger(ctx, singerID)
+}
+
+func (r *observedAlbumRepository) GetIDsBySinger(ctx context.Context, singerID model.SingerID) (_ []model.AlbumID, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "GetIDsBySinger")
+	defer func() { done(err) }()
+	return r.next.GetIDsBySinger(ctx, singerID)
+}
+
+func (r *observedAlbumRepository) Add(ctx context.Context, album *model.Album) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Add")
+	defer func() { done(err) }()
+	return r.next.Add(ctx, album)
+}
+
+func (r *observedAlbumRepository) AddIfAbsent(ctx context.Context, album *model.Album) (_ bool, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "AddIfAbsent")
+	defer func() { done(err) }()
+	return r.next.AddIfAbsent(ctx, album)
+}
+
+func (r *observedAlbumRepository) AddBatch(ctx context.Context, albums []*model.Album) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "AddBatch")
+	defer func() { done(err) }()
+	return r.next.AddBatch(ctx, albums)
+}
+
+func (r *observedAlbumRepository) Update(ctx context.Context, album *model.Album) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Update")
+	defer func() { done(err) }()
+	return r.next.Update(ctx, album)
+}
+
+func (r *observedAlbumRepository) Delete(ctx context.Context, id model.AlbumID, version int64) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Delete")
+	defer func() { done(err) }()
+	return r.next.Delete(ctx, id, version)
+}
+
+func (r *observedAlbumRepository) Restore(ctx context.Context, id model.AlbumID) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Restore")
+	defer func() { done(err) }()
+	return r.next.Restore(ctx, id)
+}
+
+func (r *observedAlbumRepository) GetDeleted(ctx context.Context) (_ []*model.Album, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "GetDeleted")
+	defer func() { done(err) }()
+	return r.next.GetDeleted(ctx)
+}
+
+func (r *observedAlbumRepository) Purge(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	ctx, done := r.observer.StartCall(ctx, "album", "Purge")
+	defer func() { done(err) }()
+	return r.next.Purge(ctx, deletedBefore)
+}
+
+type observedTrackRepository struct {
+	next     TrackRepository
+	observer Observer
+}
+
+var _ TrackRepository = (*observedTrackRepository)(nil)
+
+// ObserveTrackRepository reports every call of next to observer.
+func ObserveTrackRepository(next TrackRepository, observer Observer) TrackRepository {
+	return &observedTrackRepository{next: next, observer: observer}
+}
+
+func (r *observedTrackRepository) GetByAlbum(ctx context.Context, albumID model.AlbumID) (_ []*model.Track, err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "GetByAlbum")
+	defer func() { done(err) }()
+	return r.next.GetByAlbum(ctx, albumID)
+}
+
+func (r *observedTrackRepository) Get(ctx context.Context, id model.TrackID) (_ *model.Track, err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "Get")
+	defer func() { done(err) }()
+	return r.next.Get(ctx, id)
+}
+
+func (r *observedTrackRepository) Add(ctx context.Context, track *model.Track) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "Add")
+	defer func() { done(err) }()
+	return r.next.Add(ctx, track)
+}
+
+func (r *observedTrackRepository) Update(ctx context.Context, track *model.Track) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "Update")
+	defer func() { done(err) }()
+	return r.next.Update(ctx, track)
+}
+
+func (r *observedTrackRepository) Delete(ctx context.Context, id model.TrackID) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "Delete")
+	defer func() { done(err) }()
+	return r.next.Delete(ctx, id)
+}
+
+func (r *observedTrackRepository) LockAlbum(ctx context.Context, albumID model.AlbumID) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "LockAlbum")
+	defer func() { done(err) }()
+	return r.next.LockAlbum(ctx, albumID)
+}
+
+func (r *observedTrackRepository) Reorder(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "track", "Reorder")
+	defer func() { done(err) }()
+	return r.next.Reorder(ctx, albumID, positions)
+}
+
+type observedCreditRepository struct {
+	next     CreditRepository
+	observer Observer
+}
+
+var _ CreditRepository = (*observedCreditRepository)(nil)
+
+// ObserveCreditRepository reports every call of next to observer.
+func ObserveCreditRepository(next CreditRepository, observer Observer) CreditRepository {
+	return &observedCreditRepository{next: next, observer: observer}
+}
+
+func (r *observedCreditRepository) GetByAlbum(ctx context.Context, albumID model.AlbumID) (_ []*model.Credit, err error) {
+	ctx, done := r.observer.StartCall(ctx, "credit", "GetByAlbum")
+	defer func() { done(err) }()
+	return r.next.GetByAlbum(ctx, albumID)
+}
+
+func (r *observedCreditRepository) Replace(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "credit", "Replace")
+	defer func() { done(err) }()
+	return r.next.Replace(ctx, albumID, version, credits)
+}
+
+type observedSearchRepository struct {
+	next     SearchRepository
+	observer Observer
+}
+
+var _ SearchRepository = (*observedSearchRepository)(nil)
+
+// ObserveSearchRepository reports every call of next to observer.
+func ObserveSearchRepository(next SearchRepository, observer Observer) SearchRepository {
+	return &observedSearchRepository{next: next, observer: observer}
+}
+
+func (r *observedSearchRepository) Search(ctx context.Context, q *model.SearchQuery) (_ *model.Page[*model.SearchResult], err error) {
+	ctx, done := r.observer.StartCall(ctx, "search", "Search")
+	defer func() { done(err) }()
+	return r.next.Search(ctx, q)
+}
+
+type observedIdempotencyRepository struct {
+	next     IdempotencyRepository
+	observer Observer
+}
+
+var _ IdempotencyRepository = (*observedIdempotencyRepository)(nil)
+
+// ObserveIdempotencyRepository reports every call of next to observer.
+func ObserveIdempotencyRepository(next IdempotencyRepository, observer Observer) IdempotencyRepository {
+	return &observedIdempotencyRepository{next: next, observer: observer}
+}
+
+func (r *observedIdempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord) (_ *model.IdempotencyRecord, err error) {
+	ctx, done := r.observer.StartCall(ctx, "idempotency", "Reserve")
+	defer func() { done(err) }()
+	return r.next.Reserve(ctx, record)
+}
+
+func (r *observedIdempotencyRepository) Complete(ctx context.Context, record *model.IdempotencyRecord) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "idempotency", "Complete")
+	defer func() { done(err) }()
+	return r.next.Complete(ctx, record)
+}
+
+func (r *observedIdempotencyRepository) Release(ctx context.Context, subject, key string) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "idempotency", "Release")
+	defer func() { done(err) }()
+	return r.next.Release(ctx, subject, key)
+}
+
+func (r *observedIdempotencyRepository) Purge(ctx context.Context, expiredBefore time.Time) (_ int64, err error) {
+	ctx, done := r.observer.StartCall(ctx, "idempotency", "Purge")
+	defer func() { done(err) }()
+	return r.next.Purge(ctx, expiredBefore)
+}
+
+type observedHealthRepository struct {
+	next     HealthRepository
+	observer Observer
+}
+
+var _ HealthRepository = (*observedHealthRepository)(nil)
+
+// ObserveHealthRepository reports every call of next to observer.
+func ObserveHealthRepository(next HealthRepository, observer Observer) HealthRepository {
+	return &observedHealthRepository{next: next, observer: observer}
+}
+
+func (r *observedHealthRepository) Ping(ctx context.Context) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "health", "Ping")
+	defer func() { done(err) }()
+	return r.next.Ping(ctx)
+}
+
+func (r *observedHealthRepository) SchemaVersion(ctx context.Context) (_ int, err error) {
+	ctx, done := r.observer.StartCall(ctx, "health", "SchemaVersion")
+	defer func() { done(err) }()
+	return r.next.SchemaVersion(ctx)
+}
+
+type observedAPIKeyRepository struct {
+	next     APIKeyRepository
+	observer Observer
+}
+
+var _ APIKeyRepository = (*observedAPIKeyRepository)(nil)
+
+// ObserveAPIKeyRepository reports every call of next to observer.
+func ObserveAPIKeyRepository(next APIKeyRepository, observer Observer) APIKeyRepository {
+	return &observedAPIKeyRepository{next: next, observer: observer}
+}
+
+func (r *observedAPIKeyRepository) GetByHash(ctx context.Context, hash string) (_ *model.APIKey, err error) {
+	ctx, done := r.observer.StartCall(ctx, "api_key", "GetByHash")
+	defer func() { done(err) }()
+	return r.next.GetByHash(ctx, hash)
+}
+
+type observedAuditRepository struct {
+	next     AuditRepository
+	observer Observer
+}
+
+var _ AuditRepository = (*observedAuditRepository)(nil)
+
+// ObserveAuditRepository reports every call of next to observer.
+func ObserveAuditRepository(next AuditRepository, observer Observer) AuditRepository {
+	return &observedAuditRepository{next: next, observer: observer}
+}
+
+func (r *observedAuditRepository) Add(ctx context.Context, event *model.AuditEvent) (err error) {
+	ctx, done := r.observer.StartCall(ctx, "audit", "Add")
+	defer func() { done(err) }()
+	return r.next.Add(ctx, event)
+}
+
+func (r *observedAuditRepository) GetAll(ctx context.Context, q *model.AuditQuery) (_ *model.Page[*model.AuditEvent], err error) {
+	ctx, done := r.observer.StartCall(ctx, "audit", "GetAll")
+	defer func() { done(err) }()
+	return r.next.GetAll(ctx, q)
+}
@@ -0,0 +1,65 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type observedCall struct {
+	repository string
+	method     string
+	err        error
+}
+
+type recordingObserver struct {
+	calls []observedCall
+}
+
+func (o *recordingObserver) StartCall(ctx context.Context, repo, method string) (context.Context, func(error)) {
+	return ctx, func(err error) {
+		o.calls = append(o.calls, observedCall{repository: repo, method: method, err: err})
+	}
+}
+
+type stubHealthRepository struct {
+	pingErr error
+}
+
+func (r *stubHealthRepository) Ping(ctx context.Context) error {
+	return r.pingErr
+}
+
+func (r *stubHealthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	return repository.SchemaVersion, nil
+}
+
+func TestObserveHealthRepository(t *testing.T) {
+	ctx := context.Background()
+	refused := errors.New("connection refused")
+	observer := &recordingObserver{}
+	repo := repository.ObserveHealthRepository(&stubHealthRepository{pingErr: refused}, observer)
+
+	err := repo.Ping(ctx)
+	assert.ErrorIs(t, err, refused)
+	version, err := repo.SchemaVersion(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.SchemaVersion, version)
+
+	assert.Equal(t, []observedCall{
+		{repository: "health", method: "Ping", err: refused},
+		{repository: "health", method: "SchemaVersion"},
+	}, observer.calls)
+}
+
+func TestObservers(t *testing.T) {
+	first, second := &recordingObserver{}, &recordingObserver{}
+	repo := repository.ObserveHealthRepository(&stubHealthRepository{}, repository.Observers(first, second))
+
+	assert.NoError(t, repo.Ping(context.Background()))
+	assert.Equal(t, []observedCall{{repository: "health", method: "Ping"}}, first.calls)
+	assert.Equal(t, first.calls, second.calls)
+}
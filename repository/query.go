@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// listQuery accumulates the WHERE conditions and arguments of a paginated listing.
+type listQuery struct {
+	conditions []string
+	args       []any
+}
+
+func (q *listQuery) where(condition string, args ...any) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// after continues a keyset listing ordered by column (then idColumn) after the cursor row.
+func (q *listQuery) after(column, idColumn string, cursor *model.Cursor, value any) {
+	op := ">"
+	if cursor.Desc {
+		op = "<"
+	}
+	if column == idColumn {
+		q.where(fmt.Sprintf("%s %s ?", idColumn, op), cursor.ID)
+		return
+	}
+	q.where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", column, op, column, idColumn, op), value, value, cursor.ID)
+}
+
+func orderBy(column, idColumn string, desc bool) string {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	if column == idColumn {
+		return fmt.Sprintf("ORDER BY %s %s", idColumn, direction)
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s %s", column, direction, idColumn, direction)
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied prefix.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// cursorValue converts a cursor's sort key back into a query argument.
+func cursorValue(cursor *model.Cursor, isTime bool) (any, error) {
+	if !isTime {
+		return cursor.Value, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, model.NewError(model.KindValidation, "cursor is malformed", err)
+	}
+	return t, nil
+}
+
+// placeholderList returns n comma separated placeholders for an IN list.
+func placeholderList(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// placeholders returns the VALUES rows of a multi-row INSERT of n rows with columns columns each.
+func placeholders(n, columns int) string {
+	row := "(" + placeholderList(columns) + ")"
+	return strings.TrimSuffix(strings.Repeat(row+", ", n), ", ")
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error)
+}
+
+type searchRepository struct {
+	db *sql.DB
+}
+
+var _ SearchRepository = (*searchRepository)(nil)
+
+func NewSearchRepository(db *sql.DB) SearchRepository {
+	return &searchRepository{
+		db: db,
+	}
+}
+
+// Search ranks singers and albums by the FULLTEXT relevance of their name or title.
+func (r *searchRepository) Search(ctx context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error) {
+	selects := make([]string, 0, 2)
+	args := make([]any, 0, 6)
+	if q.Includes(model.SearchResultSinger) {
+		selects = append(selects, `
+			SELECT 'singer' AS type, s.id, s.name AS label, s.id AS singer_id, s.name AS singer_name,
+				MATCH(s.name) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+			FROM singers s
+			WHERE MATCH(s.name) AGAINST (? IN NATURAL LANGUAGE MODE) AND s.deleted_at IS NULL`)
+		args = append(args, q.Query, q.Query)
+	}
+	if q.Includes(model.SearchResultAlbum) {
+		selects = append(selects, `
+			SELECT 'album' AS type, a.id, a.title AS label, s.id AS singer_id, s.name AS singer_name,
+				MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+			FROM albums a
+			JOIN singers s ON a.singer_id = s.id
+			WHERE MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AND a.deleted_at IS NULL`)
+		args = append(args, q.Query, q.Query)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT type, id, label, singer_id, singer_name, score
+		FROM (%s) results
+		ORDER BY score DESC, type, id
+		LIMIT ? OFFSET ?
+	`, strings.Join(selects, " UNION ALL "))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(args, q.Limit+1, q.Offset())...)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	results := make([]*model.SearchResult, 0)
+	for rows.Next() {
+		result := model.SearchResult{}
+		singer := model.Singer{}
+		if err = rows.Scan(&result.Type, &result.ID, &result.Name, &singer.ID, &singer.Name, &result.Score); err != nil {
+			return nil, translateError(err, nil)
+		}
+		if result.Type == model.SearchResultAlbum {
+			result.Singer = &singer
+		}
+		results = append(results, &result)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+
+	page := &model.Page[*model.SearchResult]{Items: results, NextCursor: model.NextSearchCursor(q, len(results))}
+	if len(results) > q.Limit {
+		page.Items = results[:q.Limit]
+	}
+	return page, nil
+}
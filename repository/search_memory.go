@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type inMemorySearchRepository struct {
+	singers []*model.Singer
+	albums  []*model.Album
+}
+
+var _ SearchRepository = (*inMemorySearchRepository)(nil)
+
+// NewInMemorySearchRepository searches a fixed snapshot of singers and albums.
+// It ranks like the FULLTEXT implementation closely enough for tests that run without a database.
+func NewInMemorySearchRepository(singers []*model.Singer, albums []*model.Album) SearchRepository {
+	return &inMemorySearchRepository{
+		singers: singers,
+		albums:  albums,
+	}
+}
+
+func (r *inMemorySearchRepository) Search(_ context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error) {
+	results := make([]*model.SearchResult, 0)
+	if q.Includes(model.SearchResultSinger) {
+		for _, singer := range r.singers {
+			if singer.DeletedAt != nil {
+				continue
+			}
+			if score := matchScore(singer.Name, q.Query); score > 0 {
+				results = append(results, &model.SearchResult{
+					Type:  model.SearchResultSinger,
+					ID:    int(singer.ID),
+					Name:  singer.Name,
+					Score: score,
+				})
+			}
+		}
+	}
+	if q.Includes(model.SearchResultAlbum) {
+		for _, album := range r.albums {
+			if album.DeletedAt != nil {
+				continue
+			}
+			if score := matchScore(album.Title, q.Query); score > 0 {
+				results = append(results, &model.SearchResult{
+					Type:   model.SearchResultAlbum,
+					ID:     int(album.ID),
+					Name:   album.Title,
+					Singer: album.Singer,
+					Score:  score,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	offset := min(q.Offset(), len(results))
+	results = results[offset:]
+	page := &model.Page[*model.SearchResult]{Items: results, NextCursor: model.NextSearchCursor(q, len(results))}
+	if len(results) > q.Limit {
+		page.Items = results[:q.Limit]
+	}
+	return page, nil
+}
+
+// matchScore favours prefix matches and repeated occurrences; zero means no match.
+func matchScore(text, query string) float64 {
+	text, query = strings.ToLower(text), strings.ToLower(query)
+	count := strings.Count(text, query)
+	if count == 0 {
+		return 0
+	}
+	score := float64(count)
+	if strings.HasPrefix(text, query) {
+		score++
+	}
+	return score
+}
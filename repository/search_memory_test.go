@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInMemorySearchRepository(t *testing.T) {
+	alice := &model.Singer{ID: model.SingerID(1), Name: "Alice"}
+	bella := &model.Singer{ID: model.SingerID(2), Name: "Bella"}
+	albums := []*model.Album{
+		{ID: model.AlbumID(1), Title: "Alice's 1st Album", SingerID: alice.ID, Singer: alice},
+		{ID: model.AlbumID(3), Title: "Bella's 1st Album", SingerID: bella.ID, Singer: bella},
+		{ID: model.AlbumID(4), Title: "Wonderland", SingerID: bella.ID, Singer: bella},
+	}
+	searchRepository := repository.NewInMemorySearchRepository([]*model.Singer{alice, bella}, albums)
+
+	page, err := searchRepository.Search(context.Background(), &model.SearchQuery{Query: "ALI", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, model.SearchResultAlbum, page.Items[0].Type)
+	assert.Equal(t, 1, page.Items[0].ID)
+	assert.Equal(t, alice, page.Items[0].Singer)
+	assert.Equal(t, model.SearchResultSinger, page.Items[1].Type)
+	assert.Nil(t, page.NextCursor)
+
+	q := &model.SearchQuery{Query: "album", Types: []model.SearchResultType{model.SearchResultAlbum}, Limit: 1}
+	page, err = searchRepository.Search(context.Background(), q)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, 1, page.Items[0].ID)
+	assert.Equal(t, &model.Cursor{Sort: "relevance", Offset: 1}, page.NextCursor)
+
+	q.Cursor = page.NextCursor
+	page, err = searchRepository.Search(context.Background(), q)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, 3, page.Items[0].ID)
+	assert.Nil(t, page.NextCursor)
+}
+
+func TestInMemorySearchRepository_SkipsTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	alice := &model.Singer{ID: model.SingerID(1), Name: "Alice"}
+	alicia := &model.Singer{ID: model.SingerID(6), Name: "Alicia", DeletedAt: &deletedAt}
+	albums := []*model.Album{
+		{ID: model.AlbumID(1), Title: "Alice's 1st Album", SingerID: alice.ID, Singer: alice, DeletedAt: &deletedAt},
+	}
+	searchRepository := repository.NewInMemorySearchRepository([]*model.Singer{alice, alicia}, albums)
+
+	page, err := searchRepository.Search(context.Background(), &model.SearchQuery{Query: "ali", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, model.SearchResultSinger, page.Items[0].Type)
+	assert.Equal(t, 1, page.Items[0].ID)
+}
@@ -0,0 +1,97 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SearchRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	searchRepository repository.SearchRepository
+}
+
+func TestSearchRepositorySuite(t *testing.T) {
+	suite.Run(t, new(SearchRepositorySuite))
+}
+
+func (suite *SearchRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.searchRepository = repository.NewSearchRepository(suite.DB)
+}
+
+func (suite *SearchRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.searchRepository = repository.NewSearchRepository(mockDB)
+	return mock
+}
+
+func (suite *SearchRepositorySuite) TestSearchRepositorySearch() {
+	ctx := context.Background()
+
+	rows := sqlmock.NewRows([]string{"type", "id", "label", "singer_id", "singer_name", "score"}).
+		AddRow("singer", 1, "Alice", 1, "Alice", 1.5).
+		AddRow("album", 2, "Alice's 2nd Album", 1, "Alice", 0.8).
+		AddRow("album", 1, "Alice's 1st Album", 1, "Alice", 0.8)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT type, id, label, singer_id, singer_name, score FROM ("+
+			" SELECT 'singer' AS type, s.id, s.name AS label, s.id AS singer_id, s.name AS singer_name,"+
+			" MATCH(s.name) AGAINST (? IN NATURAL LANGUAGE MODE) AS score FROM singers s"+
+			" WHERE MATCH(s.name) AGAINST (? IN NATURAL LANGUAGE MODE) AND s.deleted_at IS NULL UNION ALL"+
+			" SELECT 'album' AS type, a.id, a.title AS label, s.id AS singer_id, s.name AS singer_name,"+
+			" MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AS score FROM albums a"+
+			" JOIN singers s ON a.singer_id = s.id WHERE MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AND a.deleted_at IS NULL"+
+			") results ORDER BY score DESC, type, id LIMIT ? OFFSET ?",
+	).WithArgs("ali", "ali", "ali", "ali", 3, 0).WillReturnRows(rows)
+
+	q := &model.SearchQuery{Query: "ali", Limit: 2}
+	page, err := suite.searchRepository.Search(ctx, q)
+	suite.NoError(err)
+
+	suite.Len(page.Items, 2)
+	suite.Equal(model.SearchResultSinger, page.Items[0].Type)
+	suite.Nil(page.Items[0].Singer)
+	suite.Equal(model.SearchResultAlbum, page.Items[1].Type)
+	suite.Equal("Alice's 2nd Album", page.Items[1].Name)
+	suite.Equal("Alice", page.Items[1].Singer.Name)
+	suite.Equal(&model.Cursor{Sort: "relevance", Offset: 2}, page.NextCursor)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SearchRepositorySuite) TestSearchRepositorySearch_AlbumsOnly() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT type, id, label, singer_id, singer_name, score FROM ("+
+			" SELECT 'album' AS type, a.id, a.title AS label, s.id AS singer_id, s.name AS singer_name,"+
+			" MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AS score FROM albums a"+
+			" JOIN singers s ON a.singer_id = s.id WHERE MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AND a.deleted_at IS NULL"+
+			") results ORDER BY score DESC, type, id LIMIT ? OFFSET ?",
+	).WithArgs("album", "album", 21, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "id", "label", "singer_id", "singer_name", "score"}))
+
+	q := &model.SearchQuery{
+		Query:  "album",
+		Types:  []model.SearchResultType{model.SearchResultAlbum},
+		Limit:  20,
+		Cursor: &model.Cursor{Sort: "relevance", Offset: 20},
+	}
+	page, err := suite.searchRepository.Search(ctx, q)
+	suite.NoError(err)
+	suite.Empty(page.Items)
+	suite.Nil(page.NextCursor)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
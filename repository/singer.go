@@ -4,16 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log/slog"
+	"fmt"
+	"time"
 
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
 )
 
 type SingerRepository interface {
-	GetAll(ctx context.Context) ([]*model.Singer, error)
+	GetAll(ctx context.Context, q *model.SingerQuery) (*model.Page[*model.Singer], error)
+	Export(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) error
 	Get(ctx context.Context, id model.SingerID) (*model.Singer, error)
+	GetByName(ctx context.Context, name string) (*model.Singer, error)
 	Add(ctx context.Context, singer *model.Singer) error
-	Delete(ctx context.Context, id model.SingerID) error
+	// AddIfAbsent adds singer unless a live singer already has its name, in which case singer
+	// is filled in from that one. It reports whether singer was added.
+	AddIfAbsent(ctx context.Context, singer *model.Singer) (bool, error)
+	AddBatch(ctx context.Context, singers []*model.Singer) error
+	Update(ctx context.Context, singer *model.Singer) error
+	Delete(ctx context.Context, id model.SingerID, version int64) error
+	Restore(ctx context.Context, id model.SingerID) error
+	GetDeleted(ctx context.Context) ([]*model.Singer, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 type singerRepository struct {
 	db *sql.DB
@@ -26,70 +38,396 @@ func NewSingerRepository(db *sql.DB) SingerRepository {
 		db: db,
 	}
 }
-func (r *singerRepository) GetAll(ctx context.Context) ([]*model.Singer, error) {
-	query := `SELECT id, name FROM singers ORDER BY id`
-	rows, err := r.db.QueryContext(ctx, query)
+
+var singerSortColumns = map[model.SingerSortField]string{
+	model.SingerSortID:        "id",
+	model.SingerSortName:      "name",
+	model.SingerSortCreatedAt: "created_at",
+}
+
+func (r *singerRepository) GetAll(ctx context.Context, q *model.SingerQuery) (*model.Page[*model.Singer], error) {
+	column, ok := singerSortColumns[q.Sort]
+	if !ok {
+		return nil, model.NewError(model.KindValidation, "unsupported sort field", nil)
+	}
+
+	lq := singerFilters(q)
+	if q.Cursor != nil {
+		value, err := cursorValue(q.Cursor, q.Sort == model.SingerSortCreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		lq.after(column, "id", q.Cursor, value)
+	}
+
+	query := fmt.Sprintf(`SELECT id, name, created_at FROM singers %s %s LIMIT ?`,
+		lq.whereClause(), orderBy(column, "id", q.Desc))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, append(lq.args, q.Limit+1)...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, nil)
 	}
 	defer func() {
 		if err = rows.Close(); err != nil {
-			slog.Error("failed to close rows", "error", err)
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
 		}
 	}()
 
 	singers := make([]*model.Singer, 0)
 	for rows.Next() {
 		singer := model.Singer{}
-		if err = rows.Scan(&singer.ID, &singer.Name); err != nil {
-			return nil, err
+		if err = rows.Scan(&singer.ID, &singer.Name, &singer.CreatedAt); err != nil {
+			return nil, translateError(err, nil)
 		}
 		singers = append(singers, &singer)
 	}
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err, nil)
 	}
 
-	return singers, nil
+	page := &model.Page[*model.Singer]{Items: singers}
+	if len(singers) > q.Limit {
+		page.Items = singers[:q.Limit]
+		last := page.Items[q.Limit-1]
+		page.NextCursor = &model.Cursor{Sort: string(q.Sort), Desc: q.Desc, Value: singerSortValue(last, q.Sort), ID: int(last.ID)}
+	}
+	return page, nil
+}
+
+// singerFilters holds the conditions shared by the listing and the export.
+func singerFilters(q *model.SingerQuery) *listQuery {
+	lq := &listQuery{}
+	lq.where("deleted_at IS NULL")
+	if q.NamePrefix != "" {
+		lq.where("name LIKE ?", escapeLike(q.NamePrefix)+"%")
+	}
+	if q.CreatedAfter != nil {
+		lq.where("created_at > ?", *q.CreatedAfter)
+	}
+	return lq
+}
+
+// Export calls fn for every singer matching q, in q's order, as the rows arrive. Limit and
+// Cursor are ignored. An error from fn stops the export and is returned as is.
+func (r *singerRepository) Export(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) error {
+	column, ok := singerSortColumns[q.Sort]
+	if !ok {
+		return model.NewError(model.KindValidation, "unsupported sort field", nil)
+	}
+
+	lq := singerFilters(q)
+	query := fmt.Sprintf(`SELECT id, name, created_at, version FROM singers %s %s`,
+		lq.whereClause(), orderBy(column, "id", q.Desc))
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, lq.args...)
+	if err != nil {
+		return translateError(err, nil)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		singer := model.Singer{}
+		if err = rows.Scan(&singer.ID, &singer.Name, &singer.CreatedAt, &singer.Version); err != nil {
+			return translateError(err, nil)
+		}
+		if err = fn(&singer); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+func singerSortValue(singer *model.Singer, sort model.SingerSortField) string {
+	switch sort {
+	case model.SingerSortName:
+		return singer.Name
+	case model.SingerSortCreatedAt:
+		return singer.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return ""
 }
 
 func (r *singerRepository) Get(ctx context.Context, id model.SingerID) (*model.Singer, error) {
-	query := `SELECT id, name FROM singers WHERE id = ?`
+	query := `SELECT id, name, created_at, version FROM singers WHERE id = ? AND deleted_at IS NULL`
 	singer := model.Singer{}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&singer.ID, &singer.Name)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&singer.ID, &singer.Name, &singer.CreatedAt, &singer.Version)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrorSingerNotFound
 	} else if err != nil {
-		return nil, err
+		return nil, translateError(err, nil)
 	}
 
 	return &singer, nil
 }
 
+// GetByName finds the live singer called name.
+func (r *singerRepository) GetByName(ctx context.Context, name string) (*model.Singer, error) {
+	query := `SELECT id, name, created_at, version FROM singers WHERE name = ? AND deleted_at IS NULL`
+	singer := model.Singer{}
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, name)
+	if err := row.Scan(&singer.ID, &singer.Name, &singer.CreatedAt, &singer.Version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrorSingerNotFound
+		}
+		return nil, translateError(err, nil)
+	}
+
+	return &singer, nil
+}
+
+// Add inserts singer. When singer.ID is zero the database allocates the id and it is written back to singer.
 func (r *singerRepository) Add(ctx context.Context, singer *model.Singer) error {
-	query := `INSERT INTO singers (id, name) VALUES (?, ?)`
-	if _, err := r.db.ExecContext(ctx, query, singer.ID, singer.Name); err != nil {
-		return err
+	query := `INSERT INTO singers (name) VALUES (?)`
+	args := []any{singer.Name}
+	if singer.ID != 0 {
+		query = `INSERT INTO singers (id, name) VALUES (?, ?)`
+		args = []any{singer.ID, singer.Name}
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateSingerWriteError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return translateError(err, nil)
+	}
+	singer.ID = model.SingerID(id)
+	singer.Version = 1
+	return nil
+}
+
+// AddIfAbsent lets the unique name key settle races between concurrent callers. The loser
+// reads the winner's row with a locking read, which sees it although it was committed after
+// the loser's transaction took its snapshot.
+func (r *singerRepository) AddIfAbsent(ctx context.Context, singer *model.Singer) (bool, error) {
+	err := r.Add(ctx, singer)
+	if !errors.Is(err, ErrorSingerNameTaken) {
+		return err == nil, err
+	}
+
+	query := `SELECT id, created_at, version FROM singers WHERE name = ? AND deleted_at IS NULL FOR SHARE`
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, singer.Name)
+	if scanErr := row.Scan(&singer.ID, &singer.CreatedAt, &singer.Version); scanErr != nil {
+		if errors.Is(scanErr, sql.ErrNoRows) {
+			// Trashed again in the meantime.
+			return false, err
+		}
+		return false, translateError(scanErr, nil)
+	}
+	return false, nil
+}
+
+func translateSingerWriteError(err error) error {
+	return translateDuplicate(err, singerNameKey, ErrorSingerNameTaken, mysqlErrors{mysqlErrDupEntry: ErrorSingerAlreadyExists})
+}
+
+// AddBatch inserts singers with multi-row INSERT statements, one for the singers with an id
+// and one for those without. Any failure fails the whole statement. Run it within
+// Transactor.WithinTx so the generated ids are read back from rows no one else can change yet.
+func (r *singerRepository) AddBatch(ctx context.Context, singers []*model.Singer) error {
+	var withID, withoutID []*model.Singer
+	for _, singer := range singers {
+		if singer.ID != 0 {
+			withID = append(withID, singer)
+		} else {
+			withoutID = append(withoutID, singer)
+		}
+	}
+
+	if len(withID) > 0 {
+		args := make([]any, 0, len(withID)*2)
+		for _, singer := range withID {
+			args = append(args, singer.ID, singer.Name)
+		}
+		query := `INSERT INTO singers (id, name) VALUES ` + placeholders(len(withID), 2)
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+			return translateSingerWriteError(err)
+		}
+	}
+	if len(withoutID) > 0 {
+		args := make([]any, 0, len(withoutID))
+		for _, singer := range withoutID {
+			args = append(args, singer.Name)
+		}
+		query := `INSERT INTO singers (name) VALUES ` + placeholders(len(withoutID), 1)
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+			return translateSingerWriteError(err)
+		}
+		if err := r.readBackIDs(ctx, withoutID, args); err != nil {
+			return err
+		}
+	}
+
+	for _, singer := range singers {
+		singer.Version = 1
 	}
 	return nil
 }
 
-func (r *singerRepository) Delete(ctx context.Context, id model.SingerID) error {
-	query := `DELETE FROM singers WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, id)
+// readBackIDs fills in the ids generated for singers by their live name, which the unique key
+// makes unambiguous. A multi-row INSERT need not generate consecutive ids, e.g. with
+// innodb_autoinc_lock_mode=2 or an auto_increment_increment other than 1.
+func (r *singerRepository) readBackIDs(ctx context.Context, singers []*model.Singer, names []any) error {
+	query := `SELECT id, name FROM singers WHERE name IN (` + placeholderList(len(names)) + `) AND deleted_at IS NULL`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, names...)
 	if err != nil {
-		return err
+		return translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	ids := make(map[string]model.SingerID, len(names))
+	for rows.Next() {
+		var id model.SingerID
+		var name string
+		if err = rows.Scan(&id, &name); err != nil {
+			return translateError(err, nil)
+		}
+		ids[name] = id
+	}
+	if err = rows.Err(); err != nil {
+		return translateError(err, nil)
+	}
+
+	for _, singer := range singers {
+		id, ok := ids[singer.Name]
+		if !ok {
+			return model.NewError(model.KindInternal, "inserted singer not found", nil)
+		}
+		singer.ID = id
+	}
+	return nil
+}
+
+// Update writes singer if it is still at singer.Version and advances the version.
+func (r *singerRepository) Update(ctx context.Context, singer *model.Singer) error {
+	query := `UPDATE singers SET name = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, singer.Name, singer.ID, singer.Version)
+	if err != nil {
+		return translateSingerWriteError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	if rowsAffected == 0 {
+		if _, err = r.Get(ctx, singer.ID); err != nil {
+			return err
+		}
+		return ErrorSingerVersionMismatch
+	}
+
+	singer.Version++
+	return nil
+}
+
+// Delete moves the singer to the trash. A singer whose albums are still live stays put,
+// as it did when the foreign key blocked the hard delete.
+func (r *singerRepository) Delete(ctx context.Context, id model.SingerID, version int64) error {
+	query := `
+		UPDATE singers SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM albums WHERE singer_id = ? AND deleted_at IS NULL)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, version, id)
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	if rowsAffected == 0 {
+		current, err := r.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if current.Version != version {
+			return ErrorSingerVersionMismatch
+		}
+		return ErrorSingerHasAlbums
+	}
+
+	return nil
+}
+
+func (r *singerRepository) Restore(ctx context.Context, id model.SingerID) error {
+	query := `UPDATE singers SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return translateSingerWriteError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return translateError(err, nil)
 	}
 
 	if rowsAffected == 0 {
-		return ErrorSingerNotFound
+		return ErrorSingerNotInTrash
 	}
 
 	return nil
 }
+
+func (r *singerRepository) GetDeleted(ctx context.Context) ([]*model.Singer, error) {
+	query := `SELECT id, name, created_at, deleted_at FROM singers WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	singers := make([]*model.Singer, 0)
+	for rows.Next() {
+		singer := model.Singer{}
+		if err = rows.Scan(&singer.ID, &singer.Name, &singer.CreatedAt, &singer.DeletedAt); err != nil {
+			return nil, translateError(err, nil)
+		}
+		singers = append(singers, &singer)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+	return singers, nil
+}
+
+// Purge hard-deletes singers trashed before deletedBefore. Singers still referenced by an
+// album or credit, even a trashed one, are kept until those rows are purged.
+func (r *singerRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `
+		DELETE FROM singers
+		WHERE deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM albums WHERE albums.singer_id = singers.id)
+			AND NOT EXISTS (SELECT 1 FROM album_credits WHERE album_credits.singer_id = singers.id)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, translateError(err, nil)
+	}
+	return purged, nil
+}
@@ -2,12 +2,15 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
 	"github.com/pulse227/server-recruit-challenge-sample/repository"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type SingerRepositorySuite struct {
@@ -44,17 +47,20 @@ func (suite *SingerRepositorySuite) TestSingerRepositoryGetAll() {
 		{ID: model.SingerID(2), Name: "Test Singer 2"},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name"})
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at"})
 	for _, singer := range singers {
-		rows.AddRow(singer.ID, singer.Name)
+		rows.AddRow(singer.ID, singer.Name, singer.CreatedAt)
 	}
 
 	mock := suite.MockDB()
-	mock.ExpectQuery("SELECT id, name FROM singers ORDER BY id").
+	mock.ExpectQuery("SELECT id, name, created_at FROM singers WHERE deleted_at IS NULL ORDER BY id ASC LIMIT ?").
+		WithArgs(model.DefaultPageLimit + 1).
 		WillReturnRows(rows)
 
-	result, err := suite.singerRepository.GetAll(ctx)
+	q := &model.SingerQuery{Limit: model.DefaultPageLimit, Sort: model.SingerSortID}
+	page, err := suite.singerRepository.GetAll(ctx, q)
 	suite.NoError(err)
+	result := page.Items
 
 	suite.Len(result, len(singers))
 	for i, singer := range singers {
@@ -71,12 +77,12 @@ func (suite *SingerRepositorySuite) TestSingerRepositoryGet() {
 
 	singer := &model.Singer{ID: model.SingerID(1), Name: "Test Singer"}
 
-	rows := sqlmock.NewRows([]string{"id", "name"}).
-		AddRow(singer.ID, singer.Name)
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "version"}).
+		AddRow(singer.ID, singer.Name, singer.CreatedAt, 2)
 
 	mock := suite.MockDB()
 
-	mock.ExpectQuery("SELECT id, name FROM singers WHERE id = ?").
+	mock.ExpectQuery("SELECT id, name, created_at, version FROM singers WHERE id = ? AND deleted_at IS NULL").
 		WithArgs(singer.ID).
 		WillReturnRows(rows)
 
@@ -86,6 +92,7 @@ func (suite *SingerRepositorySuite) TestSingerRepositoryGet() {
 	suite.NotNil(result)
 	suite.Equal(singer.ID, result.ID)
 	suite.Equal(singer.Name, result.Name)
+	suite.Equal(int64(2), result.Version)
 
 	err = mock.ExpectationsWereMet()
 	suite.NoError(err)
@@ -114,12 +121,372 @@ func (suite *SingerRepositorySuite) TestSingerRepository_Delete() {
 	singerID := model.SingerID(1)
 
 	mock := suite.MockDB()
-	mock.ExpectExec("DELETE FROM singers WHERE id = ?").
+	mock.ExpectExec(
+		"UPDATE singers SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND NOT EXISTS (SELECT 1 FROM albums WHERE singer_id = ? AND deleted_at IS NULL)",
+	).WithArgs(singerID, int64(1), singerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.singerRepository.Delete(ctx, singerID, 1)
+	suite.NoError(err)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Update() {
+	ctx := context.Background()
+
+	singer := &model.Singer{ID: model.SingerID(1), Name: "Updated Singer", Version: 3}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE singers SET name = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL").
+		WithArgs(singer.Name, singer.ID, singer.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.singerRepository.Update(ctx, singer)
+	suite.NoError(err)
+	suite.Equal(int64(4), singer.Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Update_NotFound() {
+	ctx := context.Background()
+
+	singer := &model.Singer{ID: model.SingerID(99), Name: "Missing Singer", Version: 1}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE singers SET name = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL").
+		WithArgs(singer.Name, singer.ID, singer.Version).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, name, created_at, version FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(singer.ID).
+		WillReturnError(sql.ErrNoRows)
+
+	err := suite.singerRepository.Update(ctx, singer)
+	suite.ErrorIs(err, repository.ErrorSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Update_VersionMismatch() {
+	ctx := context.Background()
+
+	singer := &model.Singer{ID: model.SingerID(1), Name: "Stale Name", Version: 1}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE singers SET name = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL").
+		WithArgs(singer.Name, singer.ID, singer.Version).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, name, created_at, version FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(singer.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "version"}).AddRow(1, "Alice", time.Now(), 2))
+
+	err := suite.singerRepository.Update(ctx, singer)
+	suite.ErrorIs(err, repository.ErrorSingerVersionMismatch)
+	suite.Equal(model.KindPreconditionFailed, model.KindOf(err))
+	suite.Equal(int64(1), singer.Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Delete_HasAlbums() {
+	ctx := context.Background()
+
+	singerID := model.SingerID(1)
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"UPDATE singers SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND NOT EXISTS (SELECT 1 FROM albums WHERE singer_id = ? AND deleted_at IS NULL)",
+	).WithArgs(singerID, int64(1), singerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, name, created_at, version FROM singers WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(singerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "version"}).AddRow(1, "Alice", time.Now(), 1))
+
+	err := suite.singerRepository.Delete(ctx, singerID, 1)
+	suite.ErrorIs(err, repository.ErrorSingerHasAlbums)
+	suite.Equal(model.KindConflict, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Add_GeneratedID() {
+	ctx := context.Background()
+
+	singer := &model.Singer{Name: "Test Singer"}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO singers (name) VALUES (?)").
+		WithArgs(singer.Name).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	err := suite.singerRepository.Add(ctx, singer)
+	suite.NoError(err)
+	suite.Equal(model.SingerID(7), singer.ID)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_GetByName() {
+	ctx := context.Background()
+	query := "SELECT id, name, created_at, version FROM singers WHERE name = ? AND deleted_at IS NULL"
+	now := time.Now()
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(query).
+		WithArgs("Alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "version"}).AddRow(3, "Alice", now, 2))
+	mock.ExpectQuery(query).
+		WithArgs("Nobody").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "version"}))
+
+	singer, err := suite.singerRepository.GetByName(ctx, "Alice")
+	suite.NoError(err)
+	suite.Equal(model.SingerID(3), singer.ID)
+
+	_, err = suite.singerRepository.GetByName(ctx, "Nobody")
+	suite.ErrorIs(err, repository.ErrorSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Add_NameTaken() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO singers (name) VALUES (?)").
+		WithArgs("Alice").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Alice-1' for key 'singers.uq_singers_name'"})
+
+	err := suite.singerRepository.Add(ctx, &model.Singer{Name: "Alice"})
+	suite.ErrorIs(err, repository.ErrorSingerNameTaken)
+	suite.Equal(model.KindConflict, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_AddIfAbsent() {
+	ctx := context.Background()
+	now := time.Now()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO singers (name) VALUES (?)").
+		WithArgs("Alice").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO singers (name) VALUES (?)").
+		WithArgs("Bob").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Bob-1' for key 'singers.uq_singers_name'"})
+	mock.ExpectQuery("SELECT id, created_at, version FROM singers WHERE name = ? AND deleted_at IS NULL FOR SHARE").
+		WithArgs("Bob").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "version"}).AddRow(4, now, 3))
+
+	alice := &model.Singer{Name: "Alice"}
+	added, err := suite.singerRepository.AddIfAbsent(ctx, alice)
+	suite.NoError(err)
+	suite.True(added)
+	suite.Equal(model.SingerID(7), alice.ID)
+
+	bob := &model.Singer{Name: "Bob"}
+	added, err = suite.singerRepository.AddIfAbsent(ctx, bob)
+	suite.NoError(err)
+	suite.False(added)
+	suite.Equal(model.SingerID(4), bob.ID)
+	suite.Equal(int64(3), bob.Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Restore_NameTaken() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE singers SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL").
+		WithArgs(model.SingerID(2)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Alice-1' for key 'singers.uq_singers_name'"})
+
+	err := suite.singerRepository.Restore(ctx, model.SingerID(2))
+	suite.ErrorIs(err, repository.ErrorSingerNameTaken)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_AddBatch() {
+	ctx := context.Background()
+
+	singers := []*model.Singer{
+		{Name: "Alice"},
+		{ID: model.SingerID(50), Name: "Bob"},
+		{Name: "Carol"},
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO singers (id, name) VALUES (?, ?)").
+		WithArgs(model.SingerID(50), "Bob").
+		WillReturnResult(sqlmock.NewResult(50, 1))
+	mock.ExpectExec("INSERT INTO singers (name) VALUES (?), (?)").
+		WithArgs("Alice", "Carol").
+		WillReturnResult(sqlmock.NewResult(7, 2))
+	// not consecutive, as with innodb_autoinc_lock_mode=2 under concurrent inserts
+	mock.ExpectQuery("SELECT id, name FROM singers WHERE name IN (?, ?) AND deleted_at IS NULL").
+		WithArgs("Alice", "Carol").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(9, "Carol").
+			AddRow(7, "Alice"))
+
+	err := suite.singerRepository.AddBatch(ctx, singers)
+	suite.NoError(err)
+	suite.Equal(model.SingerID(7), singers[0].ID)
+	suite.Equal(model.SingerID(50), singers[1].ID)
+	suite.Equal(model.SingerID(9), singers[2].ID)
+	suite.Equal(int64(1), singers[2].Version)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_AddBatch_RowMissing() {
+	ctx := context.Background()
+
+	singers := []*model.Singer{{Name: "Alice"}, {Name: "Carol"}}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO singers (name) VALUES (?), (?)").
+		WithArgs("Alice", "Carol").
+		WillReturnResult(sqlmock.NewResult(7, 2))
+	mock.ExpectQuery("SELECT id, name FROM singers WHERE name IN (?, ?) AND deleted_at IS NULL").
+		WithArgs("Alice", "Carol").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Alice"))
+
+	err := suite.singerRepository.AddBatch(ctx, singers)
+	suite.Error(err)
+	suite.Equal(model.KindInternal, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepositoryGetAll_NextCursor() {
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "name", "created_at"}).
+		AddRow(3, "Chris", createdAt).
+		AddRow(4, "Daisy", createdAt.Add(time.Minute))
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id, name, created_at FROM singers WHERE deleted_at IS NULL ORDER BY created_at ASC, id ASC LIMIT ?").
+		WithArgs(2).
+		WillReturnRows(rows)
+
+	q := &model.SingerQuery{Limit: 1, Sort: model.SingerSortCreatedAt}
+	page, err := suite.singerRepository.GetAll(ctx, q)
+	suite.NoError(err)
+	suite.Len(page.Items, 1)
+	suite.Equal(&model.Cursor{Sort: "created_at", Value: createdAt.Format(time.RFC3339Nano), ID: 3}, page.NextCursor)
+
+	mock.ExpectQuery("SELECT id, name, created_at FROM singers WHERE deleted_at IS NULL AND (created_at > ? OR (created_at = ? AND id > ?)) ORDER BY created_at ASC, id ASC LIMIT ?").
+		WithArgs(createdAt, createdAt, 3, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).AddRow(4, "Daisy", createdAt.Add(time.Minute)))
+
+	q.Cursor = page.NextCursor
+	page, err = suite.singerRepository.GetAll(ctx, q)
+	suite.NoError(err)
+	suite.Len(page.Items, 1)
+	suite.Nil(page.NextCursor)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Delete_NotFound() {
+	ctx := context.Background()
+
+	singerID := model.SingerID(99)
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"UPDATE singers SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL "+
+			"AND NOT EXISTS (SELECT 1 FROM albums WHERE singer_id = ? AND deleted_at IS NULL)",
+	).WithArgs(singerID, int64(1), singerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, name, created_at, version FROM singers WHERE id = ? AND deleted_at IS NULL").
 		WithArgs(singerID).
+		WillReturnError(sql.ErrNoRows)
+
+	err := suite.singerRepository.Delete(ctx, singerID, 1)
+	suite.ErrorIs(err, repository.ErrorSingerNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Restore() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE singers SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL").
+		WithArgs(model.SingerID(1)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE singers SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL").
+		WithArgs(model.SingerID(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.singerRepository.Restore(ctx, model.SingerID(1))
+	suite.NoError(err)
+
+	err = suite.singerRepository.Restore(ctx, model.SingerID(2))
+	suite.ErrorIs(err, repository.ErrorSingerNotInTrash)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_GetDeleted() {
+	ctx := context.Background()
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
 
-	err := suite.singerRepository.Delete(ctx, singerID)
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id, name, created_at, deleted_at FROM singers WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "deleted_at"}).
+			AddRow(5, "Ellen", deletedAt.AddDate(-1, 0, 0), deletedAt))
+
+	singers, err := suite.singerRepository.GetDeleted(ctx)
+	suite.NoError(err)
+	suite.Len(singers, 1)
+	suite.Equal("Ellen", singers[0].Name)
+	suite.Equal(deletedAt, *singers[0].DeletedAt)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *SingerRepositorySuite) TestSingerRepository_Purge() {
+	ctx := context.Background()
+	before := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+
+	mock := suite.MockDB()
+	mock.ExpectExec(
+		"DELETE FROM singers WHERE deleted_at < ? " +
+			"AND NOT EXISTS (SELECT 1 FROM albums WHERE albums.singer_id = singers.id) " +
+			"AND NOT EXISTS (SELECT 1 FROM album_credits WHERE album_credits.singer_id = singers.id)",
+	).WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := suite.singerRepository.Purge(ctx, before)
 	suite.NoError(err)
+	suite.Equal(int64(3), purged)
 
 	err = mock.ExpectationsWereMet()
 	suite.NoError(err)
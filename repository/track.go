@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+type TrackRepository interface {
+	GetByAlbum(ctx context.Context, albumID model.AlbumID) ([]*model.Track, error)
+	Get(ctx context.Context, id model.TrackID) (*model.Track, error)
+	Add(ctx context.Context, track *model.Track) error
+	Update(ctx context.Context, track *model.Track) error
+	Delete(ctx context.Context, id model.TrackID) error
+	// LockAlbum locks the live album until the transaction ctx carries ends, so that it is
+	// neither trashed nor given tracks in the meantime.
+	LockAlbum(ctx context.Context, albumID model.AlbumID) error
+	Reorder(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) error
+}
+
+type trackRepository struct {
+	db *sql.DB
+}
+
+var _ TrackRepository = (*trackRepository)(nil)
+
+func NewTrackRepository(db *sql.DB) TrackRepository {
+	return &trackRepository{
+		db: db,
+	}
+}
+
+func (r *trackRepository) GetByAlbum(ctx context.Context, albumID model.AlbumID) ([]*model.Track, error) {
+	query := `
+		SELECT id, album_id, disc_number, track_number, title, duration_seconds, isrc, explicit
+		FROM tracks
+		WHERE album_id = ?
+		ORDER BY disc_number, track_number
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, albumID)
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to close rows", "error", err)
+		}
+	}()
+
+	tracks := make([]*model.Track, 0)
+	for rows.Next() {
+		track := model.Track{}
+		if err = rows.Scan(
+			&track.ID, &track.AlbumID, &track.DiscNumber, &track.TrackNumber,
+			&track.Title, &track.DurationSeconds, &track.ISRC, &track.Explicit,
+		); err != nil {
+			return nil, translateError(err, nil)
+		}
+		tracks = append(tracks, &track)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, translateError(err, nil)
+	}
+	return tracks, nil
+}
+
+func (r *trackRepository) Get(ctx context.Context, id model.TrackID) (*model.Track, error) {
+	query := `
+		SELECT t.id, t.album_id, t.disc_number, t.track_number, t.title, t.duration_seconds, t.isrc, t.explicit
+		FROM tracks t
+		JOIN albums a ON t.album_id = a.id
+		WHERE t.id = ? AND a.deleted_at IS NULL
+	`
+	track := model.Track{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&track.ID, &track.AlbumID, &track.DiscNumber, &track.TrackNumber,
+		&track.Title, &track.DurationSeconds, &track.ISRC, &track.Explicit,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrorTrackNotFound
+	} else if err != nil {
+		return nil, translateError(err, nil)
+	}
+	return &track, nil
+}
+
+// Add inserts track unless its album is missing or in the trash.
+func (r *trackRepository) Add(ctx context.Context, track *model.Track) error {
+	query := `
+		INSERT INTO tracks (album_id, disc_number, track_number, title, duration_seconds, isrc, explicit)
+		SELECT id, ?, ?, ?, ?, ?, ? FROM albums WHERE id = ? AND deleted_at IS NULL
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		track.DiscNumber, track.TrackNumber, track.Title, track.DurationSeconds, track.ISRC, track.Explicit, track.AlbumID)
+	if err != nil {
+		return translateError(err, mysqlErrors{
+			mysqlErrDupEntry:        ErrorTrackPositionTaken,
+			mysqlErrNoReferencedRow: ErrorReferencedAlbumNotFound,
+		})
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+	if rowsAffected == 0 {
+		return ErrorAlbumNotFound
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return translateError(err, nil)
+	}
+	track.ID = model.TrackID(id)
+	return nil
+}
+
+func (r *trackRepository) Update(ctx context.Context, track *model.Track) error {
+	query := `
+		UPDATE tracks t
+		JOIN albums a ON t.album_id = a.id
+		SET t.disc_number = ?, t.track_number = ?, t.title = ?, t.duration_seconds = ?, t.isrc = ?, t.explicit = ?
+		WHERE t.id = ? AND a.deleted_at IS NULL
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		track.DiscNumber, track.TrackNumber, track.Title, track.DurationSeconds, track.ISRC, track.Explicit, track.ID)
+	if err != nil {
+		return translateError(err, mysqlErrors{mysqlErrDupEntry: ErrorTrackPositionTaken})
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	if rowsAffected == 0 {
+		return r.notWritten(ctx, track.ID)
+	}
+
+	return nil
+}
+
+func (r *trackRepository) Delete(ctx context.Context, id model.TrackID) error {
+	query := `DELETE t FROM tracks t JOIN albums a ON t.album_id = a.id WHERE t.id = ? AND a.deleted_at IS NULL`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, nil)
+	}
+
+	if rowsAffected == 0 {
+		return r.notWritten(ctx, id)
+	}
+
+	return nil
+}
+
+// notWritten tells why a write to the track matched no row: the track is missing, or its
+// album is in the trash.
+func (r *trackRepository) notWritten(ctx context.Context, id model.TrackID) error {
+	var exists int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT 1 FROM tracks WHERE id = ?`, id).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorTrackNotFound
+	} else if err != nil {
+		return translateError(err, nil)
+	}
+	return ErrorAlbumNotFound
+}
+
+func (r *trackRepository) LockAlbum(ctx context.Context, albumID model.AlbumID) error {
+	var id model.AlbumID
+	query := `SELECT id FROM albums WHERE id = ? AND deleted_at IS NULL FOR UPDATE`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, albumID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorAlbumNotFound
+	} else if err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+// Reorder moves the album's tracks to new positions. Run it within Transactor.WithinTx after
+// LockAlbum. Track numbers are negated first so that swapping two tracks never trips the
+// unique position key midway; a track positions leaves out would keep its negated number,
+// so that fails with ErrorTrackNotReordered.
+func (r *trackRepository) Reorder(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) error {
+	tx := conn(ctx, r.db)
+	query := `UPDATE tracks SET track_number = -track_number WHERE album_id = ?`
+	if _, err := tx.ExecContext(ctx, query, albumID); err != nil {
+		return translateError(err, nil)
+	}
+
+	query = `UPDATE tracks SET disc_number = ?, track_number = ? WHERE id = ? AND album_id = ?`
+	for _, p := range positions {
+		result, err := tx.ExecContext(ctx, query, p.DiscNumber, p.TrackNumber, p.TrackID, albumID)
+		if err != nil {
+			return translateError(err, mysqlErrors{mysqlErrDupEntry: ErrorTrackPositionTaken})
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return translateError(err, nil)
+		}
+		if rowsAffected == 0 {
+			return ErrorTrackNotFound
+		}
+	}
+
+	var stranded int
+	query = `SELECT COUNT(*) FROM tracks WHERE album_id = ? AND track_number < 0`
+	if err := tx.QueryRowContext(ctx, query, albumID).Scan(&stranded); err != nil {
+		return translateError(err, nil)
+	}
+	if stranded > 0 {
+		return ErrorTrackNotReordered
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TrackRepositorySuite struct {
+	mysqldb.DBMYSQLSuite
+	trackRepository repository.TrackRepository
+}
+
+func TestTrackRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TrackRepositorySuite))
+}
+
+func (suite *TrackRepositorySuite) SetupSuite() {
+	suite.DBMYSQLSuite.SetupSuite()
+	suite.trackRepository = repository.NewTrackRepository(suite.DB)
+}
+
+func (suite *TrackRepositorySuite) MockDB() sqlmock.Sqlmock {
+	mockDB, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	suite.trackRepository = repository.NewTrackRepository(mockDB)
+	return mock
+}
+
+func (suite *TrackRepositorySuite) AfterTest() {
+	suite.trackRepository = repository.NewTrackRepository(suite.DB)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryGetByAlbum() {
+	ctx := context.Background()
+	albumID := model.AlbumID(1)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery(
+		"SELECT id, album_id, disc_number, track_number, title, duration_seconds, isrc, explicit FROM tracks WHERE album_id = ? ORDER BY disc_number, track_number",
+	).WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "album_id", "disc_number", "track_number", "title", "duration_seconds", "isrc", "explicit"}).
+			AddRow(1, 1, 1, 1, "Opening", 95, "JPABC2400001", false).
+			AddRow(2, 1, 1, 2, "Closing", 240, "", true))
+
+	tracks, err := suite.trackRepository.GetByAlbum(ctx, albumID)
+	suite.NoError(err)
+	suite.Len(tracks, 2)
+	suite.Equal("Opening", tracks[0].Title)
+	suite.True(tracks[1].Explicit)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryAdd() {
+	ctx := context.Background()
+	track := &model.Track{AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 3, Title: "Encore", DurationSeconds: 180}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO tracks (album_id, disc_number, track_number, title, duration_seconds, isrc, explicit) SELECT id, ?, ?, ?, ?, ?, ? FROM albums WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(track.DiscNumber, track.TrackNumber, track.Title, track.DurationSeconds, track.ISRC, track.Explicit, track.AlbumID).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	err := suite.trackRepository.Add(ctx, track)
+	suite.NoError(err)
+	suite.Equal(model.TrackID(4), track.ID)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryAdd_Errors() {
+	ctx := context.Background()
+	track := &model.Track{AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "Opening", DurationSeconds: 95}
+
+	tests := []struct {
+		name     string
+		mysqlErr *mysql.MySQLError
+		expected error
+	}{
+		{
+			name:     "position taken",
+			mysqlErr: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1-1-1' for key 'tracks.uq_tracks_position'"},
+			expected: repository.ErrorTrackPositionTaken,
+		},
+		{
+			name:     "album not found",
+			mysqlErr: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"},
+			expected: repository.ErrorReferencedAlbumNotFound,
+		},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			mock := suite.MockDB()
+			mock.ExpectExec("INSERT INTO tracks (album_id, disc_number, track_number, title, duration_seconds, isrc, explicit) SELECT id, ?, ?, ?, ?, ?, ? FROM albums WHERE id = ? AND deleted_at IS NULL").
+				WithArgs(track.DiscNumber, track.TrackNumber, track.Title, track.DurationSeconds, track.ISRC, track.Explicit, track.AlbumID).
+				WillReturnError(tt.mysqlErr)
+
+			err := suite.trackRepository.Add(ctx, track)
+			suite.ErrorIs(err, tt.expected)
+
+			err = mock.ExpectationsWereMet()
+			suite.NoError(err)
+		})
+	}
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryDelete_NotFound() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("DELETE t FROM tracks t JOIN albums a ON t.album_id = a.id WHERE t.id = ? AND a.deleted_at IS NULL").
+		WithArgs(model.TrackID(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT 1 FROM tracks WHERE id = ?").
+		WithArgs(model.TrackID(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	err := suite.trackRepository.Delete(ctx, model.TrackID(9))
+	suite.ErrorIs(err, repository.ErrorTrackNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryReorder() {
+	ctx := context.Background()
+	albumID := model.AlbumID(1)
+	positions := []model.TrackPosition{
+		{TrackID: model.TrackID(2), DiscNumber: 1, TrackNumber: 1},
+		{TrackID: model.TrackID(1), DiscNumber: 1, TrackNumber: 2},
+	}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE tracks SET track_number = -track_number WHERE album_id = ?").
+		WithArgs(albumID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	for _, p := range positions {
+		mock.ExpectExec("UPDATE tracks SET disc_number = ?, track_number = ? WHERE id = ? AND album_id = ?").
+			WithArgs(p.DiscNumber, p.TrackNumber, p.TrackID, albumID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectQuery("SELECT COUNT(*) FROM tracks WHERE album_id = ? AND track_number < 0").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+
+	err := suite.trackRepository.Reorder(ctx, albumID, positions)
+	suite.NoError(err)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryReorder_TrackNotFound() {
+	ctx := context.Background()
+	albumID := model.AlbumID(1)
+	positions := []model.TrackPosition{{TrackID: model.TrackID(7), DiscNumber: 1, TrackNumber: 1}}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE tracks SET track_number = -track_number WHERE album_id = ?").
+		WithArgs(albumID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE tracks SET disc_number = ?, track_number = ? WHERE id = ? AND album_id = ?").
+		WithArgs(1, 1, model.TrackID(7), albumID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.trackRepository.Reorder(ctx, albumID, positions)
+	suite.ErrorIs(err, repository.ErrorTrackNotFound)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryAdd_TrashedAlbum() {
+	ctx := context.Background()
+	track := &model.Track{AlbumID: model.AlbumID(5), DiscNumber: 1, TrackNumber: 1, Title: "Late", DurationSeconds: 120}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("INSERT INTO tracks (album_id, disc_number, track_number, title, duration_seconds, isrc, explicit) SELECT id, ?, ?, ?, ?, ?, ? FROM albums WHERE id = ? AND deleted_at IS NULL").
+		WithArgs(track.DiscNumber, track.TrackNumber, track.Title, track.DurationSeconds, track.ISRC, track.Explicit, track.AlbumID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.trackRepository.Add(ctx, track)
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+	suite.Equal(model.KindNotFound, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryUpdate_TrashedAlbum() {
+	ctx := context.Background()
+	track := &model.Track{ID: model.TrackID(3), DiscNumber: 1, TrackNumber: 3, Title: "Goodnight", DurationSeconds: 301}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE tracks t JOIN albums a ON t.album_id = a.id SET t.disc_number = ?, t.track_number = ?, t.title = ?, t.duration_seconds = ?, t.isrc = ?, t.explicit = ? WHERE t.id = ? AND a.deleted_at IS NULL").
+		WithArgs(track.DiscNumber, track.TrackNumber, track.Title, track.DurationSeconds, track.ISRC, track.Explicit, track.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT 1 FROM tracks WHERE id = ?").
+		WithArgs(track.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	err := suite.trackRepository.Update(ctx, track)
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+	suite.Equal(model.KindNotFound, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryDelete_TrashedAlbum() {
+	ctx := context.Background()
+
+	mock := suite.MockDB()
+	mock.ExpectExec("DELETE t FROM tracks t JOIN albums a ON t.album_id = a.id WHERE t.id = ? AND a.deleted_at IS NULL").
+		WithArgs(model.TrackID(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT 1 FROM tracks WHERE id = ?").
+		WithArgs(model.TrackID(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	err := suite.trackRepository.Delete(ctx, model.TrackID(3))
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+	suite.Equal(model.KindNotFound, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryReorder_TrackLeftOut() {
+	ctx := context.Background()
+	albumID := model.AlbumID(1)
+	positions := []model.TrackPosition{{TrackID: model.TrackID(1), DiscNumber: 1, TrackNumber: 1}}
+
+	mock := suite.MockDB()
+	mock.ExpectExec("UPDATE tracks SET track_number = -track_number WHERE album_id = ?").
+		WithArgs(albumID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE tracks SET disc_number = ?, track_number = ? WHERE id = ? AND album_id = ?").
+		WithArgs(1, 1, model.TrackID(1), albumID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT COUNT(*) FROM tracks WHERE album_id = ? AND track_number < 0").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+
+	err := suite.trackRepository.Reorder(ctx, albumID, positions)
+	suite.ErrorIs(err, repository.ErrorTrackNotReordered)
+	suite.Equal(model.KindConflict, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TrackRepositorySuite) TestTrackRepositoryLockAlbum_TrashedAlbum() {
+	ctx := context.Background()
+	albumID := model.AlbumID(5)
+
+	mock := suite.MockDB()
+	mock.ExpectQuery("SELECT id FROM albums WHERE id = ? AND deleted_at IS NULL FOR UPDATE").
+		WithArgs(albumID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := suite.trackRepository.LockAlbum(ctx, albumID)
+	suite.ErrorIs(err, repository.ErrorAlbumNotFound)
+	suite.Equal(model.KindNotFound, model.KindOf(err))
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+)
+
+// Transactor runs service-level units of work in one database transaction.
+type Transactor interface {
+	// WithinTx calls fn with a context carrying the transaction. Repositories given that
+	// context run their statements on it. A call nested inside another WithinTx joins the
+	// outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinReadOnlyTx is WithinTx for readers that need every statement to see one
+	// consistent snapshot.
+	WithinReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *sql.DB
+}
+
+var _ Transactor = (*transactor)(nil)
+
+func NewTransactor(db *sql.DB) Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+type txKey struct{}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.within(ctx, nil, fn)
+}
+
+func (t *transactor) WithinReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.within(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+func (t *transactor) within(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, opts)
+	if err != nil {
+		return translateError(err, nil)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return translateError(err, nil)
+	}
+	return nil
+}
+
+// querier is the part of *sql.DB and *sql.Tx the repositories use.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
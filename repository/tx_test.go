@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pulse227/server-recruit-challenge-sample/infra/mysqldb"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactorSuite struct {
+	suite.Suite
+}
+
+func TestTransactorSuite(t *testing.T) {
+	suite.Run(t, new(TransactorSuite))
+}
+
+func (suite *TransactorSuite) TestWithinTx_Commit() {
+	ctx := context.Background()
+	db, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE albums SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL").
+		WithArgs(model.AlbumID(1), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	albumRepository := repository.NewAlbumRepository(db)
+	err = repository.NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+		return albumRepository.Delete(ctx, 1, 1)
+	})
+	suite.NoError(err)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TransactorSuite) TestWithinTx_Rollback() {
+	ctx := context.Background()
+	db, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	cause := errors.New("boom")
+	err = repository.NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+		return cause
+	})
+	suite.ErrorIs(err, cause)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TransactorSuite) TestWithinTx_Nested() {
+	ctx := context.Background()
+	db, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	transactor := repository.NewTransactor(db)
+	err = transactor.WithinTx(ctx, func(ctx context.Context) error {
+		return transactor.WithinTx(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+	suite.NoError(err)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
+
+func (suite *TransactorSuite) TestWithinReadOnlyTx_Export() {
+	ctx := context.Background()
+	db, mock, err := mysqldb.MockDB()
+	suite.Require().NoError(err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, name, created_at, version FROM singers WHERE deleted_at IS NULL AND name LIKE ? ORDER BY name ASC, id ASC").
+		WithArgs("Al%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "version"}).
+			AddRow(1, "Alice", time.Now(), 1).
+			AddRow(4, "Alan", time.Now(), 3))
+	mock.ExpectCommit()
+
+	singerRepository := repository.NewSingerRepository(db)
+	var names []string
+	err = repository.NewTransactor(db).WithinReadOnlyTx(ctx, func(ctx context.Context) error {
+		q := &model.SingerQuery{Sort: model.SingerSortName, NamePrefix: "Al"}
+		return singerRepository.Export(ctx, q, func(singer *model.Singer) error {
+			names = append(names, singer.Name)
+			return nil
+		})
+	})
+	suite.NoError(err)
+	suite.Equal([]string{"Alice", "Alan"}, names)
+
+	err = mock.ExpectationsWereMet()
+	suite.NoError(err)
+}
@@ -7,28 +7,42 @@ import (
 )
 
 type AlbumService interface {
-	GetAlbumListService(ctx context.Context) ([]*model.Album, error)
+	GetAlbumListService(ctx context.Context, q *model.AlbumQuery) (*model.Page[*model.Album], error)
 	GetAlbumService(ctx context.Context, albumID model.AlbumID) (*model.Album, error)
+	GetAlbumWithTracksService(ctx context.Context, albumID model.AlbumID) (*model.Album, error)
+	// PostAlbumService and UpdateAlbumService leave album as stored, with its Singer.
 	PostAlbumService(ctx context.Context, album *model.Album) error
-	DeleteAlbumService(ctx context.Context, albumID model.AlbumID) error
+	UpdateAlbumService(ctx context.Context, album *model.Album) error
+	DeleteAlbumService(ctx context.Context, albumID model.AlbumID, version int64) error
+	RestoreAlbumService(ctx context.Context, albumID model.AlbumID) (*model.Album, error)
+	// ExportAlbumsService calls fn for every album matching q, all read from one snapshot.
+	ExportAlbumsService(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) error
+	BatchAlbumService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Album]) ([]*model.BatchResult, error)
 }
 
 type albumService struct {
 	albumRepository repository.AlbumRepository
+	trackRepository repository.TrackRepository
+	transactor      repository.Transactor
+	auditWriter     AuditWriter
 }
 
 var _ AlbumService = (*albumService)(nil)
 
-func NewAlbumService(albumRepository repository.AlbumRepository) AlbumService {
-	return &albumService{albumRepository: albumRepository}
+func NewAlbumService(albumRepository repository.AlbumRepository, trackRepository repository.TrackRepository, transactor repository.Transactor, auditWriter AuditWriter) AlbumService {
+	return &albumService{albumRepository: albumRepository, trackRepository: trackRepository, transactor: transactor, auditWriter: auditWriter}
 }
 
-func (s *albumService) GetAlbumListService(ctx context.Context) ([]*model.Album, error) {
-	albums, err := s.albumRepository.GetAll(ctx)
+func (s *albumService) GetAlbumListService(ctx context.Context, q *model.AlbumQuery) (*model.Page[*model.Album], error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.albumRepository.GetAll(ctx, q)
 	if err != nil {
 		return nil, err
 	}
-	return albums, nil
+	return page, nil
 }
 
 func (s *albumService) GetAlbumService(ctx context.Context, albumID model.AlbumID) (*model.Album, error) {
@@ -39,20 +53,135 @@ func (s *albumService) GetAlbumService(ctx context.Context, albumID model.AlbumI
 	return album, nil
 }
 
+func (s *albumService) GetAlbumWithTracksService(ctx context.Context, albumID model.AlbumID) (*model.Album, error) {
+	album, err := s.albumRepository.Get(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	album.Tracks, err = s.trackRepository.GetByAlbum(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+	return album, nil
+}
+
 func (s *albumService) PostAlbumService(ctx context.Context, album *model.Album) error {
 	if err := album.Validate(); err != nil {
 		return err
 	}
 
-	if err := s.albumRepository.Add(ctx, album); err != nil {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.albumRepository.Add(ctx, album); err != nil {
+			return err
+		}
+		return s.auditCreated(ctx, album)
+	})
+}
+
+// addAlbums works like singerService.addSingers.
+func (s *albumService) addAlbums(ctx context.Context, albums []*model.Album) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.albumRepository.AddBatch(ctx, albums); err != nil {
+			return err
+		}
+		for _, album := range albums {
+			if err := s.auditCreated(ctx, album); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// auditCreated also fills in album.Singer, which the album's ETag covers.
+func (s *albumService) auditCreated(ctx context.Context, album *model.Album) error {
+	after, err := s.albumRepository.Get(ctx, album.ID)
+	if err != nil {
 		return err
 	}
-	return nil
+	album.Singer = after.Singer
+	return writeAudit(ctx, s.auditWriter, model.AuditCreate, model.AuditEntityAlbum, int(album.ID), nil, after)
+}
+
+func (s *albumService) UpdateAlbumService(ctx context.Context, album *model.Album) error {
+	if err := album.Validate(); err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, version, err := loadForWrite(ctx, s.albumRepository.Get, album.ID, album.Version, albumVersion)
+		if err != nil {
+			return err
+		}
+
+		album.Version = version
+		if err = s.albumRepository.Update(ctx, album); err != nil {
+			return err
+		}
+		after, err := s.albumRepository.Get(ctx, album.ID)
+		if err != nil {
+			return err
+		}
+		album.Singer = after.Singer
+		return writeAudit(ctx, s.auditWriter, model.AuditUpdate, model.AuditEntityAlbum, int(album.ID), before, after)
+	})
+}
+
+func albumVersion(album *model.Album) int64 {
+	return album.Version
+}
+
+func (s *albumService) DeleteAlbumService(ctx context.Context, albumID model.AlbumID, version int64) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, version, err := loadForWrite(ctx, s.albumRepository.Get, albumID, version, albumVersion)
+		if err != nil {
+			return err
+		}
+
+		if err = s.albumRepository.Delete(ctx, albumID, version); err != nil {
+			return err
+		}
+		return writeAudit(ctx, s.auditWriter, model.AuditDelete, model.AuditEntityAlbum, int(albumID), before, nil)
+	})
+}
+
+func (s *albumService) RestoreAlbumService(ctx context.Context, albumID model.AlbumID) (*model.Album, error) {
+	var album *model.Album
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.albumRepository.Restore(ctx, albumID)
+		if err != nil {
+			return err
+		}
+		if album, err = s.albumRepository.Get(ctx, albumID); err != nil {
+			return err
+		}
+		return writeAudit(ctx, s.auditWriter, model.AuditRestore, model.AuditEntityAlbum, int(albumID), nil, album)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return album, nil
+}
+
+func (s *albumService) BatchAlbumService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Album]) ([]*model.BatchResult, error) {
+	return runBatch(ctx, s.transactor, mode, ops, batchSteps[*model.Album]{
+		validate: (*model.Album).Validate,
+		addAll:   s.addAlbums,
+		add:      s.PostAlbumService,
+		update:   s.UpdateAlbumService,
+		delete: func(ctx context.Context, album *model.Album) error {
+			return s.DeleteAlbumService(ctx, album.ID, album.Version)
+		},
+		id: func(album *model.Album) int { return int(album.ID) },
+	})
 }
 
-func (s *albumService) DeleteAlbumService(ctx context.Context, albumID model.AlbumID) error {
-	if err := s.albumRepository.Delete(ctx, albumID); err != nil {
+func (s *albumService) ExportAlbumsService(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) error {
+	if err := q.ValidateExport(); err != nil {
 		return err
 	}
-	return nil
+	return s.transactor.WithinReadOnlyTx(ctx, func(ctx context.Context) error {
+		return s.albumRepository.Export(ctx, q, fn)
+	})
 }
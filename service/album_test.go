@@ -2,11 +2,15 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
 	"github.com/pulse227/server-recruit-challenge-sample/service"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type MockAlbumRepository struct {
@@ -17,12 +21,23 @@ func NewMockAlbumRepository() *MockAlbumRepository {
 	return &MockAlbumRepository{}
 }
 
-func (m *MockAlbumRepository) GetAll(ctx context.Context) ([]*model.Album, error) {
-	args := m.Called(ctx)
+func (m *MockAlbumRepository) GetAll(ctx context.Context, q *model.AlbumQuery) (*model.Page[*model.Album], error) {
+	args := m.Called(ctx, q)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Album), args.Error(1)
+	return args.Get(0).(*model.Page[*model.Album]), args.Error(1)
+}
+func (m *MockAlbumRepository) Export(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) error {
+	args := m.Called(ctx, q)
+	if albums, ok := args.Get(0).([]*model.Album); ok {
+		for _, album := range albums {
+			if err := fn(album); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
 }
 func (m *MockAlbumRepository) Get(ctx context.Context, id model.AlbumID) (*model.Album, error) {
 	args := m.Called(ctx, id)
@@ -31,6 +46,29 @@ func (m *MockAlbumRepository) Get(ctx context.Context, id model.AlbumID) (*model
 	}
 	return args.Get(0).(*model.Album), args.Error(1)
 }
+func (m *MockAlbumRepository) GetBySinger(ctx context.Context, singerID model.SingerID) ([]*model.SingerAlbum, error) {
+	args := m.Called(ctx, singerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SingerAlbum), args.Error(1)
+}
+
+func (m *MockAlbumRepository) GetIDsBySinger(ctx context.Context, singerID model.SingerID) ([]model.AlbumID, error) {
+	args := m.Called(ctx, singerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AlbumID), args.Error(1)
+}
+
+func (m *MockAlbumRepository) GetBySingerAndTitle(ctx context.Context, singerID model.SingerID, title string) (*model.Album, error) {
+	args := m.Called(ctx, singerID, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Album), args.Error(1)
+}
 func (m *MockAlbumRepository) Add(ctx context.Context, album *model.Album) error {
 	args := m.Called(ctx, album)
 	if err, ok := args.Get(0).(error); ok {
@@ -38,7 +76,33 @@ func (m *MockAlbumRepository) Add(ctx context.Context, album *model.Album) error
 	}
 	return nil
 }
-func (m *MockAlbumRepository) Delete(ctx context.Context, id model.AlbumID) error {
+func (m *MockAlbumRepository) AddIfAbsent(ctx context.Context, album *model.Album) (bool, error) {
+	args := m.Called(ctx, album)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockAlbumRepository) AddBatch(ctx context.Context, albums []*model.Album) error {
+	args := m.Called(ctx, albums)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+func (m *MockAlbumRepository) Update(ctx context.Context, album *model.Album) error {
+	args := m.Called(ctx, album)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+func (m *MockAlbumRepository) Delete(ctx context.Context, id model.AlbumID, version int64) error {
+	args := m.Called(ctx, id, version)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockAlbumRepository) Restore(ctx context.Context, id model.AlbumID) error {
 	args := m.Called(ctx, id)
 	if err, ok := args.Get(0).(error); ok {
 		return err
@@ -46,10 +110,26 @@ func (m *MockAlbumRepository) Delete(ctx context.Context, id model.AlbumID) erro
 	return nil
 }
 
+func (m *MockAlbumRepository) GetDeleted(ctx context.Context) ([]*model.Album, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Album), args.Error(1)
+}
+
+func (m *MockAlbumRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type AlbumServiceSuite struct {
 	suite.Suite
 	albumService        service.AlbumService
 	mockAlbumRepository *MockAlbumRepository
+	mockTrackRepository *MockTrackRepository
+	mockTransactor      *MockTransactor
+	auditWriter         *capturingAuditWriter
 }
 
 func TestAlbumServiceTestSuite(t *testing.T) {
@@ -58,7 +138,14 @@ func TestAlbumServiceTestSuite(t *testing.T) {
 
 func (suite *AlbumServiceSuite) SetupSuite() {
 	suite.mockAlbumRepository = NewMockAlbumRepository()
-	suite.albumService = service.NewAlbumService(suite.mockAlbumRepository)
+	suite.mockTrackRepository = NewMockTrackRepository()
+	suite.mockTransactor = NewMockTransactor()
+	suite.auditWriter = &capturingAuditWriter{}
+	suite.albumService = service.NewAlbumService(suite.mockAlbumRepository, suite.mockTrackRepository, suite.mockTransactor, suite.auditWriter)
+}
+
+func (suite *AlbumServiceSuite) SetupTest() {
+	*suite.auditWriter = capturingAuditWriter{}
 }
 
 func (suite *AlbumServiceSuite) TestAlbumServiceGetAlbumListService() {
@@ -79,12 +166,14 @@ func (suite *AlbumServiceSuite) TestAlbumServiceGetAlbumListService() {
 		},
 	}
 
-	suite.mockAlbumRepository.On("GetAll", ctx).Return(albums, nil)
+	q := &model.AlbumQuery{Limit: model.DefaultPageLimit, Sort: model.AlbumSortID}
+	page := &model.Page[*model.Album]{Items: albums}
+	suite.mockAlbumRepository.On("GetAll", ctx, q).Return(page, nil)
 
-	result, err := suite.albumService.GetAlbumListService(ctx)
+	result, err := suite.albumService.GetAlbumListService(ctx, q)
 
 	suite.Assert().Nil(err)
-	suite.Assert().Equal(albums, result)
+	suite.Assert().Equal(albums, result.Items)
 	suite.mockAlbumRepository.AssertExpectations(suite.T())
 }
 
@@ -107,17 +196,44 @@ func (suite *AlbumServiceSuite) TestAlbumServiceGetAlbumService() {
 	suite.mockAlbumRepository.AssertExpectations(suite.T())
 }
 
+func (suite *AlbumServiceSuite) TestAlbumServiceGetAlbumWithTracksService() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(5),
+		Title:    "Album With Tracks",
+		SingerID: model.SingerID(1),
+		Singer:   &model.Singer{ID: model.SingerID(1), Name: "Test Singer"},
+	}
+	tracks := []*model.Track{
+		{ID: model.TrackID(1), AlbumID: album.ID, DiscNumber: 1, TrackNumber: 1, Title: "Intro", DurationSeconds: 60},
+		{ID: model.TrackID(2), AlbumID: album.ID, DiscNumber: 1, TrackNumber: 2, Title: "Song", DurationSeconds: 200},
+	}
+
+	suite.mockAlbumRepository.On("Get", ctx, album.ID).Return(album, nil)
+	suite.mockTrackRepository.On("GetByAlbum", ctx, album.ID).Return(tracks, nil)
+
+	result, err := suite.albumService.GetAlbumWithTracksService(ctx, album.ID)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(tracks, result.Tracks)
+	suite.Assert().Equal(260, result.TotalDuration())
+	suite.mockTrackRepository.AssertExpectations(suite.T())
+}
+
 func (suite *AlbumServiceSuite) TestAlbumServicePostAlbumService() {
 	ctx := context.Background()
 
 	album := &model.Album{
-		ID:       model.AlbumID(1),
+		ID:       model.AlbumID(42),
 		Title:    "New Album",
 		SingerID: model.SingerID(1),
 		Singer:   &model.Singer{ID: model.SingerID(1), Name: "Test Singer"},
 	}
 
+	suite.mockTransactor.On("WithinTx", ctx).Return()
 	suite.mockAlbumRepository.On("Add", ctx, album).Return(nil)
+	suite.mockAlbumRepository.On("Get", ctx, album.ID).Return(album, nil)
 
 	err := suite.albumService.PostAlbumService(ctx, album)
 
@@ -127,12 +243,221 @@ func (suite *AlbumServiceSuite) TestAlbumServicePostAlbumService() {
 
 func (suite *AlbumServiceSuite) TestAlbumServiceDeleteAlbumService() {
 	ctx := context.Background()
-	id := model.AlbumID(1)
+	id := model.AlbumID(43)
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("Get", ctx, id).Return(&model.Album{ID: id, Version: 3}, nil)
+	suite.mockAlbumRepository.On("Delete", ctx, id, int64(3)).Return(nil)
+
+	err := suite.albumService.DeleteAlbumService(ctx, id, 3)
+
+	suite.Assert().Nil(err)
+	suite.mockAlbumRepository.AssertExpectations(suite.T())
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceDeleteAlbumService_NoVersion() {
+	ctx := context.Background()
+	id := model.AlbumID(40)
 
-	suite.mockAlbumRepository.On("Delete", ctx, id).Return(nil)
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("Get", ctx, id).Return(&model.Album{ID: id, Version: 5}, nil)
+	suite.mockAlbumRepository.On("Delete", ctx, id, int64(5)).Return(nil)
 
-	err := suite.albumService.DeleteAlbumService(ctx, id)
+	err := suite.albumService.DeleteAlbumService(ctx, id, 0)
+
+	suite.Assert().Nil(err)
+	suite.mockAlbumRepository.AssertCalled(suite.T(), "Delete", ctx, id, int64(5))
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceDeleteAlbumService_VersionMismatch() {
+	ctx := context.Background()
+	id := model.AlbumID(41)
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("Get", ctx, id).Return(&model.Album{ID: id, Version: 2}, nil)
+	suite.mockAlbumRepository.On("Delete", ctx, id, int64(1)).Return(repository.ErrorAlbumVersionMismatch)
+
+	err := suite.albumService.DeleteAlbumService(ctx, id, 1)
+
+	suite.Assert().Equal(model.KindPreconditionFailed, model.KindOf(err))
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceUpdateAlbumService() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(44),
+		Title:    "Updated Album",
+		SingerID: model.SingerID(2),
+		Version:  1,
+	}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("Get", ctx, album.ID).Return(&model.Album{ID: album.ID, Title: "Album", Version: 1}, nil)
+	suite.mockAlbumRepository.On("Update", ctx, album).Return(nil)
+
+	err := suite.albumService.UpdateAlbumService(ctx, album)
 
 	suite.Assert().Nil(err)
 	suite.mockAlbumRepository.AssertExpectations(suite.T())
 }
+
+func (suite *AlbumServiceSuite) TestAlbumServiceUpdateAlbumService_RecordsAudit() {
+	ctx := logging.WithRequestID(context.Background(), "req-45")
+	album := &model.Album{ID: model.AlbumID(45), Title: "After", SingerID: model.SingerID(2)}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("Get", ctx, album.ID).Return(&model.Album{ID: album.ID, Title: "Before", SingerID: 2, Version: 2}, nil).Once()
+	suite.mockAlbumRepository.On("Update", ctx, album).Return(nil)
+	suite.mockAlbumRepository.On("Get", ctx, album.ID).
+		Return(&model.Album{ID: album.ID, Title: "After", SingerID: 2, Singer: &model.Singer{ID: 2, Version: 5}, Version: 3}, nil).Once()
+
+	err := suite.albumService.UpdateAlbumService(ctx, album)
+
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), album.Version)
+	// the controller's ETag covers the singer
+	suite.Equal(int64(5), album.Singer.Version)
+	suite.Require().Len(suite.auditWriter.events, 1)
+	event := suite.auditWriter.events[0]
+	suite.Equal(model.AuditUpdate, event.Action)
+	suite.Equal(model.AuditEntityAlbum, event.EntityType)
+	suite.Equal("req-45", event.RequestID)
+	var before, after model.Album
+	suite.Require().NoError(json.Unmarshal(event.Before, &before))
+	suite.Require().NoError(json.Unmarshal(event.After, &after))
+	suite.Equal("Before", before.Title)
+	suite.Equal("After", after.Title)
+	suite.Equal(int64(3), after.Version)
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceUpdateAlbumService_InvalidParam() {
+	ctx := context.Background()
+
+	album := &model.Album{
+		ID:       model.AlbumID(1),
+		Title:    "",
+		SingerID: model.SingerID(2),
+	}
+
+	err := suite.albumService.UpdateAlbumService(ctx, album)
+
+	suite.Assert().ErrorIs(err, model.ErrInvalidParam)
+	suite.mockAlbumRepository.AssertNotCalled(suite.T(), "Update", ctx, album)
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceGetAlbumListService_InvalidQuery() {
+	ctx := context.Background()
+
+	q := &model.AlbumQuery{Limit: model.MaxPageLimit + 1, Sort: "singer"}
+
+	result, err := suite.albumService.GetAlbumListService(ctx, q)
+
+	suite.Assert().Nil(result)
+	suite.Assert().ErrorIs(err, model.ErrInvalidParam)
+	suite.mockAlbumRepository.AssertNotCalled(suite.T(), "GetAll", ctx, q)
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceRestoreAlbumService() {
+	ctx := context.Background()
+	album := &model.Album{
+		ID:       model.AlbumID(7),
+		Title:    "Restored Album",
+		SingerID: model.SingerID(1),
+		Singer:   &model.Singer{ID: model.SingerID(1), Name: "Test Singer"},
+	}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("Restore", ctx, album.ID).Return(nil)
+	suite.mockAlbumRepository.On("Get", ctx, album.ID).Return(album, nil)
+
+	result, err := suite.albumService.RestoreAlbumService(ctx, album.ID)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(album, result)
+	suite.Require().Len(suite.auditWriter.events, 1)
+	suite.Equal(model.AuditRestore, suite.auditWriter.events[0].Action)
+	suite.Nil(suite.auditWriter.events[0].Before)
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceBatchAlbumService_BestEffort() {
+	ctx := context.Background()
+	created := &model.Album{Title: "Batch Album", SingerID: model.SingerID(1)}
+	invalid := &model.Album{Title: "", SingerID: model.SingerID(1)}
+	stale := &model.Album{ID: model.AlbumID(60), Title: "Stale Album", SingerID: model.SingerID(1), Version: 2}
+	deleted := &model.Album{ID: model.AlbumID(61), Version: 1}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("AddBatch", ctx, []*model.Album{created}).
+		Run(func(args mock.Arguments) { created.ID = model.AlbumID(70) }).
+		Return(nil)
+	suite.mockAlbumRepository.On("Get", ctx, model.AlbumID(70)).Return(created, nil)
+	suite.mockAlbumRepository.On("Get", ctx, stale.ID).Return(&model.Album{ID: stale.ID, Title: "Stale Album", Version: 3}, nil)
+	suite.mockAlbumRepository.On("Update", ctx, stale).Return(repository.ErrorAlbumVersionMismatch)
+	suite.mockAlbumRepository.On("Get", ctx, deleted.ID).Return(deleted, nil)
+	suite.mockAlbumRepository.On("Delete", ctx, deleted.ID, int64(1)).Return(nil)
+
+	results, err := suite.albumService.BatchAlbumService(ctx, model.BatchBestEffort, []model.BatchOperation[*model.Album]{
+		{Action: model.BatchCreate, Item: created},
+		{Action: model.BatchCreate, Item: invalid},
+		{Action: model.BatchUpdate, Item: stale},
+		{Action: model.BatchDelete, Item: deleted},
+	})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 4)
+	suite.Equal(70, results[0].ID)
+	suite.NoError(results[0].Err)
+	suite.ErrorIs(results[1].Err, model.ErrInvalidParam)
+	suite.ErrorIs(results[2].Err, repository.ErrorAlbumVersionMismatch)
+	suite.Equal(61, results[3].ID)
+	suite.NoError(results[3].Err)
+	suite.Require().Len(suite.auditWriter.events, 2)
+	suite.Equal(model.AuditCreate, suite.auditWriter.events[0].Action)
+	suite.Equal(70, suite.auditWriter.events[0].EntityID)
+	suite.Equal(model.AuditDelete, suite.auditWriter.events[1].Action)
+	suite.Equal(61, suite.auditWriter.events[1].EntityID)
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceBatchAlbumService_AtomicFallsBackToSingleRows() {
+	ctx := context.Background()
+	first := &model.Album{ID: model.AlbumID(80), Title: "First Batch Album", SingerID: model.SingerID(1)}
+	duplicate := &model.Album{ID: model.AlbumID(81), Title: "Duplicate Batch Album", SingerID: model.SingerID(1)}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockAlbumRepository.On("AddBatch", ctx, []*model.Album{first, duplicate}).Return(repository.ErrorAlbumAlreadyExists)
+	suite.mockAlbumRepository.On("Add", ctx, first).Return(nil)
+	suite.mockAlbumRepository.On("Get", ctx, first.ID).Return(first, nil)
+	suite.mockAlbumRepository.On("Add", ctx, duplicate).Return(repository.ErrorAlbumAlreadyExists)
+
+	results, err := suite.albumService.BatchAlbumService(ctx, model.BatchAtomic, []model.BatchOperation[*model.Album]{
+		{Action: model.BatchCreate, Item: first},
+		{Action: model.BatchCreate, Item: duplicate},
+	})
+
+	suite.Nil(results)
+	var batchErr *model.BatchError
+	suite.Require().ErrorAs(err, &batchErr)
+	suite.Equal(1, batchErr.Index)
+	suite.ErrorIs(err, repository.ErrorAlbumAlreadyExists)
+	suite.mockTransactor.AssertCalled(suite.T(), "WithinTx", ctx)
+}
+
+func (suite *AlbumServiceSuite) TestAlbumServiceExportAlbumsService() {
+	ctx := context.Background()
+	singerID := model.SingerID(9)
+	q := &model.AlbumQuery{Sort: model.AlbumSortID, SingerID: &singerID}
+	albums := []*model.Album{{ID: model.AlbumID(90), Title: "Export Album", SingerID: singerID}}
+
+	suite.mockTransactor.On("WithinReadOnlyTx", ctx).Return()
+	suite.mockAlbumRepository.On("Export", ctx, q).Return(albums, nil)
+
+	var exported []*model.Album
+	err := suite.albumService.ExportAlbumsService(ctx, q, func(album *model.Album) error {
+		exported = append(exported, album)
+		return nil
+	})
+
+	suite.NoError(err)
+	suite.Equal(albums, exported)
+}
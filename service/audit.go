@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+// AuditWriter records changes to singers and albums, whichever service makes them. Services
+// call it inside the transaction of the change, so a failed write rolls the change back.
+type AuditWriter interface {
+	Write(ctx context.Context, event *model.AuditEvent) error
+}
+
+type auditWriter struct {
+	auditRepository repository.AuditRepository
+}
+
+var _ AuditWriter = (*auditWriter)(nil)
+
+func NewAuditWriter(auditRepository repository.AuditRepository) AuditWriter {
+	return &auditWriter{auditRepository: auditRepository}
+}
+
+func (w *auditWriter) Write(ctx context.Context, event *model.AuditEvent) error {
+	return w.auditRepository.Add(ctx, event)
+}
+
+// writeAudit records action on an entity by the principal and request ctx belongs to.
+// before and after are the entity around the change, nil where it was absent or trashed.
+func writeAudit[T any](ctx context.Context, w AuditWriter, action model.AuditAction, entityType model.AuditEntityType, entityID int, before, after *T) error {
+	event := &model.AuditEvent{
+		Actor:      model.AuditActorAnonymous,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		RequestID:  logging.RequestID(ctx),
+	}
+	if principal, ok := auth.FromContext(ctx); ok {
+		event.Actor = principal.Subject
+	}
+	var err error
+	if event.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if event.After, err = snapshot(after); err != nil {
+		return err
+	}
+	return w.Write(ctx, event)
+}
+
+// loadForWrite reads the entity a write is about to change, for its audit snapshot, and the
+// version the write is conditional on. Without a version from the client, whatever is
+// current gets overwritten.
+func loadForWrite[ID, T any](ctx context.Context, get func(context.Context, ID) (*T, error), id ID, version int64, current func(*T) int64) (*T, int64, error) {
+	before, err := get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version == 0 {
+		version = current(before)
+	}
+	return before, version, nil
+}
+
+func snapshot[T any](entity *T) (json.RawMessage, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return nil, model.NewError(model.KindInternal, "failed to encode audit snapshot", err)
+	}
+	return b, nil
+}
+
+type AuditService interface {
+	GetAuditEventsService(ctx context.Context, q *model.AuditQuery) (*model.Page[*model.AuditEvent], error)
+}
+
+type auditService struct {
+	auditRepository repository.AuditRepository
+}
+
+var _ AuditService = (*auditService)(nil)
+
+func NewAuditService(auditRepository repository.AuditRepository) AuditService {
+	return &auditService{auditRepository: auditRepository}
+}
+
+func (s *auditService) GetAuditEventsService(ctx context.Context, q *model.AuditQuery) (*model.Page[*model.AuditEvent], error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.auditRepository.GetAll(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
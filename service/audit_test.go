@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// capturingAuditWriter keeps the events written to it so tests can assert on them.
+type capturingAuditWriter struct {
+	events []*model.AuditEvent
+	err    error
+}
+
+func (w *capturingAuditWriter) Write(ctx context.Context, event *model.AuditEvent) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.events = append(w.events, event)
+	return nil
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Add(ctx context.Context, event *model.AuditEvent) error {
+	args := m.Called(ctx, event)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockAuditRepository) GetAll(ctx context.Context, q *model.AuditQuery) (*model.Page[*model.AuditEvent], error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Page[*model.AuditEvent]), args.Error(1)
+}
+
+type AuditServiceSuite struct {
+	suite.Suite
+	auditService        service.AuditService
+	mockAuditRepository *MockAuditRepository
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceSuite))
+}
+
+func (suite *AuditServiceSuite) SetupTest() {
+	suite.mockAuditRepository = &MockAuditRepository{}
+	suite.auditService = service.NewAuditService(suite.mockAuditRepository)
+}
+
+func (suite *AuditServiceSuite) TestAuditServiceGetAuditEventsService() {
+	ctx := context.Background()
+	q := &model.AuditQuery{Limit: model.DefaultPageLimit, EntityType: model.AuditEntityAlbum, EntityID: 3}
+	events := []*model.AuditEvent{{ID: 9, Actor: "api_key:local admin", Action: model.AuditDelete, EntityType: model.AuditEntityAlbum, EntityID: 3}}
+	suite.mockAuditRepository.On("GetAll", ctx, q).Return(&model.Page[*model.AuditEvent]{Items: events}, nil)
+
+	page, err := suite.auditService.GetAuditEventsService(ctx, q)
+
+	suite.NoError(err)
+	suite.Equal(events, page.Items)
+}
+
+func (suite *AuditServiceSuite) TestAuditServiceGetAuditEventsService_NoFilter() {
+	ctx := context.Background()
+	q := &model.AuditQuery{Limit: model.DefaultPageLimit}
+
+	_, err := suite.auditService.GetAuditEventsService(ctx, q)
+
+	suite.ErrorIs(err, model.ErrInvalidParam)
+	suite.mockAuditRepository.AssertNotCalled(suite.T(), "GetAll", ctx, q)
+}
+
+func (suite *AuditServiceSuite) TestAuditWriter() {
+	ctx := context.Background()
+	event := &model.AuditEvent{Actor: model.AuditActorAnonymous, Action: model.AuditCreate, EntityType: model.AuditEntitySinger, EntityID: 1}
+	suite.mockAuditRepository.On("Add", ctx, event).Return(nil)
+
+	err := service.NewAuditWriter(suite.mockAuditRepository).Write(ctx, event)
+
+	suite.NoError(err)
+	suite.mockAuditRepository.AssertExpectations(suite.T())
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+var (
+	ErrorInvalidAPIKey = model.NewError(model.KindUnauthenticated, "invalid API key", nil)
+	ErrorInvalidToken  = model.NewError(model.KindUnauthenticated, "invalid bearer token", nil)
+)
+
+// TokenVerifier checks a bearer token; *auth.Verifier implements it.
+type TokenVerifier interface {
+	Verify(token string) (*model.Principal, error)
+}
+
+type AuthService interface {
+	AuthenticateAPIKeyService(ctx context.Context, key string) (*model.Principal, error)
+	AuthenticateTokenService(ctx context.Context, token string) (*model.Principal, error)
+}
+
+type authService struct {
+	apiKeyRepository repository.APIKeyRepository
+	verifier         TokenVerifier
+}
+
+var _ AuthService = (*authService)(nil)
+
+func NewAuthService(apiKeyRepository repository.APIKeyRepository, verifier TokenVerifier) AuthService {
+	return &authService{apiKeyRepository: apiKeyRepository, verifier: verifier}
+}
+
+func (s *authService) AuthenticateAPIKeyService(ctx context.Context, key string) (*model.Principal, error) {
+	apiKey, err := s.apiKeyRepository.GetByHash(ctx, auth.HashAPIKey(key))
+	if errors.Is(err, repository.ErrorAPIKeyNotFound) {
+		return nil, ErrorInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return apiKey.Principal(), nil
+}
+
+// AuthenticateTokenService keeps why a token was rejected out of the client message, since
+// it would help forging one.
+func (s *authService) AuthenticateTokenService(ctx context.Context, token string) (*model.Principal, error) {
+	principal, err := s.verifier.Verify(token)
+	if err != nil {
+		return nil, model.NewError(model.KindUnauthenticated, ErrorInvalidToken.Message, err)
+	}
+	return principal, nil
+}
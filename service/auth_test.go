@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	args := m.Called(ctx, hash)
+	key, _ := args.Get(0).(*model.APIKey)
+	return key, args.Error(1)
+}
+
+type stubVerifier struct {
+	principal *model.Principal
+	err       error
+}
+
+func (v *stubVerifier) Verify(token string) (*model.Principal, error) {
+	return v.principal, v.err
+}
+
+type AuthServiceSuite struct {
+	suite.Suite
+	mockAPIKeyRepository *MockAPIKeyRepository
+	verifier             *stubVerifier
+	authService          service.AuthService
+}
+
+func TestAuthServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceSuite))
+}
+
+func (suite *AuthServiceSuite) SetupTest() {
+	suite.mockAPIKeyRepository = new(MockAPIKeyRepository)
+	suite.verifier = &stubVerifier{}
+	suite.authService = service.NewAuthService(suite.mockAPIKeyRepository, suite.verifier)
+}
+
+func (suite *AuthServiceSuite) TestAuthenticateAPIKeyService() {
+	ctx := context.Background()
+	suite.mockAPIKeyRepository.On("GetByHash", ctx, auth.HashAPIKey("secret")).
+		Return(&model.APIKey{ID: 1, Name: "ci", Role: model.RoleEditor}, nil)
+
+	principal, err := suite.authService.AuthenticateAPIKeyService(ctx, "secret")
+	suite.NoError(err)
+	suite.Equal(&model.Principal{Subject: "api_key:ci", Role: model.RoleEditor, Method: model.AuthMethodAPIKey}, principal)
+}
+
+func (suite *AuthServiceSuite) TestAuthenticateAPIKeyService_Unknown() {
+	ctx := context.Background()
+	suite.mockAPIKeyRepository.On("GetByHash", ctx, mock.Anything).Return(nil, repository.ErrorAPIKeyNotFound)
+
+	_, err := suite.authService.AuthenticateAPIKeyService(ctx, "guess")
+	suite.ErrorIs(err, service.ErrorInvalidAPIKey)
+	suite.Equal(model.KindUnauthenticated, model.KindOf(err))
+}
+
+func (suite *AuthServiceSuite) TestAuthenticateAPIKeyService_DatabaseDown() {
+	ctx := context.Background()
+	down := model.NewError(model.KindUnavailable, "database unavailable", nil)
+	suite.mockAPIKeyRepository.On("GetByHash", ctx, mock.Anything).Return(nil, down)
+
+	_, err := suite.authService.AuthenticateAPIKeyService(ctx, "secret")
+	suite.Equal(model.KindUnavailable, model.KindOf(err))
+}
+
+func (suite *AuthServiceSuite) TestAuthenticateTokenService() {
+	suite.verifier.principal = &model.Principal{Subject: "alice", Role: model.RoleViewer, Method: model.AuthMethodJWT}
+	principal, err := suite.authService.AuthenticateTokenService(context.Background(), "token")
+	suite.NoError(err)
+	suite.Equal("alice", principal.Subject)
+
+	expired := errors.New("token has invalid claims: token is expired")
+	suite.verifier.principal, suite.verifier.err = nil, expired
+	_, err = suite.authService.AuthenticateTokenService(context.Background(), "token")
+	suite.ErrorIs(err, expired)
+	suite.Equal(model.KindUnauthenticated, model.KindOf(err))
+	suite.Equal("invalid bearer token", err.(*model.Error).Message)
+}
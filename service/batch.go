@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+// batchSteps tells runBatch how to apply each action to items of type T.
+type batchSteps[T any] struct {
+	validate func(item T) error
+	addAll   func(ctx context.Context, items []T) error
+	add      func(ctx context.Context, item T) error
+	update   func(ctx context.Context, item T) error
+	delete   func(ctx context.Context, item T) error
+	id       func(item T) int
+}
+
+// runBatch applies ops in order. Consecutive creates go to the database as one multi-row
+// INSERT; when that fails they are retried one by one so that every item gets its own error.
+// An atomic batch runs in one transaction and stops at the first failing operation, which
+// is returned as a *model.BatchError. A best-effort batch never fails as a whole.
+func runBatch[T any](ctx context.Context, transactor repository.Transactor, mode model.BatchMode, ops []model.BatchOperation[T], steps batchSteps[T]) ([]*model.BatchResult, error) {
+	results := make([]*model.BatchResult, len(ops))
+	apply := func(ctx context.Context) error {
+		for i := 0; i < len(ops); {
+			j := i + 1
+			if ops[i].Action == model.BatchCreate {
+				// A run of creates either all carry an id or none does, so that it is one statement.
+				explicit := steps.id(ops[i].Item) != 0
+				for j < len(ops) && ops[j].Action == model.BatchCreate && (steps.id(ops[j].Item) != 0) == explicit {
+					j++
+				}
+				steps.create(ctx, ops[i:j], results[i:j], i)
+			} else {
+				results[i] = steps.apply(ctx, ops[i], i)
+			}
+
+			if mode == model.BatchAtomic {
+				for k := i; k < j; k++ {
+					if results[k].Err != nil {
+						return &model.BatchError{Index: k, Err: results[k].Err}
+					}
+				}
+			}
+			i = j
+		}
+		return nil
+	}
+
+	if mode != model.BatchAtomic {
+		_ = apply(ctx)
+		return results, nil
+	}
+	if err := transactor.WithinTx(ctx, apply); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// create fills results for a run of creates starting at offset.
+func (steps batchSteps[T]) create(ctx context.Context, ops []model.BatchOperation[T], results []*model.BatchResult, offset int) {
+	pending := make([]int, 0, len(ops))
+	for k, op := range ops {
+		results[k] = &model.BatchResult{Index: offset + k, Action: op.Action, ID: steps.id(op.Item)}
+		if err := steps.validate(op.Item); err != nil {
+			results[k].Err = err
+			continue
+		}
+		pending = append(pending, k)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	items := make([]T, len(pending))
+	for n, k := range pending {
+		items[n] = ops[k].Item
+	}
+	err := steps.addAll(ctx, items)
+	if err == nil {
+		for _, k := range pending {
+			results[k].ID = steps.id(ops[k].Item)
+		}
+		return
+	}
+
+	// Retrying one by one only helps when a particular row was rejected.
+	if kind := model.KindOf(err); kind == model.KindInternal || kind == model.KindUnavailable {
+		for _, k := range pending {
+			results[k].Err = err
+		}
+		return
+	}
+	for _, k := range pending {
+		results[k].Err = steps.add(ctx, ops[k].Item)
+		results[k].ID = steps.id(ops[k].Item)
+	}
+}
+
+func (steps batchSteps[T]) apply(ctx context.Context, op model.BatchOperation[T], index int) *model.BatchResult {
+	result := &model.BatchResult{Index: index, Action: op.Action, ID: steps.id(op.Item)}
+	switch op.Action {
+	case model.BatchUpdate:
+		result.Err = steps.update(ctx, op.Item)
+	case model.BatchDelete:
+		result.Err = steps.delete(ctx, op.Item)
+	default:
+		result.Err = model.NewError(model.KindValidation, "unsupported batch action", nil)
+	}
+	return result
+}
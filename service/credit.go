@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+type CreditService interface {
+	GetAlbumCreditsService(ctx context.Context, albumID model.AlbumID) ([]*model.Credit, error)
+	// PutAlbumCreditsService replaces the credits if the album is still at version; zero
+	// replaces them whatever the version.
+	PutAlbumCreditsService(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) ([]*model.Credit, error)
+}
+
+type creditService struct {
+	creditRepository repository.CreditRepository
+	albumRepository  repository.AlbumRepository
+	transactor       repository.Transactor
+	auditWriter      AuditWriter
+}
+
+var _ CreditService = (*creditService)(nil)
+
+func NewCreditService(
+	creditRepository repository.CreditRepository,
+	albumRepository repository.AlbumRepository,
+	transactor repository.Transactor,
+	auditWriter AuditWriter,
+) CreditService {
+	return &creditService{
+		creditRepository: creditRepository,
+		albumRepository:  albumRepository,
+		transactor:       transactor,
+		auditWriter:      auditWriter,
+	}
+}
+
+// GetAlbumCreditsService lists the primary artist first, followed by the stored credits in order.
+func (s *creditService) GetAlbumCreditsService(ctx context.Context, albumID model.AlbumID) ([]*model.Credit, error) {
+	album, err := s.albumRepository.Get(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.creditRepository.GetByAlbum(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	credits := make([]*model.Credit, 0, len(stored)+1)
+	credits = append(credits, &model.Credit{
+		AlbumID:  album.ID,
+		SingerID: album.SingerID,
+		Singer:   album.Singer,
+		Role:     model.CreditRolePrimary,
+	})
+	credits = append(credits, stored...)
+	for i, c := range credits {
+		c.Position = i + 1
+	}
+	return credits, nil
+}
+
+func (s *creditService) PutAlbumCreditsService(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) ([]*model.Credit, error) {
+	if err := model.ValidateCredits(credits); err != nil {
+		return nil, err
+	}
+
+	position := 0
+	for _, c := range credits {
+		c.AlbumID = albumID
+		if c.Role != model.CreditRolePrimary {
+			position++
+			c.Position = position
+		}
+	}
+	// The primary credit rewrites the album row, so the change is audited as an album update.
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, version, err := loadForWrite(ctx, s.albumRepository.Get, albumID, version, albumVersion)
+		if err != nil {
+			return err
+		}
+		if err = s.creditRepository.Replace(ctx, albumID, version, credits); err != nil {
+			return err
+		}
+		after, err := s.albumRepository.Get(ctx, albumID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, s.auditWriter, model.AuditUpdate, model.AuditEntityAlbum, int(albumID), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetAlbumCreditsService(ctx, albumID)
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockCreditRepository struct {
+	mock.Mock
+}
+
+func NewMockCreditRepository() *MockCreditRepository {
+	return &MockCreditRepository{}
+}
+
+func (m *MockCreditRepository) GetByAlbum(ctx context.Context, albumID model.AlbumID) ([]*model.Credit, error) {
+	args := m.Called(ctx, albumID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Credit), args.Error(1)
+}
+
+func (m *MockCreditRepository) Replace(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) error {
+	args := m.Called(ctx, albumID, version, credits)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+type CreditServiceSuite struct {
+	suite.Suite
+	creditService        service.CreditService
+	mockCreditRepository *MockCreditRepository
+	mockAlbumRepository  *MockAlbumRepository
+	mockTransactor       *MockTransactor
+	auditWriter          *capturingAuditWriter
+}
+
+func TestCreditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CreditServiceSuite))
+}
+
+func (suite *CreditServiceSuite) SetupTest() {
+	suite.mockCreditRepository = NewMockCreditRepository()
+	suite.mockAlbumRepository = NewMockAlbumRepository()
+	suite.mockTransactor = NewMockTransactor()
+	suite.mockTransactor.On("WithinTx", mock.Anything).Return()
+	suite.auditWriter = &capturingAuditWriter{}
+	suite.creditService = service.NewCreditService(suite.mockCreditRepository, suite.mockAlbumRepository, suite.mockTransactor, suite.auditWriter)
+}
+
+func (suite *CreditServiceSuite) TestGetAlbumCreditsService() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	alice := &model.Singer{ID: model.SingerID(1), Name: "Alice"}
+	bella := &model.Singer{ID: model.SingerID(2), Name: "Bella"}
+
+	suite.mockAlbumRepository.On("Get", ctx, albumID).
+		Return(&model.Album{ID: albumID, SingerID: alice.ID, Singer: alice}, nil)
+	suite.mockCreditRepository.On("GetByAlbum", ctx, albumID).Return([]*model.Credit{
+		{AlbumID: albumID, SingerID: bella.ID, Singer: bella, Role: model.CreditRoleFeatured, Position: 1},
+	}, nil)
+
+	result, err := suite.creditService.GetAlbumCreditsService(ctx, albumID)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]*model.Credit{
+		{AlbumID: albumID, SingerID: alice.ID, Singer: alice, Role: model.CreditRolePrimary, Position: 1},
+		{AlbumID: albumID, SingerID: bella.ID, Singer: bella, Role: model.CreditRoleFeatured, Position: 2},
+	}, result)
+}
+
+func (suite *CreditServiceSuite) TestPutAlbumCreditsService() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{
+		{SingerID: model.SingerID(3), Role: model.CreditRoleProducer},
+		{SingerID: model.SingerID(1), Role: model.CreditRolePrimary},
+		{SingerID: model.SingerID(2), Role: model.CreditRoleFeatured},
+	}
+
+	suite.mockAlbumRepository.On("Get", ctx, albumID).
+		Return(&model.Album{ID: albumID, SingerID: model.SingerID(2), Singer: &model.Singer{ID: model.SingerID(2)}, Version: 1}, nil).Once()
+	// without If-Match the credits replace whatever version is current
+	suite.mockCreditRepository.On("Replace", ctx, albumID, int64(1), credits).Return(nil)
+	suite.mockAlbumRepository.On("Get", ctx, albumID).
+		Return(&model.Album{ID: albumID, SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1)}, Version: 2}, nil)
+	suite.mockCreditRepository.On("GetByAlbum", ctx, albumID).Return([]*model.Credit{}, nil)
+
+	_, err := suite.creditService.PutAlbumCreditsService(ctx, albumID, 0, credits)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(1, credits[0].Position)
+	suite.Assert().Equal(2, credits[2].Position)
+	suite.mockCreditRepository.AssertExpectations(suite.T())
+
+	suite.Require().Len(suite.auditWriter.events, 1)
+	event := suite.auditWriter.events[0]
+	suite.Equal(model.AuditUpdate, event.Action)
+	suite.Equal(model.AuditEntityAlbum, event.EntityType)
+	suite.Equal(int(albumID), event.EntityID)
+	suite.Contains(string(event.Before), `"singer_id":2`)
+	suite.Contains(string(event.After), `"singer_id":1`)
+}
+
+func (suite *CreditServiceSuite) TestPutAlbumCreditsService_AuditFails() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{{SingerID: model.SingerID(1), Role: model.CreditRolePrimary}}
+	suite.auditWriter.err = model.NewError(model.KindUnavailable, "database unavailable", nil)
+
+	suite.mockAlbumRepository.On("Get", ctx, albumID).
+		Return(&model.Album{ID: albumID, SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1)}, Version: 3}, nil)
+	suite.mockCreditRepository.On("Replace", ctx, albumID, int64(3), credits).Return(nil)
+
+	_, err := suite.creditService.PutAlbumCreditsService(ctx, albumID, 3, credits)
+
+	suite.Equal(model.KindUnavailable, model.KindOf(err))
+	suite.mockCreditRepository.AssertNotCalled(suite.T(), "GetByAlbum", ctx, albumID)
+}
+
+func (suite *CreditServiceSuite) TestPutAlbumCreditsService_Invalid() {
+	ctx := context.Background()
+	credits := []*model.Credit{{SingerID: model.SingerID(2), Role: model.CreditRoleFeatured}}
+
+	_, err := suite.creditService.PutAlbumCreditsService(ctx, model.AlbumID(2), 0, credits)
+
+	suite.Assert().ErrorIs(err, model.ErrInvalidParam)
+	suite.mockCreditRepository.AssertNumberOfCalls(suite.T(), "Replace", 0)
+}
+
+func (suite *CreditServiceSuite) TestPutAlbumCreditsService_VersionMismatch() {
+	ctx := context.Background()
+	albumID := model.AlbumID(2)
+	credits := []*model.Credit{{SingerID: model.SingerID(1), Role: model.CreditRolePrimary}}
+
+	suite.mockAlbumRepository.On("Get", ctx, albumID).
+		Return(&model.Album{ID: albumID, SingerID: model.SingerID(1), Singer: &model.Singer{ID: model.SingerID(1)}, Version: 6}, nil)
+	suite.mockCreditRepository.On("Replace", ctx, albumID, int64(5), credits).Return(repository.ErrorAlbumVersionMismatch)
+
+	_, err := suite.creditService.PutAlbumCreditsService(ctx, albumID, 5, credits)
+
+	suite.ErrorIs(err, repository.ErrorAlbumVersionMismatch)
+	suite.Empty(suite.auditWriter.events)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+type HealthService interface {
+	LivenessService(ctx context.Context) *model.HealthReport
+	ReadinessService(ctx context.Context) *model.HealthReport
+	// StartDraining makes readiness fail from now on, ahead of a graceful shutdown.
+	StartDraining()
+}
+
+type healthService struct {
+	healthRepository repository.HealthRepository
+	timeout          time.Duration
+	draining         atomic.Bool
+}
+
+var _ HealthService = (*healthService)(nil)
+
+// NewHealthService bounds the dependency checks of one readiness probe by timeout.
+func NewHealthService(healthRepository repository.HealthRepository, timeout time.Duration) HealthService {
+	return &healthService{healthRepository: healthRepository, timeout: timeout}
+}
+
+// LivenessService only shows the process can still serve requests, so it never touches MySQL.
+func (s *healthService) LivenessService(ctx context.Context) *model.HealthReport {
+	return model.NewHealthReport()
+}
+
+func (s *healthService) ReadinessService(ctx context.Context) *model.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return model.NewHealthReport(
+		runCheck("draining", func() error {
+			if s.draining.Load() {
+				return fmt.Errorf("server is shutting down")
+			}
+			return nil
+		}),
+		runCheck("database", func() error {
+			return s.healthRepository.Ping(ctx)
+		}),
+		runCheck("schema", func() error {
+			version, err := s.healthRepository.SchemaVersion(ctx)
+			if err != nil {
+				return err
+			}
+			if version != repository.SchemaVersion {
+				return fmt.Errorf("schema version is %d, want %d", version, repository.SchemaVersion)
+			}
+			return nil
+		}),
+	)
+}
+
+func (s *healthService) StartDraining() {
+	s.draining.Store(true)
+}
+
+func runCheck(name string, check func() error) *model.HealthCheck {
+	start := time.Now()
+	err := check()
+	result := &model.HealthCheck{Name: name, Status: model.HealthOK, Latency: time.Since(start)}
+	if err != nil {
+		result.Status = model.HealthFail
+		result.Error = err.Error()
+	}
+	return result
+}
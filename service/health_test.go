@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockHealthRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockHealthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type HealthServiceSuite struct {
+	suite.Suite
+	healthService        service.HealthService
+	mockHealthRepository *MockHealthRepository
+}
+
+func TestHealthServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthServiceSuite))
+}
+
+func (suite *HealthServiceSuite) SetupTest() {
+	suite.mockHealthRepository = new(MockHealthRepository)
+	suite.healthService = service.NewHealthService(suite.mockHealthRepository, time.Second)
+}
+
+func (suite *HealthServiceSuite) checkStatuses(report *model.HealthReport) map[string]model.HealthStatus {
+	statuses := make(map[string]model.HealthStatus)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func (suite *HealthServiceSuite) TestLivenessService() {
+	report := suite.healthService.LivenessService(context.Background())
+
+	suite.True(report.OK())
+	suite.mockHealthRepository.AssertNotCalled(suite.T(), "Ping", mock.Anything)
+}
+
+func (suite *HealthServiceSuite) TestReadinessService_Ready() {
+	suite.mockHealthRepository.On("Ping", mock.Anything).Return(nil)
+	suite.mockHealthRepository.On("SchemaVersion", mock.Anything).Return(repository.SchemaVersion, nil)
+
+	report := suite.healthService.ReadinessService(context.Background())
+
+	suite.True(report.OK())
+	suite.Equal(map[string]model.HealthStatus{
+		"draining": model.HealthOK,
+		"database": model.HealthOK,
+		"schema":   model.HealthOK,
+	}, suite.checkStatuses(report))
+}
+
+func (suite *HealthServiceSuite) TestReadinessService_BoundedByTimeout() {
+	var deadline time.Time
+	suite.mockHealthRepository.On("Ping", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		deadline, _ = args.Get(0).(context.Context).Deadline()
+	})
+	suite.mockHealthRepository.On("SchemaVersion", mock.Anything).Return(repository.SchemaVersion, nil)
+
+	suite.healthService.ReadinessService(context.Background())
+
+	suite.WithinDuration(time.Now().Add(time.Second), deadline, time.Second)
+}
+
+func (suite *HealthServiceSuite) TestReadinessService_Failures() {
+	unavailable := model.NewError(model.KindUnavailable, "database unavailable", errors.New("connection refused"))
+	suite.mockHealthRepository.On("Ping", mock.Anything).Return(unavailable)
+	suite.mockHealthRepository.On("SchemaVersion", mock.Anything).Return(repository.SchemaVersion-1, nil)
+
+	report := suite.healthService.ReadinessService(context.Background())
+
+	suite.False(report.OK())
+	suite.Equal(model.HealthFail, suite.checkStatuses(report)["database"])
+	suite.Equal(model.HealthFail, suite.checkStatuses(report)["schema"])
+	suite.Equal(model.HealthOK, suite.checkStatuses(report)["draining"])
+}
+
+func (suite *HealthServiceSuite) TestReadinessService_Draining() {
+	suite.mockHealthRepository.On("Ping", mock.Anything).Return(nil)
+	suite.mockHealthRepository.On("SchemaVersion", mock.Anything).Return(repository.SchemaVersion, nil)
+
+	suite.healthService.StartDraining()
+	report := suite.healthService.ReadinessService(context.Background())
+
+	suite.False(report.OK())
+	suite.Equal(model.HealthFail, suite.checkStatuses(report)["draining"])
+	suite.True(suite.healthService.LivenessService(context.Background()).OK())
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+type ImportService interface {
+	// StartImportService records the job and imports the file in the background. The returned
+	// job is a snapshot; poll GetImportService for progress.
+	StartImportService(ctx context.Context, file *model.ImportFile, dryRun bool) (*model.ImportJob, error)
+	// GetImportService returns a job the caller started.
+	GetImportService(ctx context.Context, id string) (*model.ImportJob, error)
+	// Close waits for running imports to finish. Those still running when ctx is done are
+	// stopped and marked failed. Call it before closing the database.
+	Close(ctx context.Context) error
+}
+
+type importService struct {
+	importJobRepository repository.ImportJobRepository
+	singerRepository    repository.SingerRepository
+	albumRepository     repository.AlbumRepository
+	transactor          repository.Transactor
+	auditWriter         AuditWriter
+
+	workers sync.WaitGroup
+	// stopping is canceled when Close gives up waiting, which stops the running imports.
+	stopping context.Context
+	stop     context.CancelFunc
+}
+
+var _ ImportService = (*importService)(nil)
+
+func NewImportService(
+	importJobRepository repository.ImportJobRepository,
+	singerRepository repository.SingerRepository,
+	albumRepository repository.AlbumRepository,
+	transactor repository.Transactor,
+	auditWriter AuditWriter,
+) ImportService {
+	stopping, stop := context.WithCancel(context.Background())
+	return &importService{
+		importJobRepository: importJobRepository,
+		singerRepository:    singerRepository,
+		albumRepository:     albumRepository,
+		transactor:          transactor,
+		auditWriter:         auditWriter,
+		stopping:            stopping,
+		stop:                stop,
+	}
+}
+
+func (s *importService) StartImportService(ctx context.Context, file *model.ImportFile, dryRun bool) (*model.ImportJob, error) {
+	id, err := newImportJobID()
+	if err != nil {
+		return nil, err
+	}
+	job := &model.ImportJob{
+		ID:        id,
+		Subject:   subject(ctx),
+		DryRun:    dryRun,
+		Status:    model.ImportRunning,
+		TotalRows: len(file.Rows) + len(file.Errors),
+		Errors:    file.Errors,
+		CreatedAt: time.Now(),
+	}
+	job.ProcessedRows = len(file.Errors)
+	if err = s.importJobRepository.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	snapshot := job.Clone()
+	// The import outlives the request that started it, but not the server.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stopped := context.AfterFunc(s.stopping, cancel)
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		defer cancel()
+		defer stopped()
+		s.run(ctx, job, file.Rows)
+	}()
+	return snapshot, nil
+}
+
+func (s *importService) GetImportService(ctx context.Context, id string) (*model.ImportJob, error) {
+	return s.importJobRepository.Get(ctx, subject(ctx), id)
+}
+
+func (s *importService) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.stop()
+		<-done
+		return fmt.Errorf("imports stopped before finishing: %w", ctx.Err())
+	}
+}
+
+// subject is the principal ctx belongs to, or empty without one.
+func subject(ctx context.Context) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.Subject
+	}
+	return ""
+}
+
+// importOutcome says what importing one row did, or would do in a dry run.
+type importOutcome struct {
+	singerCreated bool
+	albumCreated  bool
+}
+
+// importPlan remembers what a dry run would have created, so that later rows of the same
+// file see it like an import would.
+type importPlan struct {
+	singers map[string]bool
+	albums  map[importAlbumKey]bool
+}
+
+type importAlbumKey struct {
+	singerID   model.SingerID
+	singerName string
+	title      string
+}
+
+func (s *importService) run(ctx context.Context, job *model.ImportJob, rows []*model.ImportRow) {
+	plan := &importPlan{singers: make(map[string]bool), albums: make(map[importAlbumKey]bool)}
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "import stopped by shutdown", "job_id", job.ID, "line", row.Line)
+			job.Status = model.ImportFailed
+			job.Failure = fmt.Sprintf("import stopped by shutdown at line %d", row.Line)
+			break
+		}
+		outcome, err := s.importRow(ctx, job.DryRun, row, plan)
+		job.ProcessedRows++
+		switch kind := model.KindOf(err); {
+		case err == nil:
+			if outcome.singerCreated {
+				job.SingersCreated++
+			}
+			if outcome.albumCreated {
+				job.AlbumsCreated++
+			}
+			if !outcome.singerCreated && !outcome.albumCreated {
+				job.Unchanged++
+			}
+		case kind == model.KindInternal || kind == model.KindUnavailable:
+			logging.FromContext(ctx).ErrorContext(ctx, "import failed", "job_id", job.ID, "line", row.Line, "error", err)
+			job.Status = model.ImportFailed
+			job.Failure = fmt.Sprintf("import stopped at line %d", row.Line)
+		default:
+			job.Errors = append(job.Errors, importRowErrors(row, err)...)
+		}
+		if job.Status == model.ImportFailed {
+			break
+		}
+		s.save(ctx, job)
+	}
+
+	if job.Status != model.ImportFailed {
+		job.Status = model.ImportCompleted
+	}
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	s.save(ctx, job)
+}
+
+func (s *importService) save(ctx context.Context, job *model.ImportJob) {
+	if err := s.importJobRepository.Save(ctx, job); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "failed to save import job", "job_id", job.ID, "error", err)
+	}
+}
+
+func (s *importService) importRow(ctx context.Context, dryRun bool, row *model.ImportRow, plan *importPlan) (importOutcome, error) {
+	if err := row.Validate(); err != nil {
+		return importOutcome{}, err
+	}
+	if dryRun {
+		return s.planRow(ctx, row, plan)
+	}
+
+	var outcome importOutcome
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		outcome, err = s.applyRow(ctx, row)
+		return err
+	})
+	return outcome, err
+}
+
+func (s *importService) applyRow(ctx context.Context, row *model.ImportRow) (importOutcome, error) {
+	var outcome importOutcome
+	if row.Type == model.ImportSinger {
+		_, created, err := s.resolveSinger(ctx, row.Name)
+		outcome.singerCreated = created
+		return outcome, err
+	}
+
+	singerID := row.SingerID
+	if singerID != 0 {
+		if _, err := s.singerRepository.Get(ctx, singerID); err != nil {
+			return outcome, err
+		}
+	} else {
+		singer, created, err := s.resolveSinger(ctx, row.SingerName)
+		if err != nil {
+			return outcome, err
+		}
+		singerID = singer.ID
+		outcome.singerCreated = created
+	}
+
+	// The lookups spare the common case a failed insert. AddIfAbsent still settles the race
+	// with an import that adds the same singer or album in the meantime.
+	_, err := s.albumRepository.GetBySingerAndTitle(ctx, singerID, row.Title)
+	if !errors.Is(err, repository.ErrorAlbumNotFound) {
+		return outcome, err
+	}
+	album := &model.Album{Title: row.Title, SingerID: singerID}
+	if outcome.albumCreated, err = s.albumRepository.AddIfAbsent(ctx, album); err != nil || !outcome.albumCreated {
+		return outcome, err
+	}
+	after, err := s.albumRepository.Get(ctx, album.ID)
+	if err != nil {
+		return outcome, err
+	}
+	return outcome, writeAudit(ctx, s.auditWriter, model.AuditCreate, model.AuditEntityAlbum, int(album.ID), nil, after)
+}
+
+// resolveSinger returns the singer called name, creating it when there is none.
+func (s *importService) resolveSinger(ctx context.Context, name string) (*model.Singer, bool, error) {
+	singer, err := s.singerRepository.GetByName(ctx, name)
+	if !errors.Is(err, repository.ErrorSingerNotFound) {
+		return singer, false, err
+	}
+	singer = &model.Singer{Name: name}
+	created, err := s.singerRepository.AddIfAbsent(ctx, singer)
+	if err != nil || !created {
+		return singer, false, err
+	}
+	after, err := s.singerRepository.Get(ctx, singer.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err = writeAudit(ctx, s.auditWriter, model.AuditCreate, model.AuditEntitySinger, int(singer.ID), nil, after); err != nil {
+		return nil, false, err
+	}
+	return singer, true, nil
+}
+
+func (s *importService) planRow(ctx context.Context, row *model.ImportRow, plan *importPlan) (importOutcome, error) {
+	var outcome importOutcome
+	if row.Type == model.ImportSinger {
+		if plan.singers[row.Name] {
+			return outcome, nil
+		}
+		_, err := s.singerRepository.GetByName(ctx, row.Name)
+		switch {
+		case errors.Is(err, repository.ErrorSingerNotFound):
+			plan.singers[row.Name] = true
+			outcome.singerCreated = true
+		case err != nil:
+			return outcome, err
+		}
+		return outcome, nil
+	}
+
+	key := importAlbumKey{singerID: row.SingerID, title: row.Title}
+	if row.SingerID != 0 {
+		if _, err := s.singerRepository.Get(ctx, row.SingerID); err != nil {
+			return outcome, err
+		}
+	} else if !plan.singers[row.SingerName] {
+		singer, err := s.singerRepository.GetByName(ctx, row.SingerName)
+		switch {
+		case errors.Is(err, repository.ErrorSingerNotFound):
+			plan.singers[row.SingerName] = true
+			outcome.singerCreated = true
+			key.singerName = row.SingerName
+		case err != nil:
+			return outcome, err
+		default:
+			key.singerID = singer.ID
+		}
+	} else {
+		key.singerName = row.SingerName
+	}
+
+	if plan.albums[key] {
+		return outcome, nil
+	}
+	// A singer that does not exist yet has no albums to find.
+	if key.singerID != 0 {
+		_, err := s.albumRepository.GetBySingerAndTitle(ctx, key.singerID, row.Title)
+		if !errors.Is(err, repository.ErrorAlbumNotFound) {
+			return outcome, err
+		}
+	}
+	plan.albums[key] = true
+	outcome.albumCreated = true
+	return outcome, nil
+}
+
+// importRowErrors reports why row was skipped. Errors that are not about a particular field
+// are put on the field that references the singer, which is what they are about.
+func importRowErrors(row *model.ImportRow, err error) []model.ImportRowError {
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		rowErrs := make([]model.ImportRowError, len(validationErr.Fields))
+		for i, f := range validationErr.Fields {
+			rowErrs[i] = model.ImportRowError{Line: row.Line, FieldError: f}
+		}
+		return rowErrs
+	}
+
+	field := "name"
+	switch {
+	case row.Type == model.ImportAlbum && row.SingerID != 0:
+		field = "singer_id"
+	case row.Type == model.ImportAlbum:
+		field = "singer_name"
+	}
+	message := err.Error()
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		message = domainErr.Message
+	}
+	return []model.ImportRowError{{
+		Line:       row.Line,
+		FieldError: model.FieldError{Field: field, Code: model.KindOf(err).String(), Message: message},
+	}}
+}
+
+func newImportJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", model.NewError(model.KindInternal, "failed to generate import job id", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"context"
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type ImportServiceSuite struct {
+	suite.Suite
+	importService        service.ImportService
+	mockSingerRepository *MockSingerRepository
+	mockAlbumRepository  *MockAlbumRepository
+	mockTransactor       *MockTransactor
+	auditWriter          *capturingAuditWriter
+}
+
+func TestImportServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportServiceSuite))
+}
+
+// The import runs in the background, so every test gets fresh mocks.
+func (suite *ImportServiceSuite) SetupTest() {
+	suite.mockSingerRepository = NewMockSingerRepository()
+	suite.mockAlbumRepository = NewMockAlbumRepository()
+	suite.mockTransactor = NewMockTransactor()
+	suite.mockTransactor.On("WithinTx", mock.Anything).Return()
+	suite.auditWriter = &capturingAuditWriter{}
+	suite.importService = service.NewImportService(
+		repository.NewInMemoryImportJobRepository(time.Hour),
+		suite.mockSingerRepository,
+		suite.mockAlbumRepository,
+		suite.mockTransactor,
+		suite.auditWriter,
+	)
+}
+
+// finished polls the job until the background import is done.
+func (suite *ImportServiceSuite) finished(id string) *model.ImportJob {
+	var job *model.ImportJob
+	suite.Require().Eventually(func() bool {
+		var err error
+		job, err = suite.importService.GetImportService(context.Background(), id)
+		return err == nil && job.FinishedAt != nil
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func (suite *ImportServiceSuite) TestStartImportService() {
+	ctx := context.Background()
+	alice := &model.Singer{ID: model.SingerID(10), Name: "Alice"}
+
+	suite.mockSingerRepository.On("GetByName", mock.Anything, "Alice").Return(nil, repository.ErrorSingerNotFound).Once()
+	suite.mockSingerRepository.On("AddIfAbsent", mock.Anything, &model.Singer{Name: "Alice"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*model.Singer).ID = alice.ID }).
+		Return(true, nil)
+	suite.mockSingerRepository.On("Get", mock.Anything, alice.ID).Return(alice, nil)
+	suite.mockSingerRepository.On("GetByName", mock.Anything, "Alice").Return(alice, nil)
+	suite.mockAlbumRepository.On("GetBySingerAndTitle", mock.Anything, alice.ID, "First").Return(nil, repository.ErrorAlbumNotFound)
+	suite.mockAlbumRepository.On("AddIfAbsent", mock.Anything, &model.Album{Title: "First", SingerID: alice.ID}).
+		Run(func(args mock.Arguments) { args.Get(1).(*model.Album).ID = model.AlbumID(20) }).
+		Return(true, nil)
+	suite.mockAlbumRepository.On("Get", mock.Anything, model.AlbumID(20)).
+		Return(&model.Album{ID: model.AlbumID(20), Title: "First", SingerID: alice.ID, Singer: alice}, nil)
+	suite.mockSingerRepository.On("Get", mock.Anything, model.SingerID(99)).Return(nil, repository.ErrorSingerNotFound)
+
+	file := &model.ImportFile{
+		Rows: []*model.ImportRow{
+			{Line: 2, Type: model.ImportSinger, Name: "Alice"},
+			{Line: 3, Type: model.ImportAlbum, Title: "First", SingerName: "Alice"},
+			{Line: 4, Type: model.ImportAlbum, SingerID: model.SingerID(3)},
+			{Line: 5, Type: model.ImportAlbum, Title: "Second", SingerID: model.SingerID(99)},
+		},
+		Errors: []model.ImportRowError{
+			{Line: 6, FieldError: model.FieldError{Field: "singer_id", Code: model.CodeInvalidType}},
+		},
+	}
+	started, err := suite.importService.StartImportService(ctx, file, false)
+	suite.Require().NoError(err)
+	suite.Equal(model.ImportRunning, started.Status)
+	suite.Equal(5, started.TotalRows)
+
+	job := suite.finished(started.ID)
+	suite.Equal(model.ImportCompleted, job.Status)
+	suite.Equal(5, job.ProcessedRows)
+	suite.Equal(1, job.SingersCreated)
+	suite.Equal(1, job.AlbumsCreated)
+	suite.Equal(0, job.Unchanged)
+	suite.Equal([]model.ImportRowError{
+		{Line: 6, FieldError: model.FieldError{Field: "singer_id", Code: model.CodeInvalidType}},
+		{Line: 4, FieldError: model.FieldError{Field: "title", Code: model.CodeRequired, Message: "title is required"}},
+		{Line: 5, FieldError: model.FieldError{Field: "singer_id", Code: "not_found", Message: "singer not found"}},
+	}, job.Errors)
+
+	suite.Require().Len(suite.auditWriter.events, 2)
+	suite.Equal(model.AuditCreate, suite.auditWriter.events[0].Action)
+	suite.Equal(model.AuditEntitySinger, suite.auditWriter.events[0].EntityType)
+	suite.Equal(int(alice.ID), suite.auditWriter.events[0].EntityID)
+	suite.Equal(model.AuditCreate, suite.auditWriter.events[1].Action)
+	suite.Equal(model.AuditEntityAlbum, suite.auditWriter.events[1].EntityType)
+	suite.Equal(20, suite.auditWriter.events[1].EntityID)
+}
+
+func (suite *ImportServiceSuite) TestStartImportService_DryRun() {
+	ctx := context.Background()
+	suite.mockSingerRepository.On("GetByName", mock.Anything, "Bea").Return(nil, repository.ErrorSingerNotFound)
+
+	file := &model.ImportFile{Rows: []*model.ImportRow{
+		{Line: 1, Type: model.ImportSinger, Name: "Bea"},
+		{Line: 2, Type: model.ImportAlbum, Title: "Debut", SingerName: "Bea"},
+		{Line: 3, Type: model.ImportAlbum, Title: "Debut", SingerName: "Bea"},
+	}}
+	started, err := suite.importService.StartImportService(ctx, file, true)
+	suite.Require().NoError(err)
+
+	job := suite.finished(started.ID)
+	suite.Equal(model.ImportCompleted, job.Status)
+	suite.True(job.DryRun)
+	suite.Equal(1, job.SingersCreated)
+	suite.Equal(1, job.AlbumsCreated)
+	suite.Equal(1, job.Unchanged)
+	suite.Empty(job.Errors)
+	suite.mockSingerRepository.AssertNumberOfCalls(suite.T(), "GetByName", 1)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "AddIfAbsent", mock.Anything, mock.Anything)
+	suite.mockAlbumRepository.AssertNotCalled(suite.T(), "AddIfAbsent", mock.Anything, mock.Anything)
+}
+
+func (suite *ImportServiceSuite) TestStartImportService_AddedConcurrently() {
+	ctx := context.Background()
+	erin := &model.Singer{ID: model.SingerID(11), Name: "Erin"}
+
+	// Another import adds Erin and her album between the lookups and the inserts.
+	suite.mockSingerRepository.On("GetByName", mock.Anything, "Erin").Return(nil, repository.ErrorSingerNotFound)
+	suite.mockSingerRepository.On("AddIfAbsent", mock.Anything, &model.Singer{Name: "Erin"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*model.Singer).ID = erin.ID }).
+		Return(false, nil)
+	suite.mockAlbumRepository.On("GetBySingerAndTitle", mock.Anything, erin.ID, "Live").Return(nil, repository.ErrorAlbumNotFound)
+	suite.mockAlbumRepository.On("AddIfAbsent", mock.Anything, &model.Album{Title: "Live", SingerID: erin.ID}).Return(false, nil)
+
+	file := &model.ImportFile{Rows: []*model.ImportRow{
+		{Line: 1, Type: model.ImportAlbum, Title: "Live", SingerName: "Erin"},
+	}}
+	started, err := suite.importService.StartImportService(ctx, file, false)
+	suite.Require().NoError(err)
+
+	job := suite.finished(started.ID)
+	suite.Equal(model.ImportCompleted, job.Status)
+	suite.Equal(0, job.SingersCreated)
+	suite.Equal(0, job.AlbumsCreated)
+	suite.Equal(1, job.Unchanged)
+	suite.Empty(job.Errors)
+	suite.Empty(suite.auditWriter.events)
+}
+
+func (suite *ImportServiceSuite) TestStartImportService_Unavailable() {
+	ctx := context.Background()
+	unavailable := model.NewError(model.KindUnavailable, "database unavailable", nil)
+	suite.mockSingerRepository.On("GetByName", mock.Anything, "Cleo").Return(nil, unavailable)
+
+	file := &model.ImportFile{Rows: []*model.ImportRow{
+		{Line: 1, Type: model.ImportSinger, Name: "Cleo"},
+		{Line: 2, Type: model.ImportSinger, Name: "Dana"},
+	}}
+	started, err := suite.importService.StartImportService(ctx, file, false)
+	suite.Require().NoError(err)
+
+	job := suite.finished(started.ID)
+	suite.Equal(model.ImportFailed, job.Status)
+	suite.Equal(1, job.ProcessedRows)
+	suite.Equal("import stopped at line 1", job.Failure)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "GetByName", mock.Anything, "Dana")
+}
+
+func (suite *ImportServiceSuite) TestGetImportService_NotFound() {
+	_, err := suite.importService.GetImportService(context.Background(), "missing")
+	suite.ErrorIs(err, repository.ErrorImportJobNotFound)
+}
+
+func (suite *ImportServiceSuite) TestGetImportService_OtherPrincipal() {
+	alice := auth.NewContext(context.Background(), &model.Principal{Subject: "alice", Role: model.RoleEditor})
+	bob := auth.NewContext(context.Background(), &model.Principal{Subject: "bob", Role: model.RoleViewer})
+
+	started, err := suite.importService.StartImportService(alice, &model.ImportFile{}, true)
+	suite.Require().NoError(err)
+	suite.Equal("alice", started.Subject)
+
+	_, err = suite.importService.GetImportService(alice, started.ID)
+	suite.NoError(err)
+	_, err = suite.importService.GetImportService(bob, started.ID)
+	suite.ErrorIs(err, repository.ErrorImportJobNotFound)
+	_, err = suite.importService.GetImportService(context.Background(), started.ID)
+	suite.ErrorIs(err, repository.ErrorImportJobNotFound)
+}
+
+func (suite *ImportServiceSuite) TestClose() {
+	started, err := suite.importService.StartImportService(context.Background(), &model.ImportFile{}, false)
+	suite.Require().NoError(err)
+
+	suite.NoError(suite.importService.Close(context.Background()))
+	job, err := suite.importService.GetImportService(context.Background(), started.ID)
+	suite.Require().NoError(err)
+	suite.Equal(model.ImportCompleted, job.Status)
+}
+
+func (suite *ImportServiceSuite) TestClose_StopsRunningImports() {
+	// the first row takes until the import is stopped
+	suite.mockSingerRepository.On("GetByName", mock.Anything, "Cleo").
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(&model.Singer{ID: model.SingerID(3), Name: "Cleo"}, nil)
+
+	file := &model.ImportFile{Rows: []*model.ImportRow{
+		{Line: 1, Type: model.ImportSinger, Name: "Cleo"},
+		{Line: 2, Type: model.ImportSinger, Name: "Dana"},
+	}}
+	started, err := suite.importService.StartImportService(context.Background(), file, false)
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	suite.ErrorIs(suite.importService.Close(ctx), context.DeadlineExceeded)
+
+	// Close only returns once the import has stopped.
+	job, err := suite.importService.GetImportService(context.Background(), started.ID)
+	suite.Require().NoError(err)
+	suite.Equal(model.ImportFailed, job.Status)
+	suite.Equal("import stopped by shutdown at line 2", job.Failure)
+	suite.NotNil(job.FinishedAt)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "GetByName", mock.Anything, "Dana")
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// Observer is told about every call to an observed service, e.g. to start a span for it.
+// StartCall may return a derived context for the call; done receives the call's error.
+type Observer interface {
+	StartCall(ctx context.Context, service, method string) (_ context.Context, done func(err error))
+}
+
+type observedSingerService struct {
+	next     SingerService
+	observer Observer
+}
+
+var _ SingerService = (*observedSingerService)(nil)
+
+// ObserveSingerService reports every call of next to observer.
+func ObserveSingerService(next SingerService, observer Observer) SingerService {
+	return &observedSingerService{next: next, observer: observer}
+}
+
+func (s *observedSingerService) GetSingerListService(ctx context.Context, q *model.SingerQuery) (_ *model.Page[*model.Singer], err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "GetSingerListService")
+	defer func() { done(err) }()
+	return s.next.GetSingerListService(ctx, q)
+}
+
+func (s *observedSingerService) GetSingerService(ctx context.Context, singerID model.SingerID) (_ *model.Singer, err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "GetSingerService")
+	defer func() { done(err) }()
+	return s.next.GetSingerService(ctx, singerID)
+}
+
+func (s *observedSingerService) GetSingerAlbumsService(ctx context.Context, singerID model.SingerID) (_ []*model.SingerAlbum, err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "GetSingerAlbumsService")
+	defer func() { done(err) }()
+	return s.next.GetSingerAlbumsService(ctx, singerID)
+}
+
+func (s *observedSingerService) GetSingerDiscographyService(ctx context.Context, singerID model.SingerID) (_ *model.Singer, _ *model.Discography, err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "GetSingerDiscographyService")
+	defer func() { done(err) }()
+	return s.next.GetSingerDiscographyService(ctx, singerID)
+}
+
+func (s *observedSingerService) PostSingerService(ctx context.Context, singer *model.Singer) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "PostSingerService")
+	defer func() { done(err) }()
+	return s.next.PostSingerService(ctx, singer)
+}
+
+func (s *observedSingerService) UpdateSingerService(ctx context.Context, singer *model.Singer) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "UpdateSingerService")
+	defer func() { done(err) }()
+	return s.next.UpdateSingerService(ctx, singer)
+}
+
+func (s *observedSingerService) DeleteSingerService(ctx context.Context, singerID model.SingerID, version int64) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "DeleteSingerService")
+	defer func() { done(err) }()
+	return s.next.DeleteSingerService(ctx, singerID, version)
+}
+
+func (s *observedSingerService) DeleteSingerCascadeService(ctx context.Context, singerID model.SingerID, version int64, dryRun bool) (_ *model.SingerDeletion, err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "DeleteSingerCascadeService")
+	defer func() { done(err) }()
+	return s.next.DeleteSingerCascadeService(ctx, singerID, version, dryRun)
+}
+
+func (s *observedSingerService) RestoreSingerService(ctx context.Context, singerID model.SingerID) (_ *model.Singer, err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "RestoreSingerService")
+	defer func() { done(err) }()
+	return s.next.RestoreSingerService(ctx, singerID)
+}
+
+func (s *observedSingerService) ExportSingersService(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "ExportSingersService")
+	defer func() { done(err) }()
+	return s.next.ExportSingersService(ctx, q, fn)
+}
+
+func (s *observedSingerService) BatchSingerService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Singer]) (_ []*model.BatchResult, err error) {
+	ctx, done := s.observer.StartCall(ctx, "singer", "BatchSingerService")
+	defer func() { done(err) }()
+	return s.next.BatchSingerService(ctx, mode, ops)
+}
+
+type observedAlbumService struct {
+	next     AlbumService
+	observer Observer
+}
+
+var _ AlbumService = (*observedAlbumService)(nil)
+
+// ObserveAlbumService reports every call of next to observer.
+func ObserveAlbumService(next AlbumService, observer Observer) AlbumService {
+	return &observedAlbumService{next: next, observer: observer}
+}
+
+func (s *observedAlbumService) GetAlbumListService(ctx context.Context, q *model.AlbumQuery) (_ *model.Page[*model.Album], err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "GetAlbumListService")
+	defer func() { done(err) }()
+	return s.next.GetAlbumListService(ctx, q)
+}
+
+func (s *observedAlbumService) GetAlbumService(ctx context.Context, albumID model.AlbumID) (_ *model.Album, err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "GetAlbumService")
+	defer func() { done(err) }()
+	return s.next.GetAlbumService(ctx, albumID)
+}
+
+func (s *observedAlbumService) GetAlbumWithTracksService(ctx context.Context, albumID model.AlbumID) (_ *model.Album, err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "GetAlbumWithTracksService")
+	defer func() { done(err) }()
+	return s.next.GetAlbumWithTracksService(ctx, albumID)
+}
+
+func (s *observedAlbumService) PostAlbumService(ctx context.Context, album *model.Album) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "PostAlbumService")
+	defer func() { done(err) }()
+	return s.next.PostAlbumService(ctx, album)
+}
+
+func (s *observedAlbumService) UpdateAlbumService(ctx context.Context, album *model.Album) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "UpdateAlbumService")
+	defer func() { done(err) }()
+	return s.next.UpdateAlbumService(ctx, album)
+}
+
+func (s *observedAlbumService) DeleteAlbumService(ctx context.Context, albumID model.AlbumID, version int64) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "DeleteAlbumService")
+	defer func() { done(err) }()
+	return s.next.DeleteAlbumService(ctx, albumID, version)
+}
+
+func (s *observedAlbumService) RestoreAlbumService(ctx context.Context, albumID model.AlbumID) (_ *model.Album, err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "RestoreAlbumService")
+	defer func() { done(err) }()
+	return s.next.RestoreAlbumService(ctx, albumID)
+}
+
+func (s *observedAlbumService) ExportAlbumsService(ctx context.Context, q *model.AlbumQuery, fn func(*model.Album) error) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "ExportAlbumsService")
+	defer func() { done(err) }()
+	return s.next.ExportAlbumsService(ctx, q, fn)
+}
+
+func (s *observedAlbumService) BatchAlbumService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Album]) (_ []*model.BatchResult, err error) {
+	ctx, done := s.observer.StartCall(ctx, "album", "BatchAlbumService")
+	defer func() { done(err) }()
+	return s.next.BatchAlbumService(ctx, mode, ops)
+}
+
+type observedTrackService struct {
+	next     TrackService
+	observer Observer
+}
+
+var _ TrackService = (*observedTrackService)(nil)
+
+// ObserveTrackService reports every call of next to observer.
+func ObserveTrackService(next TrackService, observer Observer) TrackService {
+	return &observedTrackService{next: next, observer: observer}
+}
+
+func (s *observedTrackService) GetTrackListService(ctx context.Context, albumID model.AlbumID) (_ []*model.Track, err error) {
+	ctx, done := s.observer.StartCall(ctx, "track", "GetTrackListService")
+	defer func() { done(err) }()
+	return s.next.GetTrackListService(ctx, albumID)
+}
+
+func (s *observedTrackService) GetTrackService(ctx context.Context, trackID model.TrackID) (_ *model.Track, err error) {
+	ctx, done := s.observer.StartCall(ctx, "track", "GetTrackService")
+	defer func() { done(err) }()
+	return s.next.GetTrackService(ctx, trackID)
+}
+
+func (s *observedTrackService) PostTrackService(ctx context.Context, track *model.Track) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "track", "PostTrackService")
+	defer func() { done(err) }()
+	return s.next.PostTrackService(ctx, track)
+}
+
+func (s *observedTrackService) UpdateTrackService(ctx context.Context, track *model.Track) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "track", "UpdateTrackService")
+	defer func() { done(err) }()
+	return s.next.UpdateTrackService(ctx, track)
+}
+
+func (s *observedTrackService) DeleteTrackService(ctx context.Context, trackID model.TrackID) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "track", "DeleteTrackService")
+	defer func() { done(err) }()
+	return s.next.DeleteTrackService(ctx, trackID)
+}
+
+func (s *observedTrackService) ReorderTracksService(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) (_ []*model.Track, err error) {
+	ctx, done := s.observer.StartCall(ctx, "track", "ReorderTracksService")
+	defer func() { done(err) }()
+	return s.next.ReorderTracksService(ctx, albumID, positions)
+}
+
+type observedCreditService struct {
+	next     CreditService
+	observer Observer
+}
+
+var _ CreditService = (*observedCreditService)(nil)
+
+// ObserveCreditService reports every call of next to observer.
+func ObserveCreditService(next CreditService, observer Observer) CreditService {
+	return &observedCreditService{next: next, observer: observer}
+}
+
+func (s *observedCreditService) GetAlbumCreditsService(ctx context.Context, albumID model.AlbumID) (_ []*model.Credit, err error) {
+	ctx, done := s.observer.StartCall(ctx, "credit", "GetAlbumCreditsService")
+	defer func() { done(err) }()
+	return s.next.GetAlbumCreditsService(ctx, albumID)
+}
+
+func (s *observedCreditService) PutAlbumCreditsService(ctx context.Context, albumID model.AlbumID, version int64, credits []*model.Credit) (_ []*model.Credit, err error) {
+	ctx, done := s.observer.StartCall(ctx, "credit", "PutAlbumCreditsService")
+	defer func() { done(err) }()
+	return s.next.PutAlbumCreditsService(ctx, albumID, version, credits)
+}
+
+type observedSearchService struct {
+	next     SearchService
+	observer Observer
+}
+
+var _ SearchService = (*observedSearchService)(nil)
+
+// ObserveSearchService reports every call of next to observer.
+func ObserveSearchService(next SearchService, observer Observer) SearchService {
+	return &observedSearchService{next: next, observer: observer}
+}
+
+func (s *observedSearchService) SearchCatalogService(ctx context.Context, q *model.SearchQuery) (_ *model.Page[*model.SearchResult], err error) {
+	ctx, done := s.observer.StartCall(ctx, "search", "SearchCatalogService")
+	defer func() { done(err) }()
+	return s.next.SearchCatalogService(ctx, q)
+}
+
+type observedTrashService struct {
+	next     TrashService
+	observer Observer
+}
+
+var _ TrashService = (*observedTrashService)(nil)
+
+// ObserveTrashService reports every call of next to observer.
+func ObserveTrashService(next TrashService, observer Observer) TrashService {
+	return &observedTrashService{next: next, observer: observer}
+}
+
+func (s *observedTrashService) GetTrashService(ctx context.Context) (_ *model.Trash, err error) {
+	ctx, done := s.observer.StartCall(ctx, "trash", "GetTrashService")
+	defer func() { done(err) }()
+	return s.next.GetTrashService(ctx)
+}
+
+func (s *observedTrashService) PurgeTrashService(ctx context.Context, deletedBefore time.Time) (err error) {
+	ctx, done := s.observer.StartCall(ctx, "trash", "PurgeTrashService")
+	defer func() { done(err) }()
+	return s.next.PurgeTrashService(ctx, deletedBefore)
+}
+
+type observedImportService struct {
+	next     ImportService
+	observer Observer
+}
+
+var _ ImportService = (*observedImportService)(nil)
+
+// ObserveImportService reports every call of next to observer.
+func ObserveImportService(next ImportService, observer Observer) ImportService {
+	return &observedImportService{next: next, observer: observer}
+}
+
+func (s *observedImportService) StartImportService(ctx context.Context, file *model.ImportFile, dryRun bool) (_ *model.ImportJob, err error) {
+	ctx, done := s.observer.StartCall(ctx, "import", "StartImportService")
+	defer func() { done(err) }()
+	return s.next.StartImportService(ctx, file, dryRun)
+}
+
+func (s *observedImportService) GetImportService(ctx context.Context, id string) (_ *model.ImportJob, err error) {
+	ctx, done := s.observer.StartCall(ctx, "import", "GetImportService")
+	defer func() { done(err) }()
+	return s.next.GetImportService(ctx, id)
+}
+
+func (s *observedImportService) Close(ctx context.Context) error {
+	return s.next.Close(ctx)
+}
+
+type observedAuthService struct {
+	next     AuthService
+	observer Observer
+}
+
+var _ AuthService = (*observedAuthService)(nil)
+
+// ObserveAuthService reports every call of next to observer.
+func ObserveAuthService(next AuthService, observer Observer) AuthService {
+	return &observedAuthService{next: next, observer: observer}
+}
+
+func (s *observedAuthService) AuthenticateAPIKeyService(ctx context.Context, key string) (_ *model.Principal, err error) {
+	ctx, done := s.observer.StartCall(ctx, "auth", "AuthenticateAPIKeyService")
+	defer func() { done(err) }()
+	return s.next.AuthenticateAPIKeyService(ctx, key)
+}
+
+func (s *observedAuthService) AuthenticateTokenService(ctx context.Context, token string) (_ *model.Principal, err error) {
+	ctx, done := s.observer.StartCall(ctx, "auth", "AuthenticateTokenService")
+	defer func() { done(err) }()
+	return s.next.AuthenticateTokenService(ctx, token)
+}
+
+type observedAuditService struct {
+	next     AuditService
+	observer Observer
+}
+
+var _ AuditService = (*observedAuditService)(nil)
+
+// ObserveAuditService reports every call of next to observer.
+func ObserveAuditService(next AuditService, observer Observer) AuditService {
+	return &observedAuditService{next: next, observer: observer}
+}
+
+func (s *observedAuditService) GetAuditEventsService(ctx context.Context, q *model.AuditQuery) (_ *model.Page[*model.AuditEvent], err error) {
+	ctx, done := s.observer.StartCall(ctx, "audit", "GetAuditEventsService")
+	defer func() { done(err) }()
+	return s.next.GetAuditEventsService(ctx, q)
+}
@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+type recordingObserver struct {
+	calls []string
+	errs  []error
+}
+
+func (o *recordingObserver) StartCall(ctx context.Context, svc, method string) (context.Context, func(error)) {
+	o.calls = append(o.calls, svc+"."+method)
+	return context.WithValue(ctx, ctxKey{}, method), func(err error) {
+		o.errs = append(o.errs, err)
+	}
+}
+
+type stubSearchService struct {
+	ctx context.Context
+}
+
+func (s *stubSearchService) SearchCatalogService(ctx context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error) {
+	s.ctx = ctx
+	return nil, model.ErrNotFound
+}
+
+func TestObserveSearchService(t *testing.T) {
+	observer := &recordingObserver{}
+	next := &stubSearchService{}
+	svc := service.ObserveSearchService(next, observer)
+
+	_, err := svc.SearchCatalogService(context.Background(), &model.SearchQuery{})
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.Equal(t, []string{"search.SearchCatalogService"}, observer.calls)
+	assert.Equal(t, []error{model.ErrNotFound}, observer.errs)
+	// the wrapped service runs with the context returned by the observer
+	assert.Equal(t, "SearchCatalogService", next.ctx.Value(ctxKey{}))
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+type SearchService interface {
+	SearchCatalogService(ctx context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error)
+}
+
+type searchService struct {
+	searchRepository repository.SearchRepository
+}
+
+var _ SearchService = (*searchService)(nil)
+
+func NewSearchService(searchRepository repository.SearchRepository) SearchService {
+	return &searchService{searchRepository: searchRepository}
+}
+
+func (s *searchService) SearchCatalogService(ctx context.Context, q *model.SearchQuery) (*model.Page[*model.SearchResult], error) {
+	q.Query = strings.TrimSpace(q.Query)
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.searchRepository.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range page.Items {
+		result.Highlight = highlight(result.Name, q.Query)
+	}
+	return page, nil
+}
+
+// highlight HTML-escapes text and wraps case-insensitive occurrences of query in <em> tags.
+func highlight(text, query string) string {
+	lowerText := strings.ToLower(text)
+	lowerQuery := strings.ToLower(query)
+	// lowercasing may change byte offsets for some scripts; fall back to no markup then
+	if len(lowerText) != len(text) || len(lowerQuery) != len(query) || query == "" {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lowerText, lowerQuery)
+		if i < 0 {
+			b.WriteString(html.EscapeString(text))
+			return b.String()
+		}
+		b.WriteString(html.EscapeString(text[:i]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[i : i+len(query)]))
+		b.WriteString("</em>")
+		text, lowerText = text[i+len(query):], lowerText[i+len(query):]
+	}
+}
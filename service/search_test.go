@@ -0,0 +1,51 @@
+package service_test
+
+import (
+	"context"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SearchServiceSuite struct {
+	suite.Suite
+	searchService service.SearchService
+}
+
+func TestSearchServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchServiceSuite))
+}
+
+func (suite *SearchServiceSuite) SetupSuite() {
+	singer := &model.Singer{ID: model.SingerID(1), Name: "Alice & Co"}
+	albums := []*model.Album{
+		{ID: model.AlbumID(1), Title: "<Alice> in alice land", SingerID: singer.ID, Singer: singer},
+	}
+	searchRepository := repository.NewInMemorySearchRepository([]*model.Singer{singer}, albums)
+	suite.searchService = service.NewSearchService(searchRepository)
+}
+
+func (suite *SearchServiceSuite) TestSearchServiceSearchCatalogService() {
+	ctx := context.Background()
+
+	page, err := suite.searchService.SearchCatalogService(ctx, &model.SearchQuery{Query: " alice ", Limit: 10})
+
+	suite.Assert().Nil(err)
+	suite.Assert().Len(page.Items, 2)
+	suite.Assert().Equal("&lt;<em>Alice</em>&gt; in <em>alice</em> land", page.Items[0].Highlight)
+	suite.Assert().Equal("<em>Alice</em> &amp; Co", page.Items[1].Highlight)
+}
+
+func (suite *SearchServiceSuite) TestSearchServiceSearchCatalogService_InvalidQuery() {
+	ctx := context.Background()
+
+	q := &model.SearchQuery{Query: "  ", Types: []model.SearchResultType{"track"}, Limit: 10}
+	page, err := suite.searchService.SearchCatalogService(ctx, q)
+
+	suite.Assert().Nil(page)
+	var validationErr *model.ValidationError
+	suite.Assert().ErrorAs(err, &validationErr)
+	suite.Assert().Len(validationErr.Fields, 2)
+}
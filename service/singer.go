@@ -8,28 +8,43 @@ import (
 )
 
 type SingerService interface {
-	GetSingerListService(ctx context.Context) ([]*model.Singer, error)
+	GetSingerListService(ctx context.Context, q *model.SingerQuery) (*model.Page[*model.Singer], error)
 	GetSingerService(ctx context.Context, singerID model.SingerID) (*model.Singer, error)
+	GetSingerAlbumsService(ctx context.Context, singerID model.SingerID) ([]*model.SingerAlbum, error)
+	GetSingerDiscographyService(ctx context.Context, singerID model.SingerID) (*model.Singer, *model.Discography, error)
 	PostSingerService(ctx context.Context, singer *model.Singer) error
-	DeleteSingerService(ctx context.Context, singerID model.SingerID) error
+	UpdateSingerService(ctx context.Context, singer *model.Singer) error
+	DeleteSingerService(ctx context.Context, singerID model.SingerID, version int64) error
+	DeleteSingerCascadeService(ctx context.Context, singerID model.SingerID, version int64, dryRun bool) (*model.SingerDeletion, error)
+	RestoreSingerService(ctx context.Context, singerID model.SingerID) (*model.Singer, error)
+	// ExportSingersService calls fn for every singer matching q, all read from one snapshot.
+	ExportSingersService(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) error
+	BatchSingerService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Singer]) ([]*model.BatchResult, error)
 }
 
 type singerService struct {
 	singerRepository repository.SingerRepository
+	albumRepository  repository.AlbumRepository
+	transactor       repository.Transactor
+	auditWriter      AuditWriter
 }
 
 var _ SingerService = (*singerService)(nil)
 
-func NewSingerService(singerRepository repository.SingerRepository) SingerService {
-	return &singerService{singerRepository: singerRepository}
+func NewSingerService(singerRepository repository.SingerRepository, albumRepository repository.AlbumRepository, transactor repository.Transactor, auditWriter AuditWriter) SingerService {
+	return &singerService{singerRepository: singerRepository, albumRepository: albumRepository, transactor: transactor, auditWriter: auditWriter}
 }
 
-func (s *singerService) GetSingerListService(ctx context.Context) ([]*model.Singer, error) {
-	singers, err := s.singerRepository.GetAll(ctx)
+func (s *singerService) GetSingerListService(ctx context.Context, q *model.SingerQuery) (*model.Page[*model.Singer], error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.singerRepository.GetAll(ctx, q)
 	if err != nil {
 		return nil, err
 	}
-	return singers, nil
+	return page, nil
 }
 
 func (s *singerService) GetSingerService(ctx context.Context, singerID model.SingerID) (*model.Singer, error) {
@@ -40,20 +55,199 @@ func (s *singerService) GetSingerService(ctx context.Context, singerID model.Sin
 	return singer, nil
 }
 
+func (s *singerService) GetSingerAlbumsService(ctx context.Context, singerID model.SingerID) ([]*model.SingerAlbum, error) {
+	// A singer without albums and a missing singer must not look the same.
+	if _, err := s.singerRepository.Get(ctx, singerID); err != nil {
+		return nil, err
+	}
+
+	albums, err := s.albumRepository.GetBySinger(ctx, singerID)
+	if err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+func (s *singerService) GetSingerDiscographyService(ctx context.Context, singerID model.SingerID) (*model.Singer, *model.Discography, error) {
+	singer, err := s.singerRepository.Get(ctx, singerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	albums, err := s.albumRepository.GetBySinger(ctx, singerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return singer, model.NewDiscography(albums), nil
+}
+
 func (s *singerService) PostSingerService(ctx context.Context, singer *model.Singer) error {
 	if err := singer.Validate(); err != nil {
 		return err
 	}
 
-	if err := s.singerRepository.Add(ctx, singer); err != nil {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.singerRepository.Add(ctx, singer); err != nil {
+			return err
+		}
+		return s.auditCreated(ctx, singer.ID)
+	})
+}
+
+// addSingers inserts singers with one statement and audits each of them.
+func (s *singerService) addSingers(ctx context.Context, singers []*model.Singer) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.singerRepository.AddBatch(ctx, singers); err != nil {
+			return err
+		}
+		for _, singer := range singers {
+			if err := s.auditCreated(ctx, singer.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *singerService) auditCreated(ctx context.Context, singerID model.SingerID) error {
+	after, err := s.singerRepository.Get(ctx, singerID)
+	if err != nil {
+		return err
+	}
+	return writeAudit(ctx, s.auditWriter, model.AuditCreate, model.AuditEntitySinger, int(singerID), nil, after)
+}
+
+func (s *singerService) UpdateSingerService(ctx context.Context, singer *model.Singer) error {
+	if err := singer.Validate(); err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, version, err := loadForWrite(ctx, s.singerRepository.Get, singer.ID, singer.Version, singerVersion)
+		if err != nil {
+			return err
+		}
+
+		singer.Version = version
+		if err = s.singerRepository.Update(ctx, singer); err != nil {
+			return err
+		}
+		after, err := s.singerRepository.Get(ctx, singer.ID)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, s.auditWriter, model.AuditUpdate, model.AuditEntitySinger, int(singer.ID), before, after)
+	})
+}
+
+func singerVersion(singer *model.Singer) int64 {
+	return singer.Version
+}
+
+func (s *singerService) DeleteSingerService(ctx context.Context, singerID model.SingerID, version int64) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		return s.deleteSinger(ctx, singerID, version)
+	})
+}
+
+// deleteSinger trashes the singer at version, or at its current version when version is zero,
+// and audits it. It must run within a transaction.
+func (s *singerService) deleteSinger(ctx context.Context, singerID model.SingerID, version int64) error {
+	before, version, err := loadForWrite(ctx, s.singerRepository.Get, singerID, version, singerVersion)
+	if err != nil {
 		return err
 	}
-	return nil
+
+	if err = s.singerRepository.Delete(ctx, singerID, version); err != nil {
+		return err
+	}
+	return writeAudit(ctx, s.auditWriter, model.AuditDelete, model.AuditEntitySinger, int(singerID), before, nil)
+}
+
+// DeleteSingerCascadeService trashes the singer together with every album it is the primary
+// artist of, in one transaction. Tracks and credits go with their albums. A dry run only
+// reports what would be removed. version guards the singer only; its albums are removed at
+// whatever version they are.
+func (s *singerService) DeleteSingerCascadeService(ctx context.Context, singerID model.SingerID, version int64, dryRun bool) (*model.SingerDeletion, error) {
+	deletion := &model.SingerDeletion{SingerID: singerID, DryRun: dryRun}
+	if dryRun {
+		if _, err := s.singerRepository.Get(ctx, singerID); err != nil {
+			return nil, err
+		}
+		albumIDs, err := s.albumRepository.GetIDsBySinger(ctx, singerID)
+		if err != nil {
+			return nil, err
+		}
+		deletion.AlbumIDs = albumIDs
+		return deletion, nil
+	}
+
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		albumIDs, err := s.albumRepository.GetIDsBySinger(ctx, singerID)
+		if err != nil {
+			return err
+		}
+		for _, albumID := range albumIDs {
+			album, err := s.albumRepository.Get(ctx, albumID)
+			if err != nil {
+				return err
+			}
+			if err = s.albumRepository.Delete(ctx, albumID, album.Version); err != nil {
+				return err
+			}
+			if err = writeAudit(ctx, s.auditWriter, model.AuditDelete, model.AuditEntityAlbum, int(albumID), album, nil); err != nil {
+				return err
+			}
+		}
+		// Fails with ErrorSingerHasAlbums if an album was added concurrently, rolling back.
+		if err = s.deleteSinger(ctx, singerID, version); err != nil {
+			return err
+		}
+		deletion.AlbumIDs = albumIDs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+func (s *singerService) RestoreSingerService(ctx context.Context, singerID model.SingerID) (*model.Singer, error) {
+	var singer *model.Singer
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.singerRepository.Restore(ctx, singerID)
+		if err != nil {
+			return err
+		}
+		if singer, err = s.singerRepository.Get(ctx, singerID); err != nil {
+			return err
+		}
+		return writeAudit(ctx, s.auditWriter, model.AuditRestore, model.AuditEntitySinger, int(singerID), nil, singer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return singer, nil
+}
+
+func (s *singerService) BatchSingerService(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation[*model.Singer]) ([]*model.BatchResult, error) {
+	return runBatch(ctx, s.transactor, mode, ops, batchSteps[*model.Singer]{
+		validate: (*model.Singer).Validate,
+		addAll:   s.addSingers,
+		add:      s.PostSingerService,
+		update:   s.UpdateSingerService,
+		delete: func(ctx context.Context, singer *model.Singer) error {
+			return s.DeleteSingerService(ctx, singer.ID, singer.Version)
+		},
+		id: func(singer *model.Singer) int { return int(singer.ID) },
+	})
 }
 
-func (s *singerService) DeleteSingerService(ctx context.Context, singerID model.SingerID) error {
-	if err := s.singerRepository.Delete(ctx, singerID); err != nil {
+func (s *singerService) ExportSingersService(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) error {
+	if err := q.ValidateExport(); err != nil {
 		return err
 	}
-	return nil
+	return s.transactor.WithinReadOnlyTx(ctx, func(ctx context.Context) error {
+		return s.singerRepository.Export(ctx, q, fn)
+	})
 }
@@ -2,11 +2,17 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"github.com/pulse227/server-recruit-challenge-sample/auth"
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
 	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
 	"github.com/pulse227/server-recruit-challenge-sample/service"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type MockSingerRepository struct {
@@ -17,12 +23,23 @@ func NewMockSingerRepository() *MockSingerRepository {
 	return &MockSingerRepository{}
 }
 
-func (m *MockSingerRepository) GetAll(ctx context.Context) ([]*model.Singer, error) {
-	args := m.Called(ctx)
+func (m *MockSingerRepository) GetAll(ctx context.Context, q *model.SingerQuery) (*model.Page[*model.Singer], error) {
+	args := m.Called(ctx, q)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Singer), args.Error(1)
+	return args.Get(0).(*model.Page[*model.Singer]), args.Error(1)
+}
+func (m *MockSingerRepository) Export(ctx context.Context, q *model.SingerQuery, fn func(*model.Singer) error) error {
+	args := m.Called(ctx, q)
+	if singers, ok := args.Get(0).([]*model.Singer); ok {
+		for _, singer := range singers {
+			if err := fn(singer); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
 }
 func (m *MockSingerRepository) Get(ctx context.Context, id model.SingerID) (*model.Singer, error) {
 	args := m.Called(ctx, id)
@@ -31,6 +48,13 @@ func (m *MockSingerRepository) Get(ctx context.Context, id model.SingerID) (*mod
 	}
 	return args.Get(0).(*model.Singer), args.Error(1)
 }
+func (m *MockSingerRepository) GetByName(ctx context.Context, name string) (*model.Singer, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Singer), args.Error(1)
+}
 func (m *MockSingerRepository) Add(ctx context.Context, singer *model.Singer) error {
 	args := m.Called(ctx, singer)
 	if err, ok := args.Get(0).(error); ok {
@@ -38,7 +62,33 @@ func (m *MockSingerRepository) Add(ctx context.Context, singer *model.Singer) er
 	}
 	return nil
 }
-func (m *MockSingerRepository) Delete(ctx context.Context, id model.SingerID) error {
+func (m *MockSingerRepository) AddIfAbsent(ctx context.Context, singer *model.Singer) (bool, error) {
+	args := m.Called(ctx, singer)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockSingerRepository) AddBatch(ctx context.Context, singers []*model.Singer) error {
+	args := m.Called(ctx, singers)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+func (m *MockSingerRepository) Update(ctx context.Context, singer *model.Singer) error {
+	args := m.Called(ctx, singer)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+func (m *MockSingerRepository) Delete(ctx context.Context, id model.SingerID, version int64) error {
+	args := m.Called(ctx, id, version)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockSingerRepository) Restore(ctx context.Context, id model.SingerID) error {
 	args := m.Called(ctx, id)
 	if err, ok := args.Get(0).(error); ok {
 		return err
@@ -46,10 +96,44 @@ func (m *MockSingerRepository) Delete(ctx context.Context, id model.SingerID) er
 	return nil
 }
 
+func (m *MockSingerRepository) GetDeleted(ctx context.Context) ([]*model.Singer, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Singer), args.Error(1)
+}
+
+func (m *MockSingerRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockTransactor struct {
+	mock.Mock
+}
+
+func NewMockTransactor() *MockTransactor {
+	return &MockTransactor{}
+}
+
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called(ctx)
+	return fn(ctx)
+}
+
+func (m *MockTransactor) WithinReadOnlyTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called(ctx)
+	return fn(ctx)
+}
+
 type SingerServiceSuite struct {
 	suite.Suite
 	singerService        service.SingerService
 	mockSingerRepository *MockSingerRepository
+	mockAlbumRepository  *MockAlbumRepository
+	mockTransactor       *MockTransactor
+	auditWriter          *capturingAuditWriter
 }
 
 func TestSingerServiceTestSuite(t *testing.T) {
@@ -58,7 +142,14 @@ func TestSingerServiceTestSuite(t *testing.T) {
 
 func (suite *SingerServiceSuite) SetupSuite() {
 	suite.mockSingerRepository = NewMockSingerRepository()
-	suite.singerService = service.NewSingerService(suite.mockSingerRepository)
+	suite.mockAlbumRepository = NewMockAlbumRepository()
+	suite.mockTransactor = NewMockTransactor()
+	suite.auditWriter = &capturingAuditWriter{}
+	suite.singerService = service.NewSingerService(suite.mockSingerRepository, suite.mockAlbumRepository, suite.mockTransactor, suite.auditWriter)
+}
+
+func (suite *SingerServiceSuite) SetupTest() {
+	*suite.auditWriter = capturingAuditWriter{}
 }
 
 func (suite *SingerServiceSuite) TestSingerServiceGetSingerListService() {
@@ -68,9 +159,11 @@ func (suite *SingerServiceSuite) TestSingerServiceGetSingerListService() {
 		{ID: model.SingerID(1), Name: "Test Singer 1"},
 		{ID: model.SingerID(2), Name: "Test Singer 2"},
 	}
-	suite.mockSingerRepository.On("GetAll", ctx).Return(singers, nil)
+	q := &model.SingerQuery{Limit: model.DefaultPageLimit, Sort: model.SingerSortID}
+	suite.mockSingerRepository.On("GetAll", ctx, q).Return(&model.Page[*model.Singer]{Items: singers}, nil)
 
-	result, err := suite.singerService.GetSingerListService(ctx)
+	page, err := suite.singerService.GetSingerListService(ctx, q)
+	result := page.Items
 	suite.Assert().Nil(err)
 	suite.Assert().Equal(singers, result)
 	suite.Assert().Equal(len(singers), len(result))
@@ -96,8 +189,10 @@ func (suite *SingerServiceSuite) TestSingerServiceGetSingerService() {
 func (suite *SingerServiceSuite) TestSingerServicePostSingerService() {
 	ctx := context.Background()
 
-	singer := &model.Singer{ID: model.SingerID(1), Name: "Test Singer"}
+	singer := &model.Singer{ID: model.SingerID(43), Name: "Test Singer"}
+	suite.mockTransactor.On("WithinTx", ctx).Return()
 	suite.mockSingerRepository.On("Add", ctx, singer).Return(nil)
+	suite.mockSingerRepository.On("Get", ctx, singer.ID).Return(singer, nil)
 
 	err := suite.singerService.PostSingerService(ctx, singer)
 	suite.Assert().Nil(err)
@@ -107,10 +202,316 @@ func (suite *SingerServiceSuite) TestSingerServicePostSingerService() {
 func (suite *SingerServiceSuite) TestSingerServiceDeleteSingerService() {
 	ctx := context.Background()
 
-	id := model.SingerID(1)
-	suite.mockSingerRepository.On("Delete", ctx, id).Return(nil)
+	id := model.SingerID(44)
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Get", ctx, id).Return(&model.Singer{ID: id, Name: "Test Singer", Version: 2}, nil)
+	suite.mockSingerRepository.On("Delete", ctx, id, int64(2)).Return(nil)
 
-	err := suite.singerService.DeleteSingerService(ctx, id)
+	err := suite.singerService.DeleteSingerService(ctx, id, 2)
 	suite.Assert().Nil(err)
 	suite.mockSingerRepository.AssertExpectations(suite.T())
 }
+
+func (suite *SingerServiceSuite) TestSingerServiceUpdateSingerService() {
+	ctx := context.Background()
+
+	singer := &model.Singer{ID: model.SingerID(45), Name: "Updated Singer", Version: 1}
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Get", ctx, singer.ID).Return(&model.Singer{ID: singer.ID, Name: "Test Singer", Version: 1}, nil)
+	suite.mockSingerRepository.On("Update", ctx, singer).Return(nil)
+
+	err := suite.singerService.UpdateSingerService(ctx, singer)
+	suite.Assert().Nil(err)
+	suite.mockSingerRepository.AssertExpectations(suite.T())
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceDeleteSingerService_RecordsAudit() {
+	principal := &model.Principal{Subject: "api_key:ci", Role: model.RoleEditor, Method: model.AuthMethodAPIKey}
+	ctx := auth.NewContext(logging.WithRequestID(context.Background(), "req-50"), principal)
+	id := model.SingerID(50)
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Get", ctx, id).Return(&model.Singer{ID: id, Name: "Audited", Version: 3}, nil)
+	suite.mockSingerRepository.On("Delete", ctx, id, int64(3)).Return(nil)
+
+	err := suite.singerService.DeleteSingerService(ctx, id, 0)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(suite.auditWriter.events, 1)
+	event := suite.auditWriter.events[0]
+	suite.Equal("api_key:ci", event.Actor)
+	suite.Equal(model.AuditDelete, event.Action)
+	suite.Equal(model.AuditEntitySinger, event.EntityType)
+	suite.Equal(int(id), event.EntityID)
+	suite.Equal("req-50", event.RequestID)
+	var before model.Singer
+	suite.Require().NoError(json.Unmarshal(event.Before, &before))
+	suite.Equal("Audited", before.Name)
+	suite.Nil(event.After)
+	suite.mockTransactor.AssertCalled(suite.T(), "WithinTx", ctx)
+}
+
+func (suite *SingerServiceSuite) TestSingerServicePostSingerService_AnonymousActor() {
+	ctx := context.Background()
+	singer := &model.Singer{Name: "Anonymous Singer"}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Add", ctx, singer).
+		Run(func(args mock.Arguments) { singer.ID = model.SingerID(51) }).
+		Return(nil)
+	suite.mockSingerRepository.On("Get", ctx, model.SingerID(51)).Return(&model.Singer{ID: 51, Name: "Anonymous Singer", Version: 1}, nil)
+
+	err := suite.singerService.PostSingerService(ctx, singer)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(suite.auditWriter.events, 1)
+	event := suite.auditWriter.events[0]
+	suite.Equal(model.AuditActorAnonymous, event.Actor)
+	suite.Equal(model.AuditCreate, event.Action)
+	suite.Equal(51, event.EntityID)
+	suite.Nil(event.Before)
+	suite.JSONEq(`{"id": 51, "name": "Anonymous Singer", "created_at": "0001-01-01T00:00:00Z", "deleted_at": null, "version": 1}`, string(event.After))
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceUpdateSingerService_AuditFails() {
+	ctx := context.Background()
+	singer := &model.Singer{ID: model.SingerID(52), Name: "Unaudited", Version: 1}
+	unavailable := errors.New("audit log unavailable")
+	suite.auditWriter.err = unavailable
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Get", ctx, singer.ID).Return(&model.Singer{ID: singer.ID, Name: "Audited", Version: 1}, nil)
+	suite.mockSingerRepository.On("Update", ctx, singer).Return(nil)
+
+	err := suite.singerService.UpdateSingerService(ctx, singer)
+
+	// the transaction is rolled back, so the update is not kept either
+	suite.ErrorIs(err, unavailable)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceUpdateSingerService_NoVersion() {
+	ctx := context.Background()
+
+	singer := &model.Singer{ID: model.SingerID(40), Name: "Unconditional"}
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Get", ctx, singer.ID).Return(&model.Singer{ID: singer.ID, Name: "Before", Version: 6}, nil)
+	suite.mockSingerRepository.On("Update", ctx, singer).Return(nil)
+
+	err := suite.singerService.UpdateSingerService(ctx, singer)
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(int64(6), singer.Version)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceUpdateSingerService_InvalidParam() {
+	ctx := context.Background()
+
+	singer := &model.Singer{ID: model.SingerID(1), Name: ""}
+
+	err := suite.singerService.UpdateSingerService(ctx, singer)
+	suite.Assert().ErrorIs(err, model.ErrInvalidParam)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "Update", ctx, singer)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceGetSingerAlbumsService() {
+	ctx := context.Background()
+	singerID := model.SingerID(2)
+
+	albums := []*model.SingerAlbum{
+		{
+			Album: &model.Album{ID: model.AlbumID(2), Title: "Alice's 2nd Album", SingerID: model.SingerID(1)},
+			Roles: []model.CreditRole{model.CreditRoleFeatured},
+		},
+		{
+			Album: &model.Album{ID: model.AlbumID(3), Title: "Bella's 1st Album", SingerID: singerID},
+			Roles: []model.CreditRole{model.CreditRolePrimary},
+		},
+	}
+	suite.mockSingerRepository.On("Get", ctx, singerID).Return(&model.Singer{ID: singerID, Name: "Bella"}, nil)
+	suite.mockAlbumRepository.On("GetBySinger", ctx, singerID).Return(albums, nil)
+
+	result, err := suite.singerService.GetSingerAlbumsService(ctx, singerID)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(albums, result)
+	suite.mockAlbumRepository.AssertExpectations(suite.T())
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceGetSingerAlbumsService_NotFound() {
+	ctx := context.Background()
+	singerID := model.SingerID(99)
+
+	suite.mockSingerRepository.On("Get", ctx, singerID).Return(nil, repository.ErrorSingerNotFound)
+
+	_, err := suite.singerService.GetSingerAlbumsService(ctx, singerID)
+
+	suite.Assert().ErrorIs(err, model.ErrNotFound)
+	suite.mockAlbumRepository.AssertNotCalled(suite.T(), "GetBySinger", ctx, singerID)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceGetSingerDiscographyService() {
+	ctx := context.Background()
+	singerID := model.SingerID(3)
+	singer := &model.Singer{ID: singerID, Name: "Chris"}
+	addedAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	albums := []*model.SingerAlbum{
+		{
+			Album: &model.Album{ID: model.AlbumID(2), Title: "Alice's 2nd Album", CreatedAt: addedAt},
+			Roles: []model.CreditRole{model.CreditRoleProducer},
+		},
+	}
+
+	suite.mockSingerRepository.On("Get", ctx, singerID).Return(singer, nil)
+	suite.mockAlbumRepository.On("GetBySinger", ctx, singerID).Return(albums, nil)
+
+	resultSinger, discography, err := suite.singerService.GetSingerDiscographyService(ctx, singerID)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(singer, resultSinger)
+	suite.Assert().Equal(1, discography.AlbumCount)
+	suite.Assert().Equal(addedAt, *discography.FirstAddedAt)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceRestoreSingerService() {
+	ctx := context.Background()
+	singer := &model.Singer{ID: model.SingerID(4), Name: "Daisy"}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Restore", ctx, singer.ID).Return(nil)
+	suite.mockSingerRepository.On("Get", ctx, singer.ID).Return(singer, nil)
+
+	result, err := suite.singerService.RestoreSingerService(ctx, singer.ID)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(singer, result)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceRestoreSingerService_NotInTrash() {
+	ctx := context.Background()
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("Restore", ctx, model.SingerID(77)).Return(repository.ErrorSingerNotInTrash)
+
+	_, err := suite.singerService.RestoreSingerService(ctx, model.SingerID(77))
+
+	suite.Assert().ErrorIs(err, model.ErrNotFound)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceDeleteSingerCascadeService() {
+	ctx := context.Background()
+	id := model.SingerID(30)
+	albumIDs := []model.AlbumID{31, 32}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return(nil)
+	suite.mockAlbumRepository.On("GetIDsBySinger", ctx, id).Return(albumIDs, nil)
+	suite.mockAlbumRepository.On("Get", ctx, model.AlbumID(31)).Return(&model.Album{ID: 31, Version: 1}, nil)
+	suite.mockAlbumRepository.On("Get", ctx, model.AlbumID(32)).Return(&model.Album{ID: 32, Version: 4}, nil)
+	suite.mockAlbumRepository.On("Delete", ctx, model.AlbumID(31), int64(1)).Return(nil)
+	suite.mockAlbumRepository.On("Delete", ctx, model.AlbumID(32), int64(4)).Return(nil)
+	suite.mockSingerRepository.On("Get", ctx, id).Return(&model.Singer{ID: id, Name: "Cascade", Version: 2}, nil)
+	suite.mockSingerRepository.On("Delete", ctx, id, int64(2)).Return(nil)
+
+	deletion, err := suite.singerService.DeleteSingerCascadeService(ctx, id, 2, false)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(&model.SingerDeletion{SingerID: id, AlbumIDs: albumIDs}, deletion)
+	suite.mockAlbumRepository.AssertCalled(suite.T(), "Delete", ctx, model.AlbumID(32), int64(4))
+	suite.mockSingerRepository.AssertCalled(suite.T(), "Delete", ctx, id, int64(2))
+	suite.Require().Len(suite.auditWriter.events, 3)
+	suite.Equal(model.AuditEntityAlbum, suite.auditWriter.events[0].EntityType)
+	suite.Equal(31, suite.auditWriter.events[0].EntityID)
+	suite.Equal(32, suite.auditWriter.events[1].EntityID)
+	suite.Equal(model.AuditEntitySinger, suite.auditWriter.events[2].EntityType)
+	suite.Equal(int(id), suite.auditWriter.events[2].EntityID)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceDeleteSingerCascadeService_AlbumFails() {
+	ctx := context.Background()
+	id := model.SingerID(33)
+
+	suite.mockTransactor.On("WithinTx", ctx).Return(nil)
+	suite.mockAlbumRepository.On("GetIDsBySinger", ctx, id).Return([]model.AlbumID{34}, nil)
+	suite.mockAlbumRepository.On("Get", ctx, model.AlbumID(34)).Return(nil, repository.ErrorAlbumNotFound)
+
+	_, err := suite.singerService.DeleteSingerCascadeService(ctx, id, 1, false)
+
+	suite.Assert().ErrorIs(err, model.ErrNotFound)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "Delete", ctx, id, int64(1))
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceDeleteSingerCascadeService_DryRun() {
+	ctx := context.Background()
+	id := model.SingerID(35)
+	albumIDs := []model.AlbumID{36}
+
+	suite.mockSingerRepository.On("Get", ctx, id).Return(&model.Singer{ID: id, Name: "Dry"}, nil)
+	suite.mockAlbumRepository.On("GetIDsBySinger", ctx, id).Return(albumIDs, nil)
+
+	deletion, err := suite.singerService.DeleteSingerCascadeService(ctx, id, 0, true)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(&model.SingerDeletion{SingerID: id, AlbumIDs: albumIDs, DryRun: true}, deletion)
+	suite.mockAlbumRepository.AssertNotCalled(suite.T(), "Delete", ctx, model.AlbumID(36), mock.Anything)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "Delete", ctx, id, mock.Anything)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceDeleteSingerCascadeService_DryRunNotFound() {
+	ctx := context.Background()
+	id := model.SingerID(37)
+
+	suite.mockSingerRepository.On("Get", ctx, id).Return(nil, repository.ErrorSingerNotFound)
+
+	_, err := suite.singerService.DeleteSingerCascadeService(ctx, id, 0, true)
+
+	suite.Assert().ErrorIs(err, model.ErrNotFound)
+	suite.mockAlbumRepository.AssertNotCalled(suite.T(), "GetIDsBySinger", ctx, id)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceBatchSingerService_BestEffortUnavailable() {
+	ctx := context.Background()
+	first := &model.Singer{Name: "Batch Singer One"}
+	second := &model.Singer{Name: "Batch Singer Two"}
+	unavailable := model.NewError(model.KindUnavailable, "database unavailable", nil)
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockSingerRepository.On("AddBatch", ctx, []*model.Singer{first, second}).Return(unavailable)
+
+	results, err := suite.singerService.BatchSingerService(ctx, model.BatchBestEffort, []model.BatchOperation[*model.Singer]{
+		{Action: model.BatchCreate, Item: first},
+		{Action: model.BatchCreate, Item: second},
+	})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 2)
+	suite.ErrorIs(results[0].Err, unavailable)
+	suite.ErrorIs(results[1].Err, unavailable)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "Add", ctx, first)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceExportSingersService() {
+	ctx := context.Background()
+	q := &model.SingerQuery{Sort: model.SingerSortName, NamePrefix: "Ex"}
+	singers := []*model.Singer{{ID: model.SingerID(40), Name: "Export One"}, {ID: model.SingerID(41), Name: "Export Two"}}
+
+	suite.mockTransactor.On("WithinReadOnlyTx", ctx).Return()
+	suite.mockSingerRepository.On("Export", ctx, q).Return(singers, nil)
+
+	var exported []*model.Singer
+	err := suite.singerService.ExportSingersService(ctx, q, func(singer *model.Singer) error {
+		exported = append(exported, singer)
+		return nil
+	})
+
+	suite.NoError(err)
+	suite.Equal(singers, exported)
+	suite.mockTransactor.AssertCalled(suite.T(), "WithinReadOnlyTx", ctx)
+}
+
+func (suite *SingerServiceSuite) TestSingerServiceExportSingersService_InvalidSort() {
+	ctx := context.Background()
+	q := &model.SingerQuery{Sort: "popularity"}
+
+	err := suite.singerService.ExportSingersService(ctx, q, func(*model.Singer) error { return nil })
+
+	suite.ErrorIs(err, model.ErrInvalidParam)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "Export", ctx, q)
+}
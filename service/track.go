@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+type TrackService interface {
+	GetTrackListService(ctx context.Context, albumID model.AlbumID) ([]*model.Track, error)
+	GetTrackService(ctx context.Context, trackID model.TrackID) (*model.Track, error)
+	PostTrackService(ctx context.Context, track *model.Track) error
+	UpdateTrackService(ctx context.Context, track *model.Track) error
+	DeleteTrackService(ctx context.Context, trackID model.TrackID) error
+	ReorderTracksService(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) ([]*model.Track, error)
+}
+
+type trackService struct {
+	trackRepository repository.TrackRepository
+	albumRepository repository.AlbumRepository
+	transactor      repository.Transactor
+}
+
+var _ TrackService = (*trackService)(nil)
+
+func NewTrackService(
+	trackRepository repository.TrackRepository,
+	albumRepository repository.AlbumRepository,
+	transactor repository.Transactor,
+) TrackService {
+	return &trackService{trackRepository: trackRepository, albumRepository: albumRepository, transactor: transactor}
+}
+
+func (s *trackService) GetTrackListService(ctx context.Context, albumID model.AlbumID) ([]*model.Track, error) {
+	// An album without tracks and a missing album must not look the same.
+	if _, err := s.albumRepository.Get(ctx, albumID); err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.trackRepository.GetByAlbum(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (s *trackService) GetTrackService(ctx context.Context, trackID model.TrackID) (*model.Track, error) {
+	track, err := s.trackRepository.Get(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+func (s *trackService) PostTrackService(ctx context.Context, track *model.Track) error {
+	if err := track.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.trackRepository.Add(ctx, track); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *trackService) UpdateTrackService(ctx context.Context, track *model.Track) error {
+	if err := track.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.trackRepository.Update(ctx, track); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *trackService) DeleteTrackService(ctx context.Context, trackID model.TrackID) error {
+	if err := s.trackRepository.Delete(ctx, trackID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReorderTracksService requires the positions to cover exactly the album's tracks, so a
+// reorder never leaves a track stranded at its negated placeholder number. The album is
+// locked before its tracks are read, so none can be added between the check and the write.
+func (s *trackService) ReorderTracksService(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) ([]*model.Track, error) {
+	var tracks []*model.Track
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.trackRepository.LockAlbum(ctx, albumID); err != nil {
+			return err
+		}
+		current, err := s.trackRepository.GetByAlbum(ctx, albumID)
+		if err != nil {
+			return err
+		}
+		if err = validateReorder(albumID, current, positions); err != nil {
+			return err
+		}
+		if err = s.trackRepository.Reorder(ctx, albumID, positions); err != nil {
+			return err
+		}
+		tracks, err = s.trackRepository.GetByAlbum(ctx, albumID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func validateReorder(albumID model.AlbumID, current []*model.Track, positions []model.TrackPosition) error {
+	v := &model.ValidationError{}
+	onAlbum := make(map[model.TrackID]bool, len(current))
+	for _, t := range current {
+		onAlbum[t.ID] = true
+	}
+	reordered := make([]*model.Track, 0, len(positions))
+	listed := make(map[model.TrackID]bool, len(positions))
+	for _, p := range positions {
+		switch {
+		case !onAlbum[p.TrackID]:
+			v.Add("tracks", model.CodeInvalid, fmt.Sprintf("track %d is not on album %d", p.TrackID, albumID))
+		case listed[p.TrackID]:
+			v.Add("tracks", model.CodeDuplicate, fmt.Sprintf("track %d is listed more than once", p.TrackID))
+		}
+		listed[p.TrackID] = true
+		reordered = append(reordered, &model.Track{ID: p.TrackID, DiscNumber: p.DiscNumber, TrackNumber: p.TrackNumber})
+		if p.DiscNumber < 1 || p.TrackNumber < 1 {
+			v.Add("tracks", model.CodeOutOfRange, fmt.Sprintf("track %d must have disc_number and track_number of at least 1", p.TrackID))
+		}
+	}
+	for _, t := range current {
+		if !listed[t.ID] {
+			v.Add("tracks", model.CodeRequired, fmt.Sprintf("track %d is missing from the new order", t.ID))
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	return model.ValidateTrackPositions(reordered)
+}
@@ -0,0 +1,227 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockTrackRepository struct {
+	mock.Mock
+}
+
+func NewMockTrackRepository() *MockTrackRepository {
+	return &MockTrackRepository{}
+}
+
+func (m *MockTrackRepository) GetByAlbum(ctx context.Context, albumID model.AlbumID) ([]*model.Track, error) {
+	args := m.Called(ctx, albumID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) Get(ctx context.Context, id model.TrackID) (*model.Track, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) Add(ctx context.Context, track *model.Track) error {
+	args := m.Called(ctx, track)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackRepository) Update(ctx context.Context, track *model.Track) error {
+	args := m.Called(ctx, track)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackRepository) Delete(ctx context.Context, id model.TrackID) error {
+	args := m.Called(ctx, id)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackRepository) LockAlbum(ctx context.Context, albumID model.AlbumID) error {
+	args := m.Called(ctx, albumID)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *MockTrackRepository) Reorder(ctx context.Context, albumID model.AlbumID, positions []model.TrackPosition) error {
+	args := m.Called(ctx, albumID, positions)
+	if err, ok := args.Get(0).(error); ok {
+		return err
+	}
+	return nil
+}
+
+type TrackServiceSuite struct {
+	suite.Suite
+	trackService        service.TrackService
+	mockTrackRepository *MockTrackRepository
+	mockAlbumRepository *MockAlbumRepository
+	mockTransactor      *MockTransactor
+}
+
+func TestTrackServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TrackServiceSuite))
+}
+
+func (suite *TrackServiceSuite) SetupTest() {
+	suite.mockTrackRepository = NewMockTrackRepository()
+	suite.mockAlbumRepository = NewMockAlbumRepository()
+	suite.mockTransactor = NewMockTransactor()
+	suite.trackService = service.NewTrackService(suite.mockTrackRepository, suite.mockAlbumRepository, suite.mockTransactor)
+}
+
+func (suite *TrackServiceSuite) albumTracks() []*model.Track {
+	return []*model.Track{
+		{ID: model.TrackID(1), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "One", DurationSeconds: 180},
+		{ID: model.TrackID(2), AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 2, Title: "Two", DurationSeconds: 200},
+	}
+}
+
+func (suite *TrackServiceSuite) TestGetTrackListService() {
+	ctx := context.Background()
+	tracks := suite.albumTracks()
+
+	suite.mockAlbumRepository.On("Get", ctx, model.AlbumID(1)).Return(&model.Album{ID: model.AlbumID(1)}, nil)
+	suite.mockTrackRepository.On("GetByAlbum", ctx, model.AlbumID(1)).Return(tracks, nil)
+
+	result, err := suite.trackService.GetTrackListService(ctx, model.AlbumID(1))
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(tracks, result)
+	suite.mockTrackRepository.AssertExpectations(suite.T())
+}
+
+func (suite *TrackServiceSuite) TestGetTrackListService_AlbumNotFound() {
+	ctx := context.Background()
+
+	suite.mockAlbumRepository.On("Get", ctx, model.AlbumID(9)).Return(nil, repository.ErrorAlbumNotFound)
+
+	_, err := suite.trackService.GetTrackListService(ctx, model.AlbumID(9))
+
+	suite.Assert().ErrorIs(err, model.ErrNotFound)
+	suite.mockTrackRepository.AssertNotCalled(suite.T(), "GetByAlbum", ctx, model.AlbumID(9))
+}
+
+func (suite *TrackServiceSuite) TestPostTrackService_InvalidISRC() {
+	ctx := context.Background()
+	track := &model.Track{AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 3, Title: "Three", DurationSeconds: 90, ISRC: "JP-1"}
+
+	err := suite.trackService.PostTrackService(ctx, track)
+
+	suite.Assert().ErrorIs(err, model.ErrInvalidParam)
+	suite.mockTrackRepository.AssertNotCalled(suite.T(), "Add", ctx, track)
+}
+
+func (suite *TrackServiceSuite) TestPostTrackService_PositionTaken() {
+	ctx := context.Background()
+	track := &model.Track{AlbumID: model.AlbumID(1), DiscNumber: 1, TrackNumber: 1, Title: "Again", DurationSeconds: 90}
+
+	suite.mockTrackRepository.On("Add", ctx, track).Return(repository.ErrorTrackPositionTaken)
+
+	err := suite.trackService.PostTrackService(ctx, track)
+
+	suite.Assert().Equal(model.KindConflict, model.KindOf(err))
+}
+
+func (suite *TrackServiceSuite) TestReorderTracksService() {
+	ctx := context.Background()
+	positions := []model.TrackPosition{
+		{TrackID: model.TrackID(1), DiscNumber: 1, TrackNumber: 2},
+		{TrackID: model.TrackID(2), DiscNumber: 1, TrackNumber: 1},
+	}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockTrackRepository.On("LockAlbum", ctx, model.AlbumID(1)).Return(nil)
+	suite.mockTrackRepository.On("GetByAlbum", ctx, model.AlbumID(1)).Return(suite.albumTracks(), nil)
+	suite.mockTrackRepository.On("Reorder", ctx, model.AlbumID(1), positions).Return(nil)
+
+	_, err := suite.trackService.ReorderTracksService(ctx, model.AlbumID(1), positions)
+
+	suite.Assert().Nil(err)
+	suite.mockTrackRepository.AssertExpectations(suite.T())
+	suite.mockTransactor.AssertExpectations(suite.T())
+}
+
+func (suite *TrackServiceSuite) TestReorderTracksService_AlbumNotFound() {
+	ctx := context.Background()
+	positions := []model.TrackPosition{{TrackID: model.TrackID(1), DiscNumber: 1, TrackNumber: 1}}
+
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockTrackRepository.On("LockAlbum", ctx, model.AlbumID(9)).Return(repository.ErrorAlbumNotFound)
+
+	_, err := suite.trackService.ReorderTracksService(ctx, model.AlbumID(9), positions)
+
+	suite.Assert().ErrorIs(err, model.ErrNotFound)
+	suite.mockTrackRepository.AssertNotCalled(suite.T(), "GetByAlbum", ctx, model.AlbumID(9))
+	suite.mockTrackRepository.AssertNumberOfCalls(suite.T(), "Reorder", 0)
+}
+
+func (suite *TrackServiceSuite) TestReorderTracksService_Invalid() {
+	ctx := context.Background()
+	suite.mockTransactor.On("WithinTx", ctx).Return()
+	suite.mockTrackRepository.On("LockAlbum", ctx, model.AlbumID(1)).Return(nil)
+	suite.mockTrackRepository.On("GetByAlbum", ctx, model.AlbumID(1)).Return(suite.albumTracks(), nil)
+
+	tests := []struct {
+		name      string
+		positions []model.TrackPosition
+		code      string
+	}{
+		{
+			name:      "missing track",
+			positions: []model.TrackPosition{{TrackID: 1, DiscNumber: 1, TrackNumber: 1}},
+			code:      model.CodeRequired,
+		},
+		{
+			name: "foreign track",
+			positions: []model.TrackPosition{
+				{TrackID: 1, DiscNumber: 1, TrackNumber: 1},
+				{TrackID: 2, DiscNumber: 1, TrackNumber: 2},
+				{TrackID: 7, DiscNumber: 1, TrackNumber: 3},
+			},
+			code: model.CodeInvalid,
+		},
+		{
+			name: "duplicate position",
+			positions: []model.TrackPosition{
+				{TrackID: 1, DiscNumber: 1, TrackNumber: 1},
+				{TrackID: 2, DiscNumber: 1, TrackNumber: 1},
+			},
+			code: model.CodeDuplicate,
+		},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			_, err := suite.trackService.ReorderTracksService(ctx, model.AlbumID(1), tt.positions)
+
+			var verr *model.ValidationError
+			suite.Require().ErrorAs(err, &verr)
+			suite.Assert().Equal(tt.code, verr.Fields[0].Code)
+		})
+	}
+	suite.mockTrackRepository.AssertNumberOfCalls(suite.T(), "Reorder", 0)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/logging"
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/repository"
+)
+
+type TrashService interface {
+	GetTrashService(ctx context.Context) (*model.Trash, error)
+	PurgeTrashService(ctx context.Context, deletedBefore time.Time) error
+}
+
+type trashService struct {
+	singerRepository repository.SingerRepository
+	albumRepository  repository.AlbumRepository
+}
+
+var _ TrashService = (*trashService)(nil)
+
+func NewTrashService(singerRepository repository.SingerRepository, albumRepository repository.AlbumRepository) TrashService {
+	return &trashService{singerRepository: singerRepository, albumRepository: albumRepository}
+}
+
+func (s *trashService) GetTrashService(ctx context.Context) (*model.Trash, error) {
+	singers, err := s.singerRepository.GetDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	albums, err := s.albumRepository.GetDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Trash{Singers: singers, Albums: albums}, nil
+}
+
+// PurgeTrashService hard-deletes everything trashed before deletedBefore. Albums go first so
+// that singers whose last albums are purged in this run can follow.
+func (s *trashService) PurgeTrashService(ctx context.Context, deletedBefore time.Time) error {
+	albums, err := s.albumRepository.Purge(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+
+	singers, err := s.singerRepository.Purge(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+
+	if albums > 0 || singers > 0 {
+		logging.FromContext(ctx).InfoContext(ctx, "purged trash", "albums", albums, "singers", singers, "deleted_before", deletedBefore)
+	}
+	return nil
+}
+
+// RunTrashPurger purges rows older than retention every interval until ctx is done.
+func RunTrashPurger(ctx context.Context, s TrashService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.PurgeTrashService(ctx, time.Now().Add(-retention)); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "failed to purge trash", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
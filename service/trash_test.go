@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashServiceSuite struct {
+	suite.Suite
+	trashService         service.TrashService
+	mockSingerRepository *MockSingerRepository
+	mockAlbumRepository  *MockAlbumRepository
+}
+
+func TestTrashServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashServiceSuite))
+}
+
+func (suite *TrashServiceSuite) SetupTest() {
+	suite.mockSingerRepository = NewMockSingerRepository()
+	suite.mockAlbumRepository = NewMockAlbumRepository()
+	suite.trashService = service.NewTrashService(suite.mockSingerRepository, suite.mockAlbumRepository)
+}
+
+func (suite *TrashServiceSuite) TestGetTrashService() {
+	ctx := context.Background()
+	deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	singers := []*model.Singer{{ID: model.SingerID(5), Name: "Ellen", DeletedAt: &deletedAt}}
+	albums := []*model.Album{{ID: model.AlbumID(3), Title: "Bella's 1st Album", DeletedAt: &deletedAt}}
+
+	suite.mockSingerRepository.On("GetDeleted", ctx).Return(singers, nil)
+	suite.mockAlbumRepository.On("GetDeleted", ctx).Return(albums, nil)
+
+	trash, err := suite.trashService.GetTrashService(ctx)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal(&model.Trash{Singers: singers, Albums: albums}, trash)
+}
+
+func (suite *TrashServiceSuite) TestPurgeTrashService() {
+	ctx := context.Background()
+	before := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+
+	var order []string
+	suite.mockAlbumRepository.On("Purge", ctx, before).Return(int64(2), nil).
+		Run(func(mock.Arguments) { order = append(order, "albums") })
+	suite.mockSingerRepository.On("Purge", ctx, before).Return(int64(1), nil).
+		Run(func(mock.Arguments) { order = append(order, "singers") })
+
+	err := suite.trashService.PurgeTrashService(ctx, before)
+
+	suite.Assert().Nil(err)
+	suite.Assert().Equal([]string{"albums", "singers"}, order)
+}
+
+func (suite *TrashServiceSuite) TestPurgeTrashService_AlbumsFail() {
+	ctx := context.Background()
+	before := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+	failure := errors.New("connection reset")
+
+	suite.mockAlbumRepository.On("Purge", ctx, before).Return(int64(0), failure)
+
+	err := suite.trashService.PurgeTrashService(ctx, before)
+
+	suite.Assert().ErrorIs(err, failure)
+	suite.mockSingerRepository.AssertNotCalled(suite.T(), "Purge", ctx, before)
+}
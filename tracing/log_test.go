@@ -0,0 +1,36 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogHandler(t *testing.T) {
+	_, tp := newRecorder()
+	var buf bytes.Buffer
+	logger := slog.New(tracing.NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	logger.InfoContext(ctx, "inside")
+	span.End()
+	logger.InfoContext(context.Background(), "outside")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	var inside, outside map[string]any
+	assert.NoError(t, json.Unmarshal(lines[0], &inside))
+	assert.NoError(t, json.Unmarshal(lines[1], &outside))
+
+	assert.Equal(t, span.SpanContext().TraceID().String(), inside["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), inside["span_id"])
+	assert.Equal(t, "test", inside["component"])
+	assert.NotContains(t, outside, "trace_id")
+}
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+)
+
+// Observer starts a span per call of an observed service or repository. It satisfies both
+// service.Observer and repository.Observer.
+type Observer struct {
+	tracer trace.Tracer
+	layer  string
+}
+
+// NewObserver names spans "<layer> <component>.<method>", e.g. "repository album.GetAll".
+func NewObserver(tp trace.TracerProvider, layer string) *Observer {
+	return &Observer{tracer: tp.Tracer(InstrumentationName), layer: layer}
+}
+
+func (o *Observer) StartCall(ctx context.Context, component, method string) (context.Context, func(err error)) {
+	ctx, span := o.tracer.Start(ctx, o.layer+" "+component+"."+method,
+		trace.WithAttributes(
+			attribute.String("code.namespace", o.layer+"."+component),
+			attribute.String("code.function", method),
+		),
+	)
+	return ctx, func(err error) {
+		recordError(span, err)
+		span.End()
+	}
+}
+
+// recordError marks span failed for server-side errors only; a not found or a validation
+// error is an expected outcome and is just noted as error.kind.
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	kind := model.KindOf(err)
+	span.SetAttributes(attribute.String("error.kind", kind.String()))
+	if kind == model.KindInternal || kind == model.KindUnavailable {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
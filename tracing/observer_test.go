@@ -0,0 +1,60 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pulse227/server-recruit-challenge-sample/model"
+	"github.com/pulse227/server-recruit-challenge-sample/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder() (*tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	return exporter, tracing.NewTracerProvider(sdktrace.NewSimpleSpanProcessor(exporter), "test", 1)
+}
+
+func TestObserver_NestsSpans(t *testing.T) {
+	exporter, tp := newRecorder()
+	service := tracing.NewObserver(tp, "service")
+	repository := tracing.NewObserver(tp, "repository")
+
+	ctx, endService := service.StartCall(context.Background(), "album", "GetAll")
+	_, endRepository := repository.StartCall(ctx, "album", "GetAll")
+	endRepository(nil)
+	endService(nil)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "repository album.GetAll", spans[0].Name)
+		assert.Equal(t, "service album.GetAll", spans[1].Name)
+		assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+		assert.Contains(t, spans[0].Attributes, attribute.String("code.namespace", "repository.album"))
+		assert.Contains(t, spans[0].Attributes, attribute.String("code.function", "GetAll"))
+		assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	}
+}
+
+func TestObserver_Errors(t *testing.T) {
+	exporter, tp := newRecorder()
+	observer := tracing.NewObserver(tp, "service")
+
+	_, end := observer.StartCall(context.Background(), "singer", "Get")
+	end(model.NewError(model.KindNotFound, "singer not found", nil))
+	_, end = observer.StartCall(context.Background(), "singer", "Get")
+	end(errors.New("connection reset"))
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 2) {
+		// an expected outcome is noted but does not fail the span
+		assert.Contains(t, spans[0].Attributes, attribute.String("error.kind", "not_found"))
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+		assert.Contains(t, spans[1].Attributes, attribute.String("error.kind", "internal"))
+		assert.Equal(t, codes.Error, spans[1].Status.Code)
+	}
+}
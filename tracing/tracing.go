@@ -0,0 +1,51 @@
+// Package tracing sets up OpenTelemetry spans for the HTTP, service, repository and SQL layers.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/pulse227/server-recruit-challenge-sample/config"
+)
+
+// InstrumentationName names the tracer of every span this server creates.
+const InstrumentationName = "github.com/pulse227/server-recruit-challenge-sample"
+
+// Propagator reads and writes W3C traceparent and tracestate headers.
+var Propagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+// Setup returns the provider selected by c and a shutdown func that flushes pending spans.
+// With the none exporter spans are not recorded at all.
+func Setup(ctx context.Context, c config.Tracing) (trace.TracerProvider, func(context.Context) error, error) {
+	if c.Exporter != config.TracingOTLP {
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.Endpoint)}
+	if c.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	tp := NewTracerProvider(sdktrace.NewBatchSpanProcessor(exporter), c.ServiceName, c.SampleRatio)
+	return tp, tp.Shutdown, nil
+}
+
+// NewTracerProvider records spans through processor; tests pass a simple processor around a
+// tracetest.InMemoryExporter.
+func NewTracerProvider(processor sdktrace.SpanProcessor, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+}